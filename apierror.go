@@ -0,0 +1,54 @@
+package twelvedata
+
+import (
+	"bytes"
+	"net/http"
+	"strconv"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+)
+
+// APIError is the error payload the API answers with in place of the data (e.g. for an unknown symbol). The
+// endpoints return it wrapped, so match it with errors.As.
+type APIError struct {
+	Code    int    // Error code, mostly like HTTP statuses (e.g. 400, 404, 429)
+	Message string // Description of the error
+}
+
+func (e *APIError) Error() string {
+	return "API error " + strconv.Itoa(e.Code) + ": " + e.Message
+}
+
+// apiErrorOf returns the error of an error payload, or nil for other bodies
+func apiErrorOf(body []byte) *APIError {
+	if !bytes.HasPrefix(bytes.TrimSpace(body), []byte("{")) {
+		return nil
+	}
+
+	var payload struct {
+		Status  string `json:"status"`
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	}
+
+	if jsoniter.ConfigDefault.Unmarshal(body, &payload) != nil || payload.Status != "error" {
+		return nil
+	}
+
+	return &APIError{Code: payload.Code, Message: payload.Message}
+}
+
+// responseError returns the error of a response not carrying data: the error of an error payload, or an error for a
+// status other than 200. It returns nil for other responses.
+func responseError(response *Response) error {
+	if apiErr := apiErrorOf(response.Body); apiErr != nil {
+		return apiErr
+	}
+
+	if response.StatusCode != http.StatusOK {
+		return errors.Errorf("unexpected status code %d", response.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,263 @@
+// Package arrowio exports time series to Apache Arrow IPC files and Parquet files and reads them back, so exports
+// can serve as a local cache format and be loaded directly by pandas, polars or DuckDB.
+//
+// Files have the stable columns twelvedata.ExportColumns: datetime is a millisecond timestamp carrying the exchange
+// timezone, and open, high, low, close and volume are float64. The TimeSeriesResponseMeta fields are stored as
+// schema (Arrow) or key-value (Parquet) metadata under their JSON names.
+//
+// This package lives apart from the twelvedata package so that only users who need it depend on Arrow.
+package arrowio
+
+import (
+	"context"
+	"io"
+	"iter"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/ipc"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/apache/arrow-go/v18/parquet"
+	"github.com/apache/arrow-go/v18/parquet/compress"
+	"github.com/apache/arrow-go/v18/parquet/file"
+	"github.com/apache/arrow-go/v18/parquet/pqarrow"
+	"github.com/jonnotjohn/twelvedata-go"
+	"github.com/pkg/errors"
+)
+
+// batchSize is the number of candles per record batch (Arrow) or row group chunk (Parquet)
+const batchSize = 64 * 1024
+
+// Schema returns the Arrow schema of an exported time series with the meta fields as metadata
+func Schema(meta twelvedata.TimeSeriesResponseMeta) *arrow.Schema {
+	metadata := arrow.NewMetadata(twelvedata.MetaFields(meta))
+
+	timezone := meta.ExchangeTimezone
+	if timezone == "" {
+		timezone = "UTC"
+	}
+
+	columns := twelvedata.ExportColumns
+	fields := []arrow.Field{{Name: columns[0], Type: &arrow.TimestampType{Unit: arrow.Millisecond, TimeZone: timezone}}}
+	for _, name := range columns[1:] {
+		fields = append(fields, arrow.Field{Name: name, Type: arrow.PrimitiveTypes.Float64, Nullable: true})
+	}
+
+	return arrow.NewSchema(fields, &metadata)
+}
+
+// writeRecords converts candles to record batches and passes each to write
+func writeRecords(schema *arrow.Schema, candles iter.Seq2[twelvedata.TimeSeriesCandle, error], write func(arrow.Record) error) error {
+	builder := array.NewRecordBuilder(memory.DefaultAllocator, schema)
+	defer builder.Release()
+
+	flush := func() error {
+		record := builder.NewRecord()
+		defer record.Release()
+
+		if record.NumRows() == 0 {
+			return nil
+		}
+		return write(record)
+	}
+
+	rows := 0
+	for candle, err := range candles {
+		if err != nil {
+			return errors.Wrap(err, "Error reading candles to export")
+		}
+
+		builder.Field(0).(*array.TimestampBuilder).Append(arrow.Timestamp(candle.DateTime.UnixMilli()))
+		for i, value := range []twelvedata.TDFloat{candle.Open, candle.High, candle.Low, candle.Close, candle.Volume} {
+			column := builder.Field(i + 1).(*array.Float64Builder)
+			if value.Valid {
+				column.Append(value.Float64)
+			} else {
+				column.AppendNull()
+			}
+		}
+
+		if rows++; rows%batchSize == 0 {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+
+	return flush()
+}
+
+// readRecords appends the candles of a record batch to resp, with datetimes in loc
+func readRecords(record arrow.Record, loc *time.Location, resp *twelvedata.TimeSeriesResponse) error {
+	if int(record.NumCols()) != len(twelvedata.ExportColumns) {
+		return errors.Errorf("expected %d columns, got %d", len(twelvedata.ExportColumns), record.NumCols())
+	}
+
+	datetimes, ok := record.Column(0).(*array.Timestamp)
+	if !ok {
+		return errors.Errorf("datetime column has unexpected type %s", record.Column(0).DataType())
+	}
+	unit := datetimes.DataType().(*arrow.TimestampType).Unit
+
+	values := make([]*array.Float64, len(twelvedata.ExportColumns)-1)
+	for i := range values {
+		if values[i], ok = record.Column(i + 1).(*array.Float64); !ok {
+			return errors.Errorf("%s column has unexpected type %s", twelvedata.ExportColumns[i+1], record.Column(i+1).DataType())
+		}
+	}
+
+	value := func(column, row int) twelvedata.TDFloat {
+		if values[column].IsNull(row) {
+			return twelvedata.TDFloat{}
+		}
+		return twelvedata.NewTDFloat(values[column].Value(row))
+	}
+
+	for row := 0; row < int(record.NumRows()); row++ {
+		resp.Candles = append(resp.Candles, twelvedata.TimeSeriesCandle{
+			DateTime: twelvedata.TDZonedTime{Time: datetimes.Value(row).ToTime(unit).In(loc)},
+			Open:     value(0, row),
+			High:     value(1, row),
+			Low:      value(2, row),
+			Close:    value(3, row),
+			Volume:   value(4, row),
+		})
+	}
+
+	return nil
+}
+
+// responseFromSchema creates an empty response with the meta fields stored in the schema metadata
+func responseFromSchema(schema *arrow.Schema) (*twelvedata.TimeSeriesResponse, *time.Location, error) {
+	metadata := schema.Metadata()
+
+	values := make(map[string]string, metadata.Len())
+	for i, key := range metadata.Keys() {
+		values[key] = metadata.Values()[i]
+	}
+
+	resp := &twelvedata.TimeSeriesResponse{Meta: twelvedata.MetaFromMap(values)}
+	loc, err := resp.Meta.Location()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return resp, loc, nil
+}
+
+// WriteArrow writes the response as an Arrow IPC file, see WriteCandlesArrow
+func WriteArrow(w io.Writer, resp *twelvedata.TimeSeriesResponse) error {
+	return WriteCandlesArrow(w, resp.Meta, resp.Seq())
+}
+
+// WriteCandlesArrow writes candles as an Arrow IPC file (Feather v2)
+func WriteCandlesArrow(w io.Writer, meta twelvedata.TimeSeriesResponseMeta, candles iter.Seq2[twelvedata.TimeSeriesCandle, error]) error {
+	schema := Schema(meta)
+
+	writer, err := ipc.NewFileWriter(w, ipc.WithSchema(schema))
+	if err != nil {
+		return errors.Wrap(err, "Error creating Arrow writer")
+	}
+
+	err = writeRecords(schema, candles, writer.Write)
+	if err != nil {
+		_ = writer.Close()
+		return errors.Wrap(err, "Error writing Arrow file")
+	}
+
+	return errors.Wrap(writer.Close(), "Error writing Arrow file")
+}
+
+// ReadArrow reads an Arrow IPC file written by WriteCandlesArrow back into a response
+func ReadArrow(r ipc.ReadAtSeeker) (*twelvedata.TimeSeriesResponse, error) {
+	reader, err := ipc.NewFileReader(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error opening Arrow file")
+	}
+	defer reader.Close()
+
+	resp, loc, err := responseFromSchema(reader.Schema())
+	if err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < reader.NumRecords(); i++ {
+		record, err := reader.Record(i)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Error reading Arrow record batch %d", i)
+		}
+
+		if err := readRecords(record, loc, resp); err != nil {
+			return nil, errors.Wrapf(err, "Error reading Arrow record batch %d", i)
+		}
+	}
+
+	return resp, nil
+}
+
+// WriteParquet writes the response as a Parquet file, see WriteCandlesParquet
+func WriteParquet(w io.Writer, resp *twelvedata.TimeSeriesResponse) error {
+	return WriteCandlesParquet(w, resp.Meta, resp.Seq())
+}
+
+// WriteCandlesParquet writes candles as a Snappy compressed Parquet file
+func WriteCandlesParquet(w io.Writer, meta twelvedata.TimeSeriesResponseMeta, candles iter.Seq2[twelvedata.TimeSeriesCandle, error]) error {
+	schema := Schema(meta)
+
+	props := parquet.NewWriterProperties(parquet.WithCompression(compress.Codecs.Snappy))
+	arrowProps := pqarrow.NewArrowWriterProperties(pqarrow.WithStoreSchema())
+
+	writer, err := pqarrow.NewFileWriter(schema, w, props, arrowProps)
+	if err != nil {
+		return errors.Wrap(err, "Error creating Parquet writer")
+	}
+
+	err = writeRecords(schema, candles, writer.WriteBuffered)
+	if err != nil {
+		_ = writer.Close()
+		return errors.Wrap(err, "Error writing Parquet file")
+	}
+
+	return errors.Wrap(writer.Close(), "Error writing Parquet file")
+}
+
+// ReadParquet reads a Parquet file written by WriteCandlesParquet back into a response
+func ReadParquet(r parquet.ReaderAtSeeker) (*twelvedata.TimeSeriesResponse, error) {
+	parquetReader, err := file.NewParquetReader(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error opening Parquet file")
+	}
+	defer parquetReader.Close()
+
+	reader, err := pqarrow.NewFileReader(parquetReader, pqarrow.ArrowReadProperties{BatchSize: batchSize}, memory.DefaultAllocator)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error opening Parquet file")
+	}
+
+	table, err := reader.ReadTable(context.Background())
+	if err != nil {
+		return nil, errors.Wrap(err, "Error reading Parquet file")
+	}
+	defer table.Release()
+
+	// The meta fields are stored as Parquet key-value metadata, which isn't part of the table schema
+	keyValues := parquetReader.MetaData().KeyValueMetadata()
+	metadata := arrow.NewMetadata(keyValues.Keys(), keyValues.Values())
+
+	resp, loc, err := responseFromSchema(arrow.NewSchema(table.Schema().Fields(), &metadata))
+	if err != nil {
+		return nil, err
+	}
+
+	tableReader := array.NewTableReader(table, batchSize)
+	defer tableReader.Release()
+
+	for tableReader.Next() {
+		if err := readRecords(tableReader.Record(), loc, resp); err != nil {
+			return nil, errors.Wrap(err, "Error reading Parquet file")
+		}
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,184 @@
+package arrowio_test
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/parquet/file"
+	"github.com/jonnotjohn/twelvedata-go"
+	"github.com/jonnotjohn/twelvedata-go/arrowio"
+)
+
+var metaKeys = []string{
+	"symbol", "interval", "currency", "currency_base", "currency_quote", "exchange_timezone", "exchange", "mic_code", "type",
+}
+
+// series is an intraday series across the start of daylight saving time in New York, with a candle without volume
+func series(t *testing.T) *twelvedata.TimeSeriesResponse {
+	t.Helper()
+
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	candle := func(datetime time.Time, price float64, volume twelvedata.TDFloat) twelvedata.TimeSeriesCandle {
+		return twelvedata.TimeSeriesCandle{
+			DateTime: twelvedata.TDZonedTime{Time: datetime},
+			Open:     twelvedata.NewTDFloat(price),
+			High:     twelvedata.NewTDFloat(price + 0.5),
+			Low:      twelvedata.NewTDFloat(price - 0.25),
+			Close:    twelvedata.NewTDFloat(price + 0.125),
+			Volume:   volume,
+		}
+	}
+
+	return &twelvedata.TimeSeriesResponse{
+		Meta: twelvedata.TimeSeriesResponseMeta{
+			Symbol:           "AAPL",
+			Interval:         "1h",
+			Currency:         "USD",
+			ExchangeTimezone: "America/New_York",
+			Exchange:         "NASDAQ",
+			MicCode:          "XNGS",
+			Type:             "Common Stock",
+		},
+		Candles: []twelvedata.TimeSeriesCandle{
+			candle(time.Date(2024, 3, 8, 15, 30, 0, 0, newYork), 170.73, twelvedata.NewTDFloat(4512200)),
+			candle(time.Date(2024, 3, 11, 9, 30, 0, 0, newYork), 172.94, twelvedata.TDFloat{}),
+			candle(time.Date(2024, 3, 11, 10, 30, 0, 0, newYork), 173.1, twelvedata.NewTDFloat(0)),
+		},
+	}
+}
+
+// checkRoundTrip compares a series read back from a file with the written one
+func checkRoundTrip(t *testing.T, got, want *twelvedata.TimeSeriesResponse) {
+	t.Helper()
+
+	if got.Meta != want.Meta {
+		t.Errorf("Meta = %+v, want %+v", got.Meta, want.Meta)
+	}
+
+	if len(got.Candles) != len(want.Candles) {
+		t.Fatalf("candles = %d, want %d", len(got.Candles), len(want.Candles))
+	}
+
+	for i, candle := range got.Candles {
+		if !candle.DateTime.Equal(want.Candles[i].DateTime.Time) {
+			t.Errorf("candle %d at %s, want %s", i, candle.DateTime, want.Candles[i].DateTime)
+		}
+
+		if zone := candle.DateTime.Location().String(); zone != "America/New_York" {
+			t.Errorf("candle %d location = %s, want America/New_York", i, zone)
+		}
+
+		candle.DateTime = want.Candles[i].DateTime
+		if !reflect.DeepEqual(candle, want.Candles[i]) {
+			t.Errorf("candle %d = %+v, want %+v", i, candle, want.Candles[i])
+		}
+	}
+}
+
+func TestSchema(t *testing.T) {
+	schema := arrowio.Schema(series(t).Meta)
+
+	metadata := schema.Metadata()
+	if !reflect.DeepEqual(metadata.Keys(), metaKeys) {
+		t.Errorf("metadata keys = %q, want %q", metadata.Keys(), metaKeys)
+	}
+
+	datetime := schema.Field(0).Type.(*arrow.TimestampType)
+	if datetime.Unit != arrow.Millisecond || datetime.TimeZone != "America/New_York" {
+		t.Errorf("datetime type = %s, want milliseconds in America/New_York", datetime)
+	}
+
+	if timezone := arrowio.Schema(twelvedata.TimeSeriesResponseMeta{}).Field(0).Type.(*arrow.TimestampType).TimeZone; timezone != "UTC" {
+		t.Errorf("datetime timezone without exchange timezone = %s, want UTC", timezone)
+	}
+}
+
+func TestArrowRoundTrip(t *testing.T) {
+	want := series(t)
+
+	var buf bytes.Buffer
+	if err := arrowio.WriteArrow(&buf, want); err != nil {
+		t.Fatal(err)
+	}
+
+	// The output is reproducible
+	var again bytes.Buffer
+	if err := arrowio.WriteArrow(&again, want); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf.Bytes(), again.Bytes()) {
+		t.Error("writing the same series twice gave different files")
+	}
+
+	got, err := arrowio.ReadArrow(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkRoundTrip(t, got, want)
+}
+
+func TestParquetRoundTrip(t *testing.T) {
+	want := series(t)
+
+	var buf bytes.Buffer
+	if err := arrowio.WriteParquet(&buf, want); err != nil {
+		t.Fatal(err)
+	}
+
+	var again bytes.Buffer
+	if err := arrowio.WriteParquet(&again, want); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf.Bytes(), again.Bytes()) {
+		t.Error("writing the same series twice gave different files")
+	}
+
+	got, err := arrowio.ReadParquet(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkRoundTrip(t, got, want)
+
+	// The meta fields are key-value metadata in a fixed order, before the stored Arrow schema
+	reader, err := file.NewParquetReader(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reader.Close()
+
+	keys := reader.MetaData().KeyValueMetadata().Keys()
+	if len(keys) < len(metaKeys) || !reflect.DeepEqual(keys[:len(metaKeys)], metaKeys) {
+		t.Errorf("key-value metadata keys = %q, want %q first", keys, metaKeys)
+	}
+}
+
+func TestEmptySeries(t *testing.T) {
+	want := &twelvedata.TimeSeriesResponse{Meta: series(t).Meta}
+
+	var arrowFile, parquetFile bytes.Buffer
+	if err := arrowio.WriteArrow(&arrowFile, want); err != nil {
+		t.Fatal(err)
+	}
+	if err := arrowio.WriteParquet(&parquetFile, want); err != nil {
+		t.Fatal(err)
+	}
+
+	fromArrow, err := arrowio.ReadArrow(bytes.NewReader(arrowFile.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkRoundTrip(t, fromArrow, want)
+
+	fromParquet, err := arrowio.ReadParquet(bytes.NewReader(parquetFile.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkRoundTrip(t, fromParquet, want)
+}
@@ -0,0 +1,341 @@
+package twelvedata
+
+import (
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+)
+
+// calendarSearchDays bounds how far NextOpen and NextClose look ahead
+const calendarSearchDays = 366
+
+// CalendarSession is a trading session as offsets from midnight in the exchange timezone. A close not after the open
+// is on the next day, for sessions running past midnight.
+type CalendarSession struct {
+	Name  string              `json:"name"`
+	Type  ExchangeSessionType `json:"type"`
+	Open  time.Duration       `json:"open"`
+	Close time.Duration       `json:"close"`
+}
+
+// Calendar answers when an exchange is open. It is built from exchange schedules with a regular trading week plus
+// per-date overrides for holidays and shortened days, and can be saved and loaded as JSON to work offline.
+// Calendar implements TradingSessions.
+type Calendar struct {
+	Name                 string                       `json:"name"`
+	Code                 string                       `json:"code"`
+	TimeZone             string                       `json:"time_zone"`
+	Weekdays             []time.Weekday               `json:"weekdays"`               // Days of the week with the regular sessions
+	Sessions             []CalendarSession            `json:"sessions"`               // Regular sessions of a trading day
+	Overrides            map[string][]CalendarSession `json:"overrides"`              // Sessions of specific dates ("2006-01-02"). An empty list is a holiday
+	IncludeExtendedHours bool                         `json:"include_extended_hours"` // Count pre and post market sessions as open
+
+	locationOnce sync.Once
+	location     *time.Location
+	locationErr  error
+}
+
+func calendarSessions(sessions []ExchangeSession) []CalendarSession {
+	calendarSessions := make([]CalendarSession, len(sessions))
+	for i, session := range sessions {
+		calendarSessions[i] = CalendarSession{
+			Name:  session.SessionName,
+			Type:  session.SessionType,
+			Open:  session.OpenTime.Duration,
+			Close: session.CloseTime.Duration,
+		}
+	}
+
+	return calendarSessions
+}
+
+// NewCalendar creates a calendar trading Monday to Friday with the sessions of the regular schedule
+func NewCalendar(schedule ExchangeSchedule) (*Calendar, error) {
+	cal := &Calendar{
+		Name:      schedule.Name,
+		Code:      schedule.Code,
+		TimeZone:  schedule.TimeZone,
+		Weekdays:  []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday},
+		Sessions:  calendarSessions(schedule.Sessions),
+		Overrides: make(map[string][]CalendarSession),
+	}
+
+	if _, err := cal.Location(); err != nil {
+		return nil, err
+	}
+
+	return cal, nil
+}
+
+// LoadCalendar reads a calendar saved with Calendar.Save
+func LoadCalendar(r io.Reader) (*Calendar, error) {
+	var cal Calendar
+	if err := jsoniter.NewDecoder(r).Decode(&cal); err != nil {
+		return nil, errors.Wrap(err, "Error decoding calendar")
+	}
+
+	if cal.Overrides == nil {
+		cal.Overrides = make(map[string][]CalendarSession)
+	}
+
+	if _, err := cal.Location(); err != nil {
+		return nil, err
+	}
+
+	return &cal, nil
+}
+
+// Save writes the calendar as JSON so it can be loaded offline with LoadCalendar
+func (c *Calendar) Save(w io.Writer) error {
+	if err := jsoniter.NewEncoder(w).Encode(c); err != nil {
+		return errors.Wrap(err, "Error encoding calendar")
+	}
+
+	return nil
+}
+
+// GetCalendar builds the calendar of an exchange from its regular schedule. The schedule of the calendar day of each of
+// the given dates in the exchange timezone is fetched as well (one call per date) and recorded as an override, which
+// picks up holidays and shortened days.
+func (c *APIClient) GetCalendar(req ExchangeScheduleRequest, dates ...time.Time) (*Calendar, error) {
+	req.Date = nil
+
+	regular, err := c.GetExchangeSchedule(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error fetching regular exchange schedule")
+	}
+
+	if len(regular.Data) == 0 {
+		return nil, errors.New("exchange schedule response has no exchange")
+	}
+
+	cal, err := NewCalendar(regular.Data[0])
+	if err != nil {
+		return nil, err
+	}
+
+	for _, date := range dates {
+		date = cal.in(date)
+		dateReq := req
+		dateReq.Date = &date
+
+		schedule, err := c.GetExchangeSchedule(dateReq)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Error fetching exchange schedule for %s", date.Format("2006-01-02"))
+		}
+
+		var sessions []ExchangeSession
+		if len(schedule.Data) > 0 {
+			sessions = schedule.Data[0].Sessions
+		}
+
+		cal.SetSessions(date, calendarSessions(sessions))
+	}
+
+	return cal, nil
+}
+
+// Location returns the exchange timezone
+func (c *Calendar) Location() (*time.Location, error) {
+	c.locationOnce.Do(func() {
+		c.location, c.locationErr = time.LoadLocation(c.TimeZone)
+		if c.locationErr != nil {
+			c.locationErr = errors.Wrap(c.locationErr, "failed to load exchange timezone")
+		}
+	})
+
+	return c.location, c.locationErr
+}
+
+// in converts t to the exchange timezone, falling back to t's own location when the timezone can't be loaded
+func (c *Calendar) in(t time.Time) time.Time {
+	if loc, err := c.Location(); err == nil {
+		return t.In(loc)
+	}
+	return t
+}
+
+// SetSessions overrides the sessions of the calendar day of date in the exchange timezone. No sessions marks the day
+// as a holiday.
+func (c *Calendar) SetSessions(date time.Time, sessions []CalendarSession) {
+	if c.Overrides == nil {
+		c.Overrides = make(map[string][]CalendarSession)
+	}
+
+	if sessions == nil {
+		sessions = []CalendarSession{}
+	}
+
+	c.Overrides[c.in(date).Format("2006-01-02")] = sessions
+}
+
+// AddHoliday marks the calendar day of date in the exchange timezone as closed
+func (c *Calendar) AddHoliday(date time.Time) {
+	c.SetSessions(date, nil)
+}
+
+// daySessions returns the open periods of the sessions opening on the calendar day of t (in the exchange timezone),
+// with contiguous sessions merged and sorted by open time
+func (c *Calendar) daySessions(t time.Time) [][2]time.Time {
+	sessions, overridden := c.Overrides[t.Format("2006-01-02")]
+	if !overridden {
+		if !c.isTradingWeekday(t.Weekday()) {
+			return nil
+		}
+		sessions = c.Sessions
+	}
+
+	var periods [][2]time.Time
+	for _, session := range sessions {
+		if session.Type != "" && session.Type != ExchangeSessionTypeCore && !c.IncludeExtendedHours {
+			continue
+		}
+		closeDay := t
+		if session.Close <= session.Open {
+			closeDay = t.AddDate(0, 0, 1)
+		}
+		periods = append(periods, [2]time.Time{atOffset(t, session.Open), atOffset(closeDay, session.Close)})
+	}
+
+	sort.Slice(periods, func(i, j int) bool {
+		return periods[i][0].Before(periods[j][0])
+	})
+
+	merged := periods[:0]
+	for _, period := range periods {
+		if n := len(merged); n > 0 && !period[0].After(merged[n-1][1]) {
+			if period[1].After(merged[n-1][1]) {
+				merged[n-1][1] = period[1]
+			}
+			continue
+		}
+		merged = append(merged, period)
+	}
+
+	return merged
+}
+
+func (c *Calendar) isTradingWeekday(weekday time.Weekday) bool {
+	for _, w := range c.Weekdays {
+		if w == weekday {
+			return true
+		}
+	}
+	return false
+}
+
+// IsTradingDay reports whether the exchange has any session on the calendar day of day
+func (c *Calendar) IsTradingDay(day time.Time) bool {
+	return len(c.daySessions(c.in(day))) > 0
+}
+
+// IsOpen reports whether the exchange is open at t
+func (c *Calendar) IsOpen(t time.Time) bool {
+	t = c.in(t)
+
+	// Sessions of the day before can run past midnight
+	for _, day := range []time.Time{t.AddDate(0, 0, -1), t} {
+		for _, period := range c.daySessions(day) {
+			if !t.Before(period[0]) && t.Before(period[1]) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// NextOpen returns the first session open strictly after t
+func (c *Calendar) NextOpen(t time.Time) (time.Time, error) {
+	t = c.in(t)
+	for day := atOffset(t, 0); day.Sub(t) < calendarSearchDays*24*time.Hour; day = day.AddDate(0, 0, 1) {
+		for _, period := range c.daySessions(day) {
+			if period[0].After(t) {
+				return period[0], nil
+			}
+		}
+	}
+
+	return time.Time{}, errors.Errorf("no session opens within %d days after %s", calendarSearchDays, t)
+}
+
+// NextClose returns the first session close strictly after t
+func (c *Calendar) NextClose(t time.Time) (time.Time, error) {
+	t = c.in(t)
+
+	// Start from the day before, whose sessions can close after midnight
+	for day := atOffset(t, 0).AddDate(0, 0, -1); day.Sub(t) < calendarSearchDays*24*time.Hour; day = day.AddDate(0, 0, 1) {
+		for _, period := range c.daySessions(day) {
+			if period[1].After(t) {
+				return period[1], nil
+			}
+		}
+	}
+
+	return time.Time{}, errors.Errorf("no session closes within %d days after %s", calendarSearchDays, t)
+}
+
+// TradingDays returns midnight (in the exchange timezone) of every trading day from the day of from through the day
+// of to, inclusive
+func (c *Calendar) TradingDays(from, to time.Time) []time.Time {
+	var days []time.Time
+	last := atOffset(c.in(to), 0)
+	for day := atOffset(c.in(from), 0); !day.After(last); day = day.AddDate(0, 0, 1) {
+		if len(c.daySessions(day)) > 0 {
+			days = append(days, day)
+		}
+	}
+
+	return days
+}
+
+// ExpectedBars returns the timestamps of the bars the API should return for interval within [from, to]. Intraday
+// bars start at each session open, daily bars at midnight of each trading day, and weekly (Monday) and monthly
+// bars at the start of each period with at least one trading day.
+func (c *Calendar) ExpectedBars(from, to time.Time, interval TimeSeriesInterval) ([]time.Time, error) {
+	from, to = c.in(from), c.in(to)
+
+	var bars []time.Time
+	inRange := func(t time.Time) bool {
+		return !t.Before(from) && !t.After(to)
+	}
+
+	switch interval {
+	case TimeSeriesInterval1Day:
+		for _, day := range c.TradingDays(from, to) {
+			if inRange(day) {
+				bars = append(bars, day)
+			}
+		}
+	case TimeSeriesInterval1Week, TimeSeriesInterval1Month:
+		// Start from the beginning of the period containing from, so its bar is included when it falls in range
+		for _, day := range c.TradingDays(from.AddDate(0, -1, 0), to) {
+			label, _, _ := bucketSpan(day, interval, ResampleOptions{})
+			if inRange(label) && (len(bars) == 0 || !bars[len(bars)-1].Equal(label)) {
+				bars = append(bars, label)
+			}
+		}
+	default:
+		d, ok := intervalDuration(interval)
+		if !ok {
+			return nil, errors.Errorf("unsupported interval %q", interval)
+		}
+
+		// Sessions of the day before from can run past midnight
+		for _, day := range c.TradingDays(from.AddDate(0, 0, -1), to) {
+			for _, period := range c.daySessions(day) {
+				for t := period[0]; t.Before(period[1]); t = t.Add(d) {
+					if inRange(t) {
+						bars = append(bars, t)
+					}
+				}
+			}
+		}
+	}
+
+	return bars, nil
+}
@@ -0,0 +1,296 @@
+package twelvedata_test
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jonnotjohn/twelvedata-go"
+	"github.com/jonnotjohn/twelvedata-go/tdtest"
+)
+
+// nasdaqCalendar builds the NASDAQ calendar from the bundled fixtures, with the half day of 2024-07-03 and the
+// holiday of 2024-07-04
+func nasdaqCalendar(t *testing.T) *twelvedata.Calendar {
+	t.Helper()
+
+	client, err := tdtest.NewReplayClient(tdtest.Fixtures(), tdtest.MatchExact)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	micCode := "XNGS"
+	cal, err := client.GetCalendar(twelvedata.ExchangeScheduleRequest{MicCode: &micCode}, at("2024-07-03 00:00"), at("2024-07-04 00:00"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return cal
+}
+
+// wallClocks formats times as wall clocks in New York
+func wallClocks(times []time.Time) []string {
+	lines := make([]string, len(times))
+	for i, t := range times {
+		lines[i] = t.In(newYork).Format("2006-01-02 15:04")
+	}
+
+	return lines
+}
+
+func TestCalendarIsOpen(t *testing.T) {
+	cal := nasdaqCalendar(t)
+
+	extended := nasdaqCalendar(t)
+	extended.IncludeExtendedHours = true
+
+	tests := []struct {
+		name     string
+		t        time.Time
+		want     bool
+		extended bool
+	}{
+		{name: "regular session", t: at("2024-07-02 10:00"), want: true, extended: true},
+		{name: "before open", t: at("2024-07-02 09:29"), want: false, extended: true},
+		{name: "at open", t: at("2024-07-02 09:30"), want: true, extended: true},
+		{name: "at close", t: at("2024-07-02 16:00"), want: false, extended: true},
+		{name: "after post market", t: at("2024-07-02 20:00"), want: false, extended: false},
+		{name: "half day", t: at("2024-07-03 12:59"), want: true, extended: true},
+		{name: "after half day close", t: at("2024-07-03 13:00"), want: false, extended: true},
+		{name: "after half day post market", t: at("2024-07-03 17:00"), want: false, extended: false},
+		{name: "holiday", t: at("2024-07-04 10:00"), want: false, extended: false},
+		{name: "weekend", t: at("2024-07-06 10:00"), want: false, extended: false},
+		{name: "UTC time", t: time.Date(2024, 7, 2, 14, 0, 0, 0, time.UTC), want: true, extended: true},
+		{name: "before open in standard time", t: time.Date(2024, 3, 8, 14, 0, 0, 0, time.UTC), want: false, extended: true},
+		{name: "after open in daylight saving time", t: time.Date(2024, 3, 11, 14, 0, 0, 0, time.UTC), want: true, extended: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cal.IsOpen(tt.t); got != tt.want {
+				t.Errorf("IsOpen(%s) = %v, want %v", tt.t, got, tt.want)
+			}
+
+			if got := extended.IsOpen(tt.t); got != tt.extended {
+				t.Errorf("IsOpen(%s) with extended hours = %v, want %v", tt.t, got, tt.extended)
+			}
+		})
+	}
+}
+
+func TestCalendarNextOpenAndClose(t *testing.T) {
+	cal := nasdaqCalendar(t)
+
+	tests := []struct {
+		name      string
+		t         time.Time
+		nextOpen  string
+		nextClose string
+	}{
+		{name: "during session", t: at("2024-07-02 10:00"), nextOpen: "2024-07-03 09:30", nextClose: "2024-07-02 16:00"},
+		{name: "before open", t: at("2024-07-02 08:00"), nextOpen: "2024-07-02 09:30", nextClose: "2024-07-02 16:00"},
+		{name: "at open", t: at("2024-07-02 09:30"), nextOpen: "2024-07-03 09:30", nextClose: "2024-07-02 16:00"},
+		{name: "half day", t: at("2024-07-03 10:00"), nextOpen: "2024-07-05 09:30", nextClose: "2024-07-03 13:00"},
+		{name: "over holiday", t: at("2024-07-03 13:00"), nextOpen: "2024-07-05 09:30", nextClose: "2024-07-05 16:00"},
+		{name: "over weekend and DST change", t: at("2024-03-08 16:00"), nextOpen: "2024-03-11 09:30", nextClose: "2024-03-11 16:00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nextOpen, err := cal.NextOpen(tt.t)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !nextOpen.Equal(at(tt.nextOpen)) {
+				t.Errorf("NextOpen(%s) = %s, want %s", tt.t, nextOpen, tt.nextOpen)
+			}
+
+			nextClose, err := cal.NextClose(tt.t)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !nextClose.Equal(at(tt.nextClose)) {
+				t.Errorf("NextClose(%s) = %s, want %s", tt.t, nextClose, tt.nextClose)
+			}
+		})
+	}
+
+	// 13:30 UTC is the open in daylight saving time, an hour earlier than in standard time
+	if nextOpen, _ := cal.NextOpen(at("2024-03-08 16:00")); !nextOpen.Equal(time.Date(2024, 3, 11, 13, 30, 0, 0, time.UTC)) {
+		t.Errorf("NextOpen over DST change = %s, want 13:30 UTC", nextOpen.UTC())
+	}
+}
+
+func TestCalendarNoSessions(t *testing.T) {
+	cal := nasdaqCalendar(t)
+	cal.Weekdays = nil
+
+	// Only the overridden half day of 2024-07-03 is left
+	if _, err := cal.NextOpen(at("2024-07-05 10:00")); err == nil {
+		t.Error("NextOpen() succeeded without any trading day")
+	}
+
+	if _, err := cal.NextClose(at("2024-07-05 10:00")); err == nil {
+		t.Error("NextClose() succeeded without any trading day")
+	}
+}
+
+func TestCalendarOvernightSession(t *testing.T) {
+	// Futures trade from 18:00 to 17:00 the next day, opening Sunday to Thursday
+	cal := &twelvedata.Calendar{
+		TimeZone: "America/New_York",
+		Weekdays: []time.Weekday{time.Sunday, time.Monday, time.Tuesday, time.Wednesday, time.Thursday},
+		Sessions: []twelvedata.CalendarSession{{Name: "Globex", Type: twelvedata.ExchangeSessionTypeCore, Open: 18 * time.Hour, Close: 17 * time.Hour}},
+	}
+
+	isOpen := map[string]bool{
+		"2024-06-09 17:59": false,
+		"2024-06-09 18:00": true,
+		"2024-06-10 00:00": true,
+		"2024-06-10 10:00": true,
+		"2024-06-10 17:00": false,
+		"2024-06-10 18:00": true,
+		"2024-06-14 16:59": true,
+		"2024-06-14 17:00": false,
+		"2024-06-15 10:00": false,
+	}
+	for wallClock, want := range isOpen {
+		if got := cal.IsOpen(at(wallClock)); got != want {
+			t.Errorf("IsOpen(%s) = %v, want %v", wallClock, got, want)
+		}
+	}
+
+	nextClose, err := cal.NextClose(at("2024-06-10 10:00"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect(t, "NextClose()", wallClocks([]time.Time{nextClose}), []string{"2024-06-10 17:00"})
+
+	nextOpen, err := cal.NextOpen(at("2024-06-14 17:00"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect(t, "NextOpen()", wallClocks([]time.Time{nextOpen}), []string{"2024-06-16 18:00"})
+
+	bars, err := cal.ExpectedBars(at("2024-06-10 15:00"), at("2024-06-10 19:00"), twelvedata.TimeSeriesInterval1Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect(t, "ExpectedBars()", wallClocks(bars), []string{"2024-06-10 15:00", "2024-06-10 16:00", "2024-06-10 18:00", "2024-06-10 19:00"})
+}
+
+func TestCalendarTradingDays(t *testing.T) {
+	cal := nasdaqCalendar(t)
+
+	got := wallClocks(cal.TradingDays(at("2024-07-01 12:00"), at("2024-07-07 12:00")))
+	want := []string{"2024-07-01 00:00", "2024-07-02 00:00", "2024-07-03 00:00", "2024-07-05 00:00"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("TradingDays() = %q, want %q", got, want)
+	}
+}
+
+func TestCalendarExpectedBars(t *testing.T) {
+	cal := nasdaqCalendar(t)
+
+	tests := []struct {
+		name     string
+		from, to time.Time
+		interval twelvedata.TimeSeriesInterval
+		want     []string
+	}{
+		{
+			name:     "hourly over half day and holiday",
+			from:     at("2024-07-03 00:00"),
+			to:       at("2024-07-05 23:59"),
+			interval: twelvedata.TimeSeriesInterval1Hour,
+			want: []string{
+				"2024-07-03 09:30", "2024-07-03 10:30", "2024-07-03 11:30", "2024-07-03 12:30",
+				"2024-07-05 09:30", "2024-07-05 10:30", "2024-07-05 11:30", "2024-07-05 12:30", "2024-07-05 13:30",
+				"2024-07-05 14:30", "2024-07-05 15:30",
+			},
+		},
+		{
+			name:     "30 minutes over DST change",
+			from:     at("2024-03-08 15:00"),
+			to:       at("2024-03-11 10:30"),
+			interval: twelvedata.TimeSeriesInterval30Min,
+			want:     []string{"2024-03-08 15:00", "2024-03-08 15:30", "2024-03-11 09:30", "2024-03-11 10:00", "2024-03-11 10:30"},
+		},
+		{
+			name:     "daily",
+			from:     at("2024-07-01 00:00"),
+			to:       at("2024-07-07 00:00"),
+			interval: twelvedata.TimeSeriesInterval1Day,
+			want:     []string{"2024-07-01 00:00", "2024-07-02 00:00", "2024-07-03 00:00", "2024-07-05 00:00"},
+		},
+		{
+			name:     "weekly",
+			from:     at("2024-07-01 00:00"),
+			to:       at("2024-07-14 00:00"),
+			interval: twelvedata.TimeSeriesInterval1Week,
+			want:     []string{"2024-07-01 00:00", "2024-07-08 00:00"},
+		},
+		{
+			name:     "monthly",
+			from:     at("2024-06-15 00:00"),
+			to:       at("2024-08-10 00:00"),
+			interval: twelvedata.TimeSeriesInterval1Month,
+			want:     []string{"2024-07-01 00:00", "2024-08-01 00:00"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bars, err := cal.ExpectedBars(tt.from, tt.to, tt.interval)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := wallClocks(bars); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ExpectedBars() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := cal.ExpectedBars(at("2024-07-01 00:00"), at("2024-07-02 00:00"), "3min"); err == nil {
+		t.Error("ExpectedBars() succeeded with an unsupported interval")
+	}
+}
+
+func TestCalendarSetSessionsInExchangeTimezone(t *testing.T) {
+	cal := nasdaqCalendar(t)
+
+	tokyo := mustLoadLocation("Asia/Tokyo")
+
+	// 2024-12-25 08:00 in Tokyo is still 2024-12-24 in New York
+	cal.AddHoliday(time.Date(2024, 12, 25, 8, 0, 0, 0, tokyo))
+
+	if cal.IsTradingDay(at("2024-12-24 12:00")) {
+		t.Error("2024-12-24 is a trading day, want the holiday")
+	}
+
+	if !cal.IsTradingDay(at("2024-12-25 12:00")) {
+		t.Error("2024-12-25 isn't a trading day, want the regular sessions")
+	}
+}
+
+func TestCalendarSaveAndLoad(t *testing.T) {
+	cal := nasdaqCalendar(t)
+
+	var buf bytes.Buffer
+	if err := cal.Save(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := twelvedata.LoadCalendar(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, wallClock := range []string{"2024-07-02 10:00", "2024-07-03 12:59", "2024-07-03 13:00", "2024-07-04 10:00"} {
+		if loaded.IsOpen(at(wallClock)) != cal.IsOpen(at(wallClock)) {
+			t.Errorf("loaded IsOpen(%s) = %v, want %v", wallClock, loaded.IsOpen(at(wallClock)), cal.IsOpen(at(wallClock)))
+		}
+	}
+}
@@ -0,0 +1,247 @@
+package twelvedata
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Tick is a live price update, e.g. from a price poll or a stream
+type Tick struct {
+	Time   time.Time
+	Price  float64
+	Volume float64 // Volume traded since the previous tick, zero when unknown
+}
+
+// BarEventKind tells what happened to the bar of a BarEvent
+type BarEventKind int
+
+const (
+	BarUpdated    BarEventKind = iota // A tick updated the open bar
+	BarClosed                         // The open bar ended at its interval boundary
+	BarReconciled                     // The official bar of the API replaced a bar built from ticks
+)
+
+// BarEvent is a change of the series of a CandleBuilder
+type BarEvent struct {
+	Kind   BarEventKind
+	Candle TimeSeriesCandle
+}
+
+// CandleBuilder keeps a candle series up to date with live ticks. It is seeded with history, updates the open bar
+// with each tick and closes it at the interval boundary in the exchange timezone. Bars built from ticks are
+// replaced by the official bars of the API with Reconcile. It is safe for concurrent use.
+type CandleBuilder struct {
+	mu       sync.Mutex
+	meta     TimeSeriesResponseMeta
+	interval TimeSeriesInterval
+	loc      *time.Location
+	opts     ResampleOptions
+
+	candles []TimeSeriesCandle // Closed bars in ascending order
+	open    *TimeSeriesCandle  // Bar being built, nil before the first tick
+	openEnd time.Time
+	built   map[int64]bool // Unix times of the closed bars built from ticks that weren't reconciled yet
+}
+
+// NewCandleBuilder creates a builder seeded with the candles of history, in its interval and exchange timezone. The
+// last candle stays open while its interval hasn't ended. Intraday bars are aligned to opts.SessionOpen, like with
+// Resample, and with a session set, ticks outside of it are dropped unless opts.IncludeExtendedHours is set.
+func NewCandleBuilder(history *TimeSeriesResponse, opts ResampleOptions) (*CandleBuilder, error) {
+	if history == nil {
+		return nil, errors.New("history is required")
+	}
+
+	interval := TimeSeriesInterval(history.Meta.Interval)
+	if _, ok := intervalDuration(interval); !ok && interval != TimeSeriesInterval1Month {
+		return nil, errors.Errorf("unsupported interval %s", interval)
+	}
+
+	loc, err := time.LoadLocation(history.Meta.ExchangeTimezone)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load exchange timezone")
+	}
+
+	b := &CandleBuilder{
+		meta:     history.Meta,
+		interval: interval,
+		loc:      loc,
+		opts:     opts,
+		built:    make(map[int64]bool),
+	}
+
+	for _, candle := range sortedCandles(history.Candles) {
+		candle.DateTime = TDZonedTime{Time: candle.DateTime.In(loc)}
+		b.candles = append(b.candles, candle)
+	}
+
+	if n := len(b.candles); n > 0 {
+		last := b.candles[n-1]
+		if _, _, end := bucketSpan(last.DateTime.Time, interval, opts); end.After(time.Now()) {
+			b.open, b.openEnd = &last, end
+			b.candles = b.candles[:n-1]
+		}
+	}
+
+	return b, nil
+}
+
+// AddTick updates the series with a tick. It closes the open bar when the tick is past its end and starts a new bar
+// when needed. Ticks older than the open bar are dropped.
+func (b *CandleBuilder) AddTick(tick Tick) []BarEvent {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	t := tick.Time.In(b.loc)
+	if !inSession(t, b.opts) {
+		return nil
+	}
+
+	label, _, end := bucketSpan(t, b.interval, b.opts)
+	events := b.advance(t)
+
+	if b.open != nil && label.Before(b.open.DateTime.Time) {
+		return events
+	}
+
+	price := NewTDFloat(tick.Price)
+	if b.open == nil {
+		if n := len(b.candles); n > 0 && !label.After(b.candles[n-1].DateTime.Time) {
+			// The bar of the tick is closed already
+			return events
+		}
+
+		b.open = &TimeSeriesCandle{DateTime: TDZonedTime{Time: label}, Open: price, High: price, Low: price, Close: price}
+		b.openEnd = end
+	}
+
+	b.open.High = maxTDFloat(b.open.High, price)
+	b.open.Low = minTDFloat(b.open.Low, price)
+	b.open.Close = price
+	if tick.Volume != 0 {
+		b.open.Volume = addTDFloat(b.open.Volume, NewTDFloat(tick.Volume))
+	}
+
+	return append(events, BarEvent{Kind: BarUpdated, Candle: *b.open})
+}
+
+// Advance closes the open bar when now is past its end, so bars close on time without waiting for the next tick
+func (b *CandleBuilder) Advance(now time.Time) []BarEvent {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.advance(now)
+}
+
+// advance implements Advance. b.mu must be held.
+func (b *CandleBuilder) advance(now time.Time) []BarEvent {
+	if b.open == nil || now.Before(b.openEnd) {
+		return nil
+	}
+
+	closed := *b.open
+	b.candles = append(b.candles, closed)
+	b.built[closed.DateTime.Unix()] = true
+	b.open = nil
+
+	return []BarEvent{{Kind: BarClosed, Candle: closed}}
+}
+
+// Reconcile replaces the closed bars built from ticks with the official candles of the API, and adds the official
+// candles of closed bars that got no tick. Candles of the open bar and later ones are ignored, as they aren't final.
+func (b *CandleBuilder) Reconcile(official []TimeSeriesCandle) []BarEvent {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var events []BarEvent
+	for _, candle := range sortedCandles(official) {
+		candle.DateTime = TDZonedTime{Time: candle.DateTime.In(b.loc)}
+		if b.open != nil && !candle.DateTime.Before(b.open.DateTime.Time) {
+			break
+		}
+
+		i := sort.Search(len(b.candles), func(i int) bool {
+			return !b.candles[i].DateTime.Before(candle.DateTime.Time)
+		})
+
+		switch {
+		case i < len(b.candles) && b.candles[i].DateTime.Equal(candle.DateTime.Time):
+			if !b.built[candle.DateTime.Unix()] {
+				continue
+			}
+			delete(b.built, candle.DateTime.Unix())
+			b.candles[i] = candle
+		case i == len(b.candles) && b.open == nil:
+			// Newer than the series, so the bar may still be open
+			continue
+		default:
+			b.candles = append(b.candles, TimeSeriesCandle{})
+			copy(b.candles[i+1:], b.candles[i:])
+			b.candles[i] = candle
+		}
+
+		events = append(events, BarEvent{Kind: BarReconciled, Candle: candle})
+	}
+
+	return events
+}
+
+// Unreconciled returns the start times of the closed bars built from ticks that weren't replaced by official candles
+// yet, in ascending order
+func (b *CandleBuilder) Unreconciled() []time.Time {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var starts []time.Time
+	for _, candle := range b.candles {
+		if b.built[candle.DateTime.Unix()] {
+			starts = append(starts, candle.DateTime.Time)
+		}
+	}
+
+	return starts
+}
+
+// Series returns a copy of the series in ascending order, with the open bar last
+func (b *CandleBuilder) Series() *TimeSeriesResponse {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	candles := make([]TimeSeriesCandle, len(b.candles), len(b.candles)+1)
+	copy(candles, b.candles)
+	if b.open != nil {
+		candles = append(candles, *b.open)
+	}
+
+	return &TimeSeriesResponse{Meta: b.meta, Candles: candles}
+}
+
+// ReconcileCandles fetches the official candles of the bars of builder that weren't reconciled yet and reconciles
+// them. req selects the listing, its interval, timezone, range, order and output size are set from the builder.
+func (c *APIClient) ReconcileCandles(builder *CandleBuilder, req TimeSeriesRequest) ([]BarEvent, error) {
+	starts := builder.Unreconciled()
+	if len(starts) == 0 {
+		return nil, nil
+	}
+
+	interval := builder.interval
+	timezone := builder.loc.String()
+	order := "asc"
+	outputSize := 5000
+	req.Interval = &interval
+	req.TimeZone = &timezone
+	req.StartDate = &starts[0]
+	req.EndDate = nil
+	req.Order = &order
+	req.OutputSize = &outputSize
+	req.FromEarliest = nil
+
+	official, err := c.GetTimeSeries(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error fetching official candles")
+	}
+
+	return builder.Reconcile(official.Candles), nil
+}
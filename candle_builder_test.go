@@ -0,0 +1,234 @@
+package twelvedata_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jonnotjohn/twelvedata-go"
+)
+
+// regularSession aligns bars to the regular session of New York
+var regularSession = twelvedata.ResampleOptions{SessionOpen: 9*time.Hour + 30*time.Minute, SessionClose: 16 * time.Hour}
+
+// describeEvents lists bar events as readable lines
+func describeEvents(events []twelvedata.BarEvent) []string {
+	kinds := map[twelvedata.BarEventKind]string{
+		twelvedata.BarUpdated:    "updated",
+		twelvedata.BarClosed:     "closed",
+		twelvedata.BarReconciled: "reconciled",
+	}
+
+	var lines []string
+	for _, event := range events {
+		c := event.Candle
+		volume := "-"
+		if c.Volume.Valid {
+			volume = fmt.Sprint(c.Volume.Float64)
+		}
+
+		lines = append(lines, fmt.Sprintf("%s %s %v/%v/%v/%v %s", kinds[event.Kind], c.DateTime.In(newYork).Format("15:04"),
+			c.Open.Float64, c.High.Float64, c.Low.Float64, c.Close.Float64, volume))
+	}
+
+	return lines
+}
+
+// starts returns the start times of candles
+func starts(candles []twelvedata.TimeSeriesCandle) []time.Time {
+	times := make([]time.Time, len(candles))
+	for i, candle := range candles {
+		times[i] = candle.DateTime.Time
+	}
+
+	return times
+}
+
+// tick returns a tick at a "2006-01-02 15:04:05" wall clock in New York
+func tick(wallClock string, price, volume float64) twelvedata.Tick {
+	t, err := time.ParseInLocation("2006-01-02 15:04:05", wallClock, newYork)
+	if err != nil {
+		panic(err)
+	}
+	return twelvedata.Tick{Time: t, Price: price, Volume: volume}
+}
+
+func TestCandleBuilderTicks(t *testing.T) {
+	type step struct {
+		tick    *twelvedata.Tick
+		advance time.Time // Time to advance to when tick is nil
+		events  []string
+	}
+	ticked := func(tick twelvedata.Tick, events ...string) step { return step{tick: &tick, events: events} }
+	advanced := func(wallClock string, events ...string) step {
+		return step{advance: tick(wallClock, 0, 0).Time, events: events}
+	}
+
+	tests := []struct {
+		name     string
+		interval twelvedata.TimeSeriesInterval
+		opts     twelvedata.ResampleOptions
+		history  []twelvedata.TimeSeriesCandle // Closed bars, in descending order like the API returns them
+		steps    []step
+		series   []string // Wall clocks of the resulting series
+	}{
+		{
+			name:     "aggregation and rollover at the boundary",
+			interval: twelvedata.TimeSeriesInterval5Min,
+			opts:     regularSession,
+			steps: []step{
+				ticked(tick("2024-06-14 09:40:05", 100, 10), "updated 09:40 100/100/100/100 10"),
+				ticked(tick("2024-06-14 09:42:00", 102, 0), "updated 09:40 100/102/100/102 10"),
+				ticked(tick("2024-06-14 09:44:59", 99, 5), "updated 09:40 100/102/99/99 15"),
+				ticked(tick("2024-06-14 09:45:00", 101, 1), "closed 09:40 100/102/99/99 15", "updated 09:45 101/101/101/101 1"),
+				ticked(tick("2024-06-14 09:44:30", 98, 1)), // Late tick of the closed bar
+				advanced("2024-06-14 09:49:59"),
+				advanced("2024-06-14 09:50:00", "closed 09:45 101/101/101/101 1"),
+				ticked(tick("2024-06-14 09:49:00", 98, 1)), // Late tick after the bar closed
+			},
+			series: []string{"2024-06-14 09:30", "2024-06-14 09:35", "2024-06-14 09:40", "2024-06-14 09:45"},
+		},
+		{
+			name:     "ticks skipping bars",
+			interval: twelvedata.TimeSeriesInterval5Min,
+			opts:     regularSession,
+			steps: []step{
+				ticked(tick("2024-06-14 09:40:00", 100, 0), "updated 09:40 100/100/100/100 -"),
+				ticked(tick("2024-06-14 09:57:00", 104, 0), "closed 09:40 100/100/100/100 -", "updated 09:55 104/104/104/104 -"),
+			},
+			series: []string{"2024-06-14 09:30", "2024-06-14 09:35", "2024-06-14 09:40", "2024-06-14 09:55"},
+		},
+		{
+			name:     "session boundaries",
+			interval: twelvedata.TimeSeriesInterval1Hour,
+			opts:     regularSession,
+			steps: []step{
+				ticked(tick("2024-06-14 09:29:59", 99, 1)), // Pre-market
+				ticked(tick("2024-06-14 15:59:00", 100, 1), "updated 15:30 100/100/100/100 1"),
+				advanced("2024-06-14 16:00:00", "closed 15:30 100/100/100/100 1"),
+				ticked(tick("2024-06-14 16:10:00", 101, 1)), // Post-market
+			},
+			history: []twelvedata.TimeSeriesCandle{bar("2024-06-13 15:30", 99)},
+			series:  []string{"2024-06-13 15:30", "2024-06-14 15:30"},
+		},
+		{
+			name:     "daily bars in the exchange timezone",
+			interval: twelvedata.TimeSeriesInterval1Day,
+			history:  []twelvedata.TimeSeriesCandle{bar("2024-06-13 00:00", 99), bar("2024-06-12 00:00", 98)},
+			steps: []step{
+				ticked(tick("2024-06-14 09:30:00", 100, 1), "updated 00:00 100/100/100/100 1"),
+				ticked(tick("2024-06-14 23:59:59", 103, 1), "updated 00:00 100/103/100/103 2"),
+				ticked(tick("2024-06-15 00:00:00", 104, 1), "closed 00:00 100/103/100/103 2", "updated 00:00 104/104/104/104 1"),
+			},
+			series: []string{"2024-06-12 00:00", "2024-06-13 00:00", "2024-06-14 00:00", "2024-06-15 00:00"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			history := tt.history
+			if history == nil {
+				history = []twelvedata.TimeSeriesCandle{bar("2024-06-14 09:35", 99), bar("2024-06-14 09:30", 98)}
+			}
+
+			builder, err := twelvedata.NewCandleBuilder(series(tt.interval, history...), tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for i, step := range tt.steps {
+				var events []twelvedata.BarEvent
+				if step.tick != nil {
+					events = builder.AddTick(*step.tick)
+				} else {
+					events = builder.Advance(step.advance)
+				}
+				expect(t, fmt.Sprintf("events of step %d", i), describeEvents(events), step.events)
+			}
+
+			expect(t, "series", wallClocks(starts(builder.Series().Candles)), tt.series)
+		})
+	}
+}
+
+func TestCandleBuilderReconcile(t *testing.T) {
+	history := series(twelvedata.TimeSeriesInterval5Min, bar("2024-06-14 09:30", 98), bar("2024-06-14 09:35", 99))
+	builder, err := twelvedata.NewCandleBuilder(history, regularSession)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	builder.AddTick(tick("2024-06-14 09:40:00", 100, 10))
+	builder.AddTick(tick("2024-06-14 09:50:00", 101, 10))
+	builder.AddTick(tick("2024-06-14 09:55:00", 102, 10))
+	expect(t, "Unreconciled()", builder.Unreconciled(), []time.Time{at("2024-06-14 09:40"), at("2024-06-14 09:50")})
+
+	// The official 09:35 bar doesn't replace history, the one of the open 09:55 bar isn't final yet
+	events := builder.Reconcile([]twelvedata.TimeSeriesCandle{
+		bar("2024-06-14 09:55", 112),
+		bar("2024-06-14 09:50", 111),
+		bar("2024-06-14 09:45", 110),
+		bar("2024-06-14 09:40", 109),
+		bar("2024-06-14 09:35", 108),
+	})
+	expect(t, "events", describeEvents(events), []string{
+		"reconciled 09:40 109/110/108/109 100",
+		"reconciled 09:45 110/111/109/110 100",
+		"reconciled 09:50 111/112/110/111 100",
+	})
+	expect(t, "Unreconciled()", builder.Unreconciled(), []time.Time(nil))
+
+	candles := builder.Series().Candles
+	expect(t, "series", wallClocks(starts(candles)), []string{
+		"2024-06-14 09:30", "2024-06-14 09:35", "2024-06-14 09:40", "2024-06-14 09:45", "2024-06-14 09:50", "2024-06-14 09:55",
+	})
+	expect(t, "09:35 close", candles[1].Close.Float64, 99.0)
+	expect(t, "09:55 close", candles[5].Close.Float64, 102.0)
+
+	// Reconciling again changes nothing
+	expect(t, "events of a second reconcile", builder.Reconcile([]twelvedata.TimeSeriesCandle{bar("2024-06-14 09:40", 1)}), []twelvedata.BarEvent(nil))
+}
+
+func TestReconcileCandles(t *testing.T) {
+	server, client, _ := serverClient(t, 1)
+	server.SetNow(func() time.Time { return at("2024-06-14 12:00") })
+
+	official, err := client.GetTimeSeries(twelvedata.TimeSeriesRequest{
+		Symbol:     ptr("AAPL"),
+		Interval:   ptr(twelvedata.TimeSeriesInterval5Min),
+		StartDate:  ptr(at("2024-06-14 09:30")),
+		OutputSize: ptr(3),
+		Order:      ptr("asc"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect(t, "official", wallClocks(starts(official.Candles)), []string{"2024-06-14 09:30", "2024-06-14 09:35", "2024-06-14 09:40"})
+
+	history := &twelvedata.TimeSeriesResponse{Meta: official.Meta, Candles: official.Candles[:1]}
+	builder, err := twelvedata.NewCandleBuilder(history, regularSession)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	builder.AddTick(tick("2024-06-14 09:35:10", 1, 1))
+	builder.AddTick(tick("2024-06-14 09:40:10", 2, 1))
+	builder.Advance(at("2024-06-14 09:45"))
+
+	events, err := client.ReconcileCandles(builder, twelvedata.TimeSeriesRequest{Symbol: ptr("AAPL")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect(t, "events", len(events), 2)
+
+	requests := server.Requests()
+	params := requests[len(requests)-1].Params
+	expect(t, "start_date", params["start_date"], "2024-06-14 09:35:00")
+	expect(t, "timezone", params["timezone"], "America/New_York")
+
+	candles := builder.Series().Candles
+	for i, candle := range official.Candles {
+		expect(t, fmt.Sprintf("candle %d", i), candles[i].Close, candle.Close)
+	}
+	expect(t, "Unreconciled()", builder.Unreconciled(), []time.Time(nil))
+}
@@ -5,12 +5,15 @@
 package twelvedata
 
 import (
+	"context"
+	"iter"
+	"log/slog"
+	"net/http"
+	"os"
+	"sync"
 	"time"
 
 	"github.com/go-resty/resty/v2"
-	"github.com/pkg/errors"
-	"go.uber.org/zap"
-	"go.uber.org/zap/zapcore"
 )
 
 type APIUrl string
@@ -22,46 +25,84 @@ const (
 )
 
 type Config struct {
-	Logger        *zap.Logger
-	RestyClient   *resty.Client
-	APIKey        string
-	APIUrl        APIUrl
-	Debug         bool
-	RetryCount    *int
-	RetryWaitTime *time.Duration
-	Timeout       int
+	Logger           Logger        // Receives the log events of the client (e.g. *slog.Logger or NewZapLogger). Defaults to NopLogger, see Debug
+	RestyClient      *resty.Client // Sends the HTTP requests when set and Doer isn't, see RestyDoer
+	Doer             Doer          // Sends the HTTP requests (defaults to an *http.Client with Timeout)
+	APIKey           string
+	APIUrl           APIUrl
+	Debug            bool // Log each response with its body at debug level. Without a Logger, writes to stderr
+	RetryCount       *int
+	RetryWaitTime    *time.Duration
+	Timeout          int   // Seconds before a request times out (default 25). Ignored with a Doer
+	PreserveDecimals bool  // Keep the exact decimals of prices in TDFloat.Decimal. Requests can override it
+	Hooks            Hooks // Observes every request, e.g. with the adapters of the tdotel package
+	QuoteTimezones   bool  // Look up the exchange timezone of quotes missing from the cache, one credit per listing. See GetQuote
 }
 
+// Client is the API endpoints implemented by APIClient, so code using them can be tested with a mock. Helpers built
+// on the endpoints, like FetchMany or GetCalendar, are methods of APIClient only.
+type Client interface {
+	GetQuote(req QuoteRequest) (*Quote, error)
+	GetTimeSeries(req TimeSeriesRequest) (*TimeSeriesResponse, error)
+	GetTimeSeriesContext(ctx context.Context, req TimeSeriesRequest) (*TimeSeriesResponse, error)
+	GetPrice(req PriceRequest) (*Price, error)
+	GetEarliestTimestamp(req EarliestTimestampRequest) (time.Time, error)
+	StreamTimeSeries(req TimeSeriesRequest) (*TimeSeriesStream, error)
+	GetStocks() (*StocksResponse, error)
+	GetStocksRequest(req StocksRequest) (*StocksResponse, error)
+	StreamStocks(req StocksRequest) iter.Seq2[Stocks, error]
+	GetCryptocurrencies() (*CryptoResponse, error)
+	GetCryptocurrenciesRequest(req CryptoRequest) (*CryptoResponse, error)
+	GetLogo(req LogoRequest) (*Logo, error)
+	GetSymbolSearch(req SymbolSearchRequest) (*SymbolSearchResponse, error)
+	GetExchangeRate(req ExchangeRateRequest) (*ExchangeRate, error)
+	GetCurrencyConversion(req CurrencyConversionRequest) (*CurrencyConversion, error)
+	GetInsiderTransactions(req FundamentalsRequest) (*InsiderTransactionsResponse, error)
+	GetInstitutionalHolders(req FundamentalsRequest) (*InstitutionalHoldersResponse, error)
+	GetFundHolders(req FundamentalsRequest) (*FundHoldersResponse, error)
+	GetKeyExecutives(req FundamentalsRequest) (*KeyExecutivesResponse, error)
+	GetOptionsExpiration(req OptionsExpirationRequest) (*OptionsExpirationResponse, error)
+	GetOptionChain(req OptionChainRequest) (*OptionChainResponse, error)
+	GetMarketMovers(req MarketMoversRequest) (*MarketMoversResponse, error)
+	GetMarketState(req MarketStateRequest) ([]MarketState, error)
+	GetExchangeSchedule(req ExchangeScheduleRequest) (*ExchangeScheduleResponse, error)
+	GetFundSummary(req FundRequest) (*FundSummary, error)
+	GetFundPerformance(req FundRequest) (*FundPerformance, error)
+	GetFundRisk(req FundRequest) (*FundRisk, error)
+	GetFundRatings(req FundRequest) (*FundRatings, error)
+	GetFundComposition(req FundRequest) (*FundComposition, error)
+	GetFundPurchaseInfo(req FundRequest) (*FundPurchaseInfo, error)
+	GetFundFull(req FundRequest) (*FundFull, error)
+}
+
+var _ Client = (*APIClient)(nil)
+
 type APIClient struct {
-	Logger *zap.Logger
+	Logger Logger
 	Debug  bool
 	Client *HTTPClient
+
+	timezones        sync.Map // Exchange timezones by symbol, used to decode CSV responses and localize quotes
+	timezoneFailures sync.Map // Times of the failed quote timezone lookups by symbol, see quoteLocation
+	quoteTimezones   bool
+	preserveDecimals bool
 }
 
 // NewAPIClient creates a new API client
 func NewAPIClient(cfg Config) (*APIClient, error) {
-	APIClient := &APIClient{Logger: cfg.Logger, Debug: cfg.Debug}
+	APIClient := &APIClient{Logger: cfg.Logger, Debug: cfg.Debug, preserveDecimals: cfg.PreserveDecimals, quoteTimezones: cfg.QuoteTimezones}
 	if APIClient.Logger == nil {
-		logger, err := createNewLogger()
-		if err != nil {
-			return nil, errors.Wrap(err, "Error creating new zap logger")
+		APIClient.Logger = NopLogger{}
+		if cfg.Debug {
+			// The responses logged by Debug would otherwise go nowhere
+			APIClient.Logger = slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelDebug}))
 		}
-
-		APIClient.Logger = logger
 	}
 
 	if cfg.Timeout == 0 {
 		cfg.Timeout = apiTimeoutDefault
 	}
 
-	// Init resty client
-	if cfg.RestyClient == nil {
-		cfg.RestyClient = resty.New()
-	}
-
-	cfg.RestyClient.SetDebug(APIClient.Debug)
-	cfg.RestyClient.SetTimeout(time.Duration(cfg.Timeout) * time.Second)
-
 	if len(cfg.APIUrl) == 0 {
 		cfg.APIUrl = apiTwelveDataURL
 	}
@@ -70,12 +111,23 @@ func NewAPIClient(cfg Config) (*APIClient, error) {
 		cfg.APIKey = apiKeyDefault
 	}
 
-	cfg.RestyClient.SetBaseURL(string(cfg.APIUrl))
+	// Init the HTTP client
+	if cfg.Doer == nil && cfg.RestyClient != nil {
+		cfg.RestyClient.SetTimeout(time.Duration(cfg.Timeout) * time.Second)
+		cfg.Doer = RestyDoer(cfg.RestyClient)
+	}
+
+	if cfg.Doer == nil {
+		cfg.Doer = &http.Client{Timeout: time.Duration(cfg.Timeout) * time.Second}
+	}
 
 	HTTPClient := &HTTPClient{
-		client: cfg.RestyClient,
-		apiKey: cfg.APIKey,
-		logger: APIClient.Logger,
+		doer:    cfg.Doer,
+		baseURL: string(cfg.APIUrl),
+		apiKey:  cfg.APIKey,
+		debug:   cfg.Debug,
+		logger:  APIClient.Logger,
+		hooks:   cfg.Hooks,
 	}
 
 	if cfg.RetryCount != nil {
@@ -100,15 +152,3 @@ func NewAPIClient(cfg Config) (*APIClient, error) {
 
 	return APIClient, nil
 }
-
-func createNewLogger() (*zap.Logger, error) {
-	cfg := zap.NewProductionConfig()
-	cfg.EncoderConfig.EncodeTime = zapcore.RFC3339TimeEncoder
-
-	logger, err := cfg.Build()
-	if err != nil {
-		return nil, errors.Wrap(err, "Logger Error: init")
-	}
-
-	return logger, nil
-}
@@ -0,0 +1,74 @@
+package twelvedata_test
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jonnotjohn/twelvedata-go"
+	"github.com/jonnotjohn/twelvedata-go/tdtest"
+)
+
+// handlerClient creates an API client serving its requests with handler in-process, without retries
+func handlerClient(t *testing.T, handler http.Handler) *twelvedata.APIClient {
+	t.Helper()
+
+	retryCount := 1
+	client, err := twelvedata.NewAPIClient(twelvedata.Config{
+		APIKey:     "test",
+		Doer:       tdtest.HandlerDoer(handler),
+		RetryCount: &retryCount,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return client
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestDebugWithoutLogger(t *testing.T) {
+	// The default logger of Debug writes to the stderr of the time the client is created
+	stderr, err := os.Create(filepath.Join(t.TempDir(), "stderr"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stderr.Close()
+
+	original := os.Stderr
+	os.Stderr = stderr
+	client, err := twelvedata.NewAPIClient(twelvedata.Config{
+		APIKey: "secret",
+		Debug:  true,
+		Doer: tdtest.HandlerDoer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"price":"212.49001"}`))
+		})),
+	})
+	os.Stderr = original
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.GetPrice(twelvedata.PriceRequest{Symbol: ptr("AAPL")}); err != nil {
+		t.Fatal(err)
+	}
+
+	written, err := os.ReadFile(stderr.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{"level=DEBUG msg=Response", "endpoint=/price", "statusCode=200", `212.49001`} {
+		if !strings.Contains(string(written), want) {
+			t.Errorf("debug output %q, want it to contain %q", written, want)
+		}
+	}
+	if strings.Contains(string(written), "secret") {
+		t.Errorf("debug output %q contains the API key", written)
+	}
+}
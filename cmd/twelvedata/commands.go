@@ -0,0 +1,247 @@
+package main
+
+import (
+	"flag"
+	"strconv"
+	"strings"
+
+	"github.com/jonnotjohn/twelvedata-go"
+	"github.com/pkg/errors"
+)
+
+func quoteCommand(fs *flag.FlagSet) func(client twelvedata.Client, args []string) (*output, error) {
+	var req twelvedata.QuoteRequest
+	var interval *string
+
+	stringFlag(fs, &interval, "interval", `Interval of the quote (e.g. "1min", "1week"). Default is "1day"`)
+	stringFlag(fs, &req.FIGI, "figi", "Financial Instrument Global Identifier")
+	stringFlag(fs, &req.ISIN, "isin", "International Securities Identification Number")
+	stringFlag(fs, &req.CUSIP, "cusip", "Committee on Uniform Securities Identification Procedures")
+	stringFlag(fs, &req.Exchange, "exchange", `Exchange code (e.g. "NASDAQ")`)
+	stringFlag(fs, &req.MicCode, "mic-code", `Market Identifier Code (e.g. "XNAS")`)
+	stringFlag(fs, &req.Country, "country", `Country code (e.g. "US")`)
+	stringFlag(fs, &req.Type, "type", `Type of asset (e.g. "Common Stock")`)
+	stringFlag(fs, &req.TimeZone, "timezone", `Timezone of the response (e.g. "UTC"). Default is the exchange timezone`)
+	intFlag(fs, &req.VolumeTimePeriod, "volume-time-period", "Number of periods for the average volume")
+	intFlag(fs, &req.RollingPeriod, "rolling-period", "Number of hours of the rolling change")
+	intFlag(fs, &req.DP, "dp", "Number of decimal places (0-11)")
+	boolFlag(fs, &req.PrePost, "prepost", "Include pre and post market data")
+	boolFlag(fs, &req.EOD, "eod", "Return the data of the last closed day")
+
+	return func(client twelvedata.Client, args []string) (*output, error) {
+		if len(args) == 0 {
+			return nil, errors.New("at least one symbol is required")
+		}
+
+		if interval != nil {
+			quoteInterval := twelvedata.QuoteInterval(*interval)
+			req.Interval = &quoteInterval
+		}
+
+		out := &output{header: []string{"symbol", "name", "exchange", "datetime", "open", "high", "low", "close", "volume", "change", "percent_change", "is_market_open"}}
+		quotes := make([]*twelvedata.Quote, 0, len(args))
+		for _, symbol := range args {
+			req.Symbol = &symbol
+			quote, err := client.GetQuote(req)
+			if err != nil {
+				return nil, errors.Wrap(err, symbol)
+			}
+
+			quotes = append(quotes, quote)
+			out.rows = append(out.rows, []string{
+				quote.Symbol,
+				quote.Name,
+				quote.Exchange,
+				formatTime(quote.DateTime.Time),
+				quote.Open.String(),
+				quote.High.String(),
+				quote.Low.String(),
+				quote.Close.String(),
+				quote.Volume.String(),
+				quote.Change.String(),
+				quote.PercentChange.String(),
+				strconv.FormatBool(quote.IsMarketOpen),
+			})
+		}
+
+		out.value = quotes
+		if len(quotes) == 1 {
+			out.value = quotes[0]
+		}
+		return out, nil
+	}
+}
+
+func seriesCommand(fs *flag.FlagSet) func(client twelvedata.Client, args []string) (*output, error) {
+	var req twelvedata.TimeSeriesRequest
+	interval := fs.String("interval", "1day", `Interval of the candles (e.g. "1min", "1h", "1day")`)
+
+	stringFlag(fs, &req.FIGI, "figi", "Financial Instrument Global Identifier")
+	stringFlag(fs, &req.ISIN, "isin", "International Securities Identification Number")
+	stringFlag(fs, &req.CUSIP, "cusip", "Committee on Uniform Securities Identification Procedures")
+	stringFlag(fs, &req.Exchange, "exchange", `Exchange code (e.g. "NASDAQ")`)
+	stringFlag(fs, &req.MicCode, "mic-code", `Market Identifier Code (e.g. "XNAS")`)
+	stringFlag(fs, &req.Country, "country", `Country code (e.g. "US")`)
+	stringFlag(fs, &req.Type, "type", `Type of asset (e.g. "Common Stock")`)
+	stringFlag(fs, &req.TimeZone, "timezone", `Timezone of the response (e.g. "UTC"). Default is the exchange timezone`)
+	stringFlag(fs, &req.Order, "order", `Sorting order, "asc" or "desc". Default is "desc"`)
+	stringFlag(fs, &req.Adjust, "adjust", `Price adjustment: "none", "dividends", "splits" or "all"`)
+	intFlag(fs, &req.OutputSize, "outputsize", "Number of candles (1-5000). Default is 30")
+	intFlag(fs, &req.DP, "dp", "Number of decimal places (0-11)")
+	boolFlag(fs, &req.PrePost, "prepost", "Include pre and post market data")
+	boolFlag(fs, &req.PreviousClose, "previous-close", "Include the previous close")
+	boolFlag(fs, &req.FromEarliest, "from-earliest", "Start from the earliest available candle when -start is not given")
+	timeFlag(fs, &req.Date, "date", "Single day to fetch")
+	timeFlag(fs, &req.StartDate, "start", "Start of the series")
+	timeFlag(fs, &req.EndDate, "end", "End of the series")
+
+	return func(client twelvedata.Client, args []string) (*output, error) {
+		if len(args) != 1 {
+			return nil, errors.New("exactly one symbol is required")
+		}
+
+		seriesInterval := twelvedata.TimeSeriesInterval(*interval)
+		req.Symbol = &args[0]
+		req.Interval = &seriesInterval
+
+		series, err := client.GetTimeSeries(req)
+		if err != nil {
+			return nil, err
+		}
+
+		out := &output{value: series, header: []string{"datetime", "open", "high", "low", "close", "volume"}}
+		for _, candle := range series.Candles {
+			out.rows = append(out.rows, []string{
+				formatTime(candle.DateTime.Time),
+				candle.Open.String(),
+				candle.High.String(),
+				candle.Low.String(),
+				candle.Close.String(),
+				candle.Volume.String(),
+			})
+		}
+		return out, nil
+	}
+}
+
+func stocksCommand(fs *flag.FlagSet) func(client twelvedata.Client, args []string) (*output, error) {
+	var req twelvedata.StocksRequest
+
+	stringFlag(fs, &req.Symbol, "symbol", `Symbol of the stock (e.g. "AAPL")`)
+	stringFlag(fs, &req.FIGI, "figi", "Financial Instrument Global Identifier")
+	stringFlag(fs, &req.ISIN, "isin", "International Securities Identification Number")
+	stringFlag(fs, &req.CUSIP, "cusip", "Committee on Uniform Securities Identification Procedures")
+	stringFlag(fs, &req.Exchange, "exchange", `Exchange code (e.g. "NASDAQ")`)
+	stringFlag(fs, &req.MicCode, "mic-code", `Market Identifier Code (e.g. "XNAS")`)
+	stringFlag(fs, &req.Country, "country", `Country code (e.g. "US")`)
+	stringFlag(fs, &req.Type, "type", `Type of the stock (e.g. "Common Stock", "ETF")`)
+
+	return func(client twelvedata.Client, args []string) (*output, error) {
+		if len(args) > 0 {
+			return nil, errors.Errorf("unexpected arguments %s", strings.Join(args, " "))
+		}
+
+		// The full list is large, CSV keeps the transfer small
+		format := twelvedata.ResponseFormatCSV
+		req.Format = &format
+
+		stocks, err := client.GetStocksRequest(req)
+		if err != nil {
+			return nil, err
+		}
+
+		out := &output{value: stocks.Data, header: []string{"symbol", "name", "currency", "exchange", "mic_code", "country", "type"}}
+		for _, stock := range stocks.Data {
+			out.rows = append(out.rows, []string{stock.Symbol, stock.Name, stock.Currency, stock.Exchange, stock.MicCode, stock.Country, stock.Type})
+		}
+		return out, nil
+	}
+}
+
+func cryptoCommand(fs *flag.FlagSet) func(client twelvedata.Client, args []string) (*output, error) {
+	var req twelvedata.CryptoRequest
+
+	stringFlag(fs, &req.Symbol, "symbol", `Symbol of the pair (e.g. "BTC/USD")`)
+	stringFlag(fs, &req.Exchange, "exchange", `Exchange name (e.g. "Binance")`)
+	stringFlag(fs, &req.CurrencyBase, "currency-base", `Base currency (e.g. "BTC")`)
+	stringFlag(fs, &req.CurrencyQuote, "currency-quote", `Quote currency (e.g. "USD")`)
+
+	return func(client twelvedata.Client, args []string) (*output, error) {
+		if len(args) > 0 {
+			return nil, errors.Errorf("unexpected arguments %s", strings.Join(args, " "))
+		}
+
+		cryptos, err := client.GetCryptocurrenciesRequest(req)
+		if err != nil {
+			return nil, err
+		}
+
+		out := &output{value: cryptos.Data, header: []string{"symbol", "currency_base", "currency_quote", "available_exchanges"}}
+		for _, crypto := range cryptos.Data {
+			out.rows = append(out.rows, []string{crypto.Symbol, crypto.CurrencyBase, crypto.CurrencyQuote, strings.Join(crypto.AvailableExchanges, ",")})
+		}
+		return out, nil
+	}
+}
+
+func logoCommand(fs *flag.FlagSet) func(client twelvedata.Client, args []string) (*output, error) {
+	var req twelvedata.LogoRequest
+
+	stringFlag(fs, &req.Exchange, "exchange", `Exchange code (e.g. "NASDAQ")`)
+	stringFlag(fs, &req.MicCode, "mic-code", `Market Identifier Code (e.g. "XNAS")`)
+	stringFlag(fs, &req.Country, "country", `Country code (e.g. "US")`)
+
+	return func(client twelvedata.Client, args []string) (*output, error) {
+		if len(args) == 0 {
+			return nil, errors.New("at least one symbol is required")
+		}
+
+		out := &output{header: []string{"symbol", "exchange", "url", "logo_base", "logo_quote"}}
+		logos := make([]*twelvedata.Logo, 0, len(args))
+		for _, symbol := range args {
+			req.Symbol = &symbol
+			logo, err := client.GetLogo(req)
+			if err != nil {
+				return nil, errors.Wrap(err, symbol)
+			}
+
+			logos = append(logos, logo)
+			out.rows = append(out.rows, []string{logo.Meta.Symbol, logo.Meta.Exchange, logo.URL, logo.LogoBase, logo.LogoQuote})
+		}
+
+		out.value = logos
+		if len(logos) == 1 {
+			out.value = logos[0]
+		}
+		return out, nil
+	}
+}
+
+func searchCommand(fs *flag.FlagSet) func(client twelvedata.Client, args []string) (*output, error) {
+	var req twelvedata.SymbolSearchRequest
+
+	stringFlag(fs, &req.FIGI, "figi", "Financial Instrument Global Identifier")
+	stringFlag(fs, &req.ISIN, "isin", "International Securities Identification Number")
+	stringFlag(fs, &req.CUSIP, "cusip", "Committee on Uniform Securities Identification Procedures")
+	intFlag(fs, &req.OutputSize, "outputsize", "Number of matches (1-120). Default is 30")
+
+	return func(client twelvedata.Client, args []string) (*output, error) {
+		if len(args) == 0 {
+			return nil, errors.New("a query is required")
+		}
+
+		query := strings.Join(args, " ")
+		req.Symbol = &query
+
+		results, err := client.GetSymbolSearch(req)
+		if err != nil {
+			return nil, err
+		}
+
+		out := &output{value: results.Data, header: []string{"symbol", "name", "type", "exchange", "mic_code", "country", "currency"}}
+		for _, result := range results.Data {
+			out.rows = append(out.rows, []string{result.Symbol, result.InstrumentName, result.InstrumentType, result.Exchange, result.MicCode, result.Country, result.Currency})
+		}
+		return out, nil
+	}
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"flag"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// The flag helpers below set a request field only when the flag is given, so unset flags leave the API defaults
+
+func stringFlag(fs *flag.FlagSet, target **string, name, usage string) {
+	fs.Func(name, usage, func(value string) error {
+		*target = &value
+		return nil
+	})
+}
+
+func intFlag(fs *flag.FlagSet, target **int, name, usage string) {
+	fs.Func(name, usage, func(value string) error {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			return errors.Errorf("invalid integer %q", value)
+		}
+		*target = &parsed
+		return nil
+	})
+}
+
+func boolFlag(fs *flag.FlagSet, target **bool, name, usage string) {
+	fs.BoolFunc(name, usage, func(value string) error {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return errors.Errorf("invalid boolean %q", value)
+		}
+		*target = &parsed
+		return nil
+	})
+}
+
+// timeFlag accepts "2006-01-02" and "2006-01-02 15:04:05"
+func timeFlag(fs *flag.FlagSet, target **time.Time, name, usage string) {
+	fs.Func(name, usage+` ("2006-01-02" or "2006-01-02 15:04:05")`, func(value string) error {
+		for _, layout := range []string{"2006-01-02 15:04:05", "2006-01-02"} {
+			if parsed, err := time.Parse(layout, value); err == nil {
+				*target = &parsed
+				return nil
+			}
+		}
+		return errors.Errorf("invalid date %q", value)
+	})
+}
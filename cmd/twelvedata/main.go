@@ -0,0 +1,202 @@
+// Command twelvedata queries the TwelveData API from the command line, using the same request validation as the
+// twelvedata package.
+//
+// Usage:
+//
+//	twelvedata <command> [flags] [arguments]
+//
+// The commands are quote, series, stocks, crypto, logo and search. Run "twelvedata <command> -h" for their flags.
+// Results are printed as a table, JSON or CSV (-format).
+//
+// The API key is read from the TWELVEDATA_API_KEY environment variable or, when unset, from the "api_key" field of
+// a JSON config file: -config, $TWELVEDATA_CONFIG or twelvedata/config.json under the user config directory. The
+// config file may also set "api_url".
+//
+// The exit status is 0 on success, 1 on errors, 2 on usage errors and 3 when the API answered with an error payload
+// (e.g. an unknown symbol or an invalid API key).
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jonnotjohn/twelvedata-go"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+)
+
+const (
+	envAPIKey = "TWELVEDATA_API_KEY"
+	envConfig = "TWELVEDATA_CONFIG"
+)
+
+// config is the content of the config file
+type config struct {
+	APIKey string `json:"api_key"`
+	APIUrl string `json:"api_url"`
+}
+
+// command is a subcommand. setup registers its flags and returns the function running it.
+type command struct {
+	usage string
+	setup func(fs *flag.FlagSet) func(client twelvedata.Client, args []string) (*output, error)
+}
+
+var commands = map[string]command{
+	"quote":  {usage: "quote [flags] SYMBOL...", setup: quoteCommand},
+	"series": {usage: "series [flags] SYMBOL", setup: seriesCommand},
+	"stocks": {usage: "stocks [flags]", setup: stocksCommand},
+	"crypto": {usage: "crypto [flags]", setup: cryptoCommand},
+	"logo":   {usage: "logo [flags] SYMBOL...", setup: logoCommand},
+	"search": {usage: "search [flags] QUERY", setup: searchCommand},
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "--help" || args[0] == "help" {
+		usage(stderr)
+		return 2
+	}
+
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "twelvedata: unknown command %q\n\n", args[0])
+		usage(stderr)
+		return 2
+	}
+
+	fs := flag.NewFlagSet(args[0], flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: twelvedata %s\n\nFlags:\n", cmd.usage)
+		fs.PrintDefaults()
+	}
+
+	format := fs.String("format", "table", "Output format: table, json or csv")
+	configPath := fs.String("config", "", "Path of the JSON config file (default $"+envConfig+" or the user config directory)")
+	debug := fs.Bool("debug", false, "Log requests and retries to stderr")
+	runCommand := cmd.setup(fs)
+
+	if err := fs.Parse(args[1:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+
+	write, ok := writers[strings.ToLower(*format)]
+	if !ok {
+		fmt.Fprintf(stderr, "twelvedata: unknown format %q (table, json or csv)\n", *format)
+		return 2
+	}
+
+	client, err := newClient(*configPath, *debug)
+	if err != nil {
+		fmt.Fprintln(stderr, "twelvedata:", err)
+		return 1
+	}
+
+	out, err := runCommand(client, fs.Args())
+	if err != nil {
+		fmt.Fprintln(stderr, "twelvedata:", err)
+
+		var apiErr *twelvedata.APIError
+		if errors.As(err, &apiErr) {
+			return 3
+		}
+		return 1
+	}
+
+	if err := write(stdout, out); err != nil {
+		fmt.Fprintln(stderr, "twelvedata:", err)
+		return 1
+	}
+
+	return 0
+}
+
+func usage(w io.Writer) {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintln(w, "Usage: twelvedata <command> [flags] [arguments]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	for _, name := range names {
+		fmt.Fprintln(w, "  "+commands[name].usage)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "The API key is read from $%s or from the config file.\n", envAPIKey)
+}
+
+// newClient creates the API client, taking the API key from the environment or the config file
+func newClient(configPath string, debug bool) (*twelvedata.APIClient, error) {
+	cfg, err := loadConfig(configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if apiKey := os.Getenv(envAPIKey); apiKey != "" {
+		cfg.APIKey = apiKey
+	}
+
+	if cfg.APIKey == "" {
+		return nil, errors.Errorf("no API key: set $%s or \"api_key\" in the config file", envAPIKey)
+	}
+
+	var logger twelvedata.Logger = twelvedata.NopLogger{}
+	if debug {
+		logger = slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	}
+
+	return twelvedata.NewAPIClient(twelvedata.Config{
+		APIKey: cfg.APIKey,
+		APIUrl: twelvedata.APIUrl(cfg.APIUrl),
+		Logger: logger,
+		Debug:  debug,
+	})
+}
+
+// loadConfig reads the config file. Only an explicitly given file has to exist.
+func loadConfig(path string) (config, error) {
+	explicit := path != ""
+	if !explicit {
+		path = os.Getenv(envConfig)
+		explicit = path != ""
+	}
+
+	if !explicit {
+		dir, err := os.UserConfigDir()
+		if err != nil {
+			return config{}, nil
+		}
+		path = filepath.Join(dir, "twelvedata", "config.json")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !explicit && errors.Is(err, os.ErrNotExist) {
+			return config{}, nil
+		}
+		return config{}, errors.Wrap(err, "Error reading config file")
+	}
+
+	var cfg config
+	if err := jsoniter.Unmarshal(data, &cfg); err != nil {
+		return config{}, errors.Wrapf(err, "Error decoding config file %s", path)
+	}
+
+	return cfg, nil
+}
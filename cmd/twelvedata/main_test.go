@@ -0,0 +1,284 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jonnotjohn/twelvedata-go/tdtest"
+)
+
+// cli runs commands against a fake server, isolated from the environment and the config of the user
+type cli struct {
+	t      *testing.T
+	server *tdtest.Server
+	dir    string
+}
+
+func newCLI(t *testing.T) *cli {
+	t.Helper()
+
+	server := tdtest.NewServer()
+	t.Cleanup(server.Close)
+	server.SetNow(func() time.Time { return time.Date(2024, 6, 14, 18, 0, 0, 0, time.UTC) })
+
+	dir := t.TempDir()
+	t.Setenv(envAPIKey, "")
+	t.Setenv(envConfig, "")
+	t.Setenv("HOME", dir)
+	t.Setenv("XDG_CONFIG_HOME", dir)
+
+	return &cli{t: t, server: server, dir: dir}
+}
+
+// writeConfig writes a config file for the fake server with apiKey and returns its path
+func (c *cli) writeConfig(name, apiKey string) string {
+	c.t.Helper()
+
+	data, err := json.Marshal(config{APIKey: apiKey, APIUrl: c.server.URL})
+	if err != nil {
+		c.t.Fatal(err)
+	}
+
+	path := filepath.Join(c.dir, name)
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		c.t.Fatal(err)
+	}
+
+	return path
+}
+
+// run runs the command with a config file for the fake server, unless args name another one
+func (c *cli) run(args ...string) (code int, stdout, stderr string) {
+	c.t.Helper()
+
+	if os.Getenv(envConfig) == "" {
+		c.t.Setenv(envConfig, c.writeConfig("config.json", "test"))
+	}
+
+	var out, errOut bytes.Buffer
+	code = run(args, &out, &errOut)
+	return code, out.String(), errOut.String()
+}
+
+// lastParams returns the params of the last request the server received
+func (c *cli) lastParams() map[string]string {
+	c.t.Helper()
+
+	requests := c.server.Requests()
+	if len(requests) == 0 {
+		c.t.Fatal("no request reached the server")
+	}
+
+	return requests[len(requests)-1].Params
+}
+
+func TestFlagsToRequest(t *testing.T) {
+	tests := []struct {
+		name   string
+		args   []string
+		path   string
+		params map[string]string // Expected params. The server doesn't record the API key
+	}{
+		{
+			name: "series",
+			args: []string{"series", "-interval", "1h", "-start", "2024-06-10", "-end", "2024-06-12 16:00:00", "-outputsize", "5",
+				"-order", "asc", "-timezone", "UTC", "-adjust", "splits", "-prepost", "-dp", "2", "AAPL"},
+			path: "/time_series",
+			params: map[string]string{
+				"symbol": "AAPL", "interval": "1h", "start_date": "2024-06-10 00:00:00", "end_date": "2024-06-12 16:00:00",
+				"outputsize": "5", "order": "asc", "timezone": "UTC", "adjust": "splits", "prepost": "true", "dp": "2",
+			},
+		},
+		{
+			name:   "series defaults",
+			args:   []string{"series", "MSFT"},
+			path:   "/time_series",
+			params: map[string]string{"symbol": "MSFT", "interval": "1day"},
+		},
+		{
+			name:   "quote",
+			args:   []string{"quote", "-interval", "1week", "-mic-code", "XNGS", "-eod=false", "-rolling-period", "8", "IBM"},
+			path:   "/quote",
+			params: map[string]string{"symbol": "IBM", "interval": "1week", "mic_code": "XNGS", "eod": "false", "rolling_period": "8"},
+		},
+		{
+			name:   "stocks",
+			args:   []string{"stocks", "-exchange", "NASDAQ", "-type", "Common Stock"},
+			path:   "/stocks",
+			params: map[string]string{"exchange": "NASDAQ", "type": "Common Stock", "format": "CSV"},
+		},
+		{
+			name:   "search",
+			args:   []string{"search", "-outputsize", "3", "apple", "inc"},
+			path:   "/symbol_search",
+			params: map[string]string{"symbol": "apple inc", "outputsize": "3"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newCLI(t)
+
+			if code, _, stderr := c.run(tt.args...); code != 0 {
+				t.Fatalf("exit code %d: %s", code, stderr)
+			}
+
+			requests := c.server.Requests()
+			if path := requests[len(requests)-1].Path; path != tt.path {
+				t.Errorf("path = %s, want %s", path, tt.path)
+			}
+
+			if params := c.lastParams(); !reflect.DeepEqual(params, tt.params) {
+				t.Errorf("params = %v, want %v", params, tt.params)
+			}
+		})
+	}
+}
+
+func TestOutputFormats(t *testing.T) {
+	args := []string{"series", "-interval", "1day", "-outputsize", "2", "-order", "asc", "AAPL"}
+
+	c := newCLI(t)
+	code, table, stderr := c.run(append([]string{args[0], "-format", "table"}, args[1:]...)...)
+	if code != 0 {
+		t.Fatalf("table exit code %d: %s", code, stderr)
+	}
+
+	lines := strings.Split(strings.TrimSpace(table), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("table lines = %q, want a header and 2 candles", lines)
+	}
+	if fields := strings.Fields(lines[0]); !reflect.DeepEqual(fields, []string{"DATETIME", "OPEN", "HIGH", "LOW", "CLOSE", "VOLUME"}) {
+		t.Errorf("table header = %q", lines[0])
+	}
+	if !strings.HasPrefix(lines[1], "2024-06-13 ") || !strings.HasPrefix(lines[2], "2024-06-14 ") {
+		t.Errorf("table rows = %q, want the daily candles of 2024-06-13 and 2024-06-14", lines[1:])
+	}
+
+	code, csvOut, stderr := c.run(append([]string{args[0], "-format", "csv"}, args[1:]...)...)
+	if code != 0 {
+		t.Fatalf("csv exit code %d: %s", code, stderr)
+	}
+	records, err := csv.NewReader(strings.NewReader(csvOut)).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 3 || !reflect.DeepEqual(records[0], []string{"datetime", "open", "high", "low", "close", "volume"}) {
+		t.Fatalf("csv records = %q, want a header and 2 candles", records)
+	}
+	for i, record := range records[1:] {
+		if want := strings.Fields(lines[i+1]); !reflect.DeepEqual(record, want) {
+			t.Errorf("csv record %d = %q, want the table row %q", i, record, want)
+		}
+	}
+
+	code, jsonOut, stderr := c.run(append([]string{args[0], "-format", "JSON"}, args[1:]...)...)
+	if code != 0 {
+		t.Fatalf("json exit code %d: %s", code, stderr)
+	}
+	var series struct {
+		Meta struct {
+			Symbol           string `json:"symbol"`
+			ExchangeTimezone string `json:"exchange_timezone"`
+		} `json:"meta"`
+		Values []struct {
+			DateTime string `json:"datetime"`
+			Close    string `json:"close"`
+		} `json:"values"`
+	}
+	if err := json.Unmarshal([]byte(jsonOut), &series); err != nil {
+		t.Fatalf("json output %s: %v", jsonOut, err)
+	}
+	if series.Meta.Symbol != "AAPL" || series.Meta.ExchangeTimezone != "America/New_York" || len(series.Values) != 2 {
+		t.Fatalf("json output = %+v, want the meta block and 2 candles of AAPL", series)
+	}
+	if close := series.Values[1].Close; close != records[2][4] {
+		t.Errorf("json close = %s, want the csv close %s", close, records[2][4])
+	}
+}
+
+func TestExitCodes(t *testing.T) {
+	tests := []struct {
+		name   string
+		args   []string
+		noKey  bool // Run without any API key
+		code   int
+		stderr string // Part of the expected error output
+	}{
+		{name: "success", args: []string{"quote", "AAPL"}, code: 0},
+		{name: "help", args: []string{"quote", "-h"}, code: 0, stderr: "Usage: twelvedata quote"},
+		{name: "no command", code: 2, stderr: "Usage: twelvedata <command>"},
+		{name: "unknown command", args: []string{"quotes"}, code: 2, stderr: `unknown command "quotes"`},
+		{name: "unknown flag", args: []string{"quote", "-nope", "AAPL"}, code: 2, stderr: "flag provided but not defined"},
+		{name: "invalid flag value", args: []string{"series", "-start", "yesterday", "AAPL"}, code: 2, stderr: `invalid date "yesterday"`},
+		{name: "unknown format", args: []string{"quote", "-format", "xml", "AAPL"}, code: 2, stderr: `unknown format "xml"`},
+		{name: "missing argument", args: []string{"series"}, code: 1, stderr: "exactly one symbol is required"},
+		{name: "no API key", args: []string{"quote", "AAPL"}, noKey: true, code: 1, stderr: "no API key"},
+		{name: "missing config file", args: []string{"quote", "-config", "missing.json", "AAPL"}, code: 1, stderr: "Error reading config file"},
+		{name: "API error payload", args: []string{"quote", "AAPL", "NOPE"}, code: 3, stderr: "NOPE"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newCLI(t)
+			if tt.noKey {
+				t.Setenv(envConfig, filepath.Join(c.dir, "none.json"))
+				if err := os.WriteFile(filepath.Join(c.dir, "none.json"), []byte(`{}`), 0o600); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			code, stdout, stderr := c.run(tt.args...)
+			if code != tt.code {
+				t.Errorf("exit code = %d, want %d (stderr %q)", code, tt.code, stderr)
+			}
+			if !strings.Contains(stderr, tt.stderr) {
+				t.Errorf("stderr = %q, want it to contain %q", stderr, tt.stderr)
+			}
+			if tt.code != 0 && stdout != "" {
+				t.Errorf("stdout = %q, want nothing on failure", stdout)
+			}
+		})
+	}
+}
+
+func TestAPIKeyPrecedence(t *testing.T) {
+	tests := []struct {
+		name   string
+		env    string // $TWELVEDATA_API_KEY
+		config string // Config file named by $TWELVEDATA_CONFIG
+		flag   string // Config file named by -config
+		key    string // API key the server accepts, it answers other keys with an error payload
+	}{
+		{name: "environment over config file", env: "from-env", config: "from-config", key: "from-env"},
+		{name: "config file", config: "from-config", key: "from-config"},
+		{name: "flag over environment config", config: "from-config", flag: "from-flag", key: "from-flag"},
+		{name: "environment over flag", env: "from-env", flag: "from-flag", key: "from-env"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newCLI(t)
+			c.server.SetAPIKey(tt.key)
+
+			t.Setenv(envAPIKey, tt.env)
+			t.Setenv(envConfig, c.writeConfig("env.json", tt.config))
+
+			args := []string{"quote", "AAPL"}
+			if tt.flag != "" {
+				args = append([]string{"quote", "-config", c.writeConfig("flag.json", tt.flag)}, "AAPL")
+			}
+
+			if code, _, stderr := c.run(args...); code != 0 {
+				t.Errorf("exit code %d, want the key %q to be used: %s", code, tt.key, stderr)
+			}
+		})
+	}
+}
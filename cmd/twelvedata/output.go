@@ -0,0 +1,66 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// output is the result of a command. JSON output encodes value, table and CSV output print the rows.
+type output struct {
+	value  any
+	header []string
+	rows   [][]string
+}
+
+var writers = map[string]func(w io.Writer, out *output) error{
+	"table": writeTable,
+	"json":  writeJSON,
+	"csv":   writeCSV,
+}
+
+func writeTable(w io.Writer, out *output) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.ToUpper(strings.Join(out.header, "\t")))
+	for _, row := range out.rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
+
+func writeJSON(w io.Writer, out *output) error {
+	data, err := jsoniter.ConfigCompatibleWithStandardLibrary.MarshalIndent(out.value, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+func writeCSV(w io.Writer, out *output) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(out.header); err != nil {
+		return err
+	}
+	if err := cw.WriteAll(out.rows); err != nil {
+		return err
+	}
+	return cw.Error()
+}
+
+// formatTime prints dates without a time of day as dates
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	if t.Hour() == 0 && t.Minute() == 0 && t.Second() == 0 {
+		return t.Format("2006-01-02")
+	}
+	return t.Format("2006-01-02 15:04:05")
+}
@@ -0,0 +1,88 @@
+package twelvedata
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// CreditBudget paces requests to stay within a number of API credits per minute. Like the API, it counts the credits
+// of each clock minute. It is safe for concurrent use, so one budget can be shared by everything using an API key.
+type CreditBudget struct {
+	mu        sync.Mutex
+	perMinute int
+	minute    time.Time // Start of the minute counted by used
+	used      int
+}
+
+// NewCreditBudget creates a budget of perMinute credits (e.g. 8 for the free plan)
+func NewCreditBudget(perMinute int) *CreditBudget {
+	if perMinute < 1 {
+		perMinute = 1
+	}
+
+	return &CreditBudget{perMinute: perMinute}
+}
+
+// PerMinute returns the credits available each minute
+func (b *CreditBudget) PerMinute() int {
+	return b.perMinute
+}
+
+// reset starts counting a new minute when now is past the counted one. b.mu must be held.
+func (b *CreditBudget) reset(now time.Time) {
+	if minute := now.Truncate(time.Minute); !minute.Equal(b.minute) {
+		b.minute = minute
+		b.used = 0
+	}
+}
+
+// Wait blocks until credits are left in the current minute and takes them. Taking more credits than PerMinute waits
+// for a whole minute.
+func (b *CreditBudget) Wait(ctx context.Context, credits int) error {
+	credits = min(credits, b.perMinute)
+
+	for {
+		b.mu.Lock()
+		now := time.Now()
+		b.reset(now)
+		if b.used+credits <= b.perMinute {
+			b.used += credits
+			b.mu.Unlock()
+			return nil
+		}
+		next := b.minute.Add(time.Minute)
+		b.mu.Unlock()
+
+		wait := time.NewTimer(next.Sub(now))
+		select {
+		case <-wait.C:
+		case <-ctx.Done():
+			wait.Stop()
+			return ctx.Err()
+		}
+	}
+}
+
+// Observe updates the budget with the credits left in the current minute, as reported by the api-credits-left
+// header. Credits spent elsewhere with the same API key are taken into account this way.
+func (b *CreditBudget) Observe(left int) {
+	if left < 0 {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.reset(time.Now())
+	b.used = max(b.used, b.perMinute-left)
+}
+
+// Exhaust marks the credits of the current minute as used, e.g. after the API answered 429
+func (b *CreditBudget) Exhaust() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.reset(time.Now())
+	b.used = b.perMinute
+}
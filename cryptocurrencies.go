@@ -1,6 +1,8 @@
 package twelvedata
 
 import (
+	"bytes"
+
 	jsoniter "github.com/json-iterator/go"
 	"github.com/pkg/errors"
 )
@@ -9,6 +11,31 @@ const (
 	urlEndpointCrypto = "/cryptocurrencies"
 )
 
+// CryptoRequest is the available parameters for a cryptocurrencies list request. All fields are optional.
+type CryptoRequest struct {
+	Symbol        *string         // Symbol of the pair (e.g. "BTC/USD")
+	Exchange      *string         // Exchange name (e.g. "Binance")
+	CurrencyBase  *string         // Base currency (e.g. "BTC")
+	CurrencyQuote *string         // Quote currency (e.g. "USD")
+	Format        *ResponseFormat // Response format ("JSON" or "CSV"). CSV responses are smaller for the full list
+	Delimiter     *string         // Delimiter of CSV responses (default is ";")
+}
+
+func (req CryptoRequest) ToParams() (map[string]string, error) {
+	params := make(map[string]string)
+
+	AddStringParam(params, "symbol", req.Symbol)
+	AddStringParam(params, "exchange", req.Exchange)
+	AddStringParam(params, "currency_base", req.CurrencyBase)
+	AddStringParam(params, "currency_quote", req.CurrencyQuote)
+
+	if err := addFormatParams(params, req.Format, req.Delimiter); err != nil {
+		return nil, err
+	}
+
+	return params, nil
+}
+
 type Crypto struct {
 	Symbol             string   `json:"symbol"`              // Cryptocurrency symbol (e.g. "BTC/USD", "ETH/EUR")
 	AvailableExchanges []string `json:"available_exchanges"` // List of exchanges where the cryptocurrency is available (e.g. ["Binance", "Coinbase"])
@@ -21,15 +48,39 @@ type CryptoResponse struct {
 	Status string   `json:"status"` // Status of the response
 }
 
+// GetCryptocurrencies returns the list of every cryptocurrency
 func (c *APIClient) GetCryptocurrencies() (cryptoResponse *CryptoResponse, err error) {
-	data, err := c.Client.Get(urlEndpointCrypto, nil)
+	return c.GetCryptocurrenciesRequest(CryptoRequest{})
+}
+
+// GetCryptocurrenciesRequest returns the list of cryptocurrencies, filtered by req
+func (c *APIClient) GetCryptocurrenciesRequest(req CryptoRequest) (cryptoResponse *CryptoResponse, err error) {
+	params, err := req.ToParams()
+	if err != nil {
+		return nil, errors.Wrap(err, "Error converting CryptoRequest to params")
+	}
+
+	data, err := c.Client.Get(urlEndpointCrypto, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "Error fetching cryptocurrencies data")
 	}
 
-	err = jsoniter.Unmarshal(data.Body(), &cryptoResponse)
+	if isCSV(req.Format) {
+		var cryptos []Crypto
+		err = c.Client.decode(data, "CSV cryptocurrencies", func(body []byte) (err error) {
+			cryptos, err = NewCryptoCSVDecoder(bytes.NewReader(body), csvDelimiter(req.Delimiter)).DecodeAll()
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		return &CryptoResponse{Data: cryptos, Status: "ok"}, nil
+	}
+
+	err = c.Client.unmarshal(jsoniter.ConfigDefault, data, &cryptoResponse, "cryptocurrencies")
 	if err != nil {
-		return nil, errors.Wrap(err, "Error unmarshalling cryptocurrencies response")
+		return nil, err
 	}
 
 	return cryptoResponse, nil
@@ -0,0 +1,340 @@
+package twelvedata
+
+import (
+	"bufio"
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+)
+
+type ResponseFormat string
+
+const (
+	ResponseFormatJSON ResponseFormat = "JSON"
+	ResponseFormatCSV  ResponseFormat = "CSV"
+)
+
+// csvDelimiterDefault is the delimiter the API uses for CSV responses when none is requested
+const csvDelimiterDefault = ';'
+
+// addFormatParams adds the format and delimiter parameters, validating that the delimiter is a single character
+func addFormatParams(params map[string]string, format *ResponseFormat, delimiter *string) error {
+	if format == nil {
+		if delimiter != nil {
+			return errors.New("delimiter is only supported with the CSV format")
+		}
+		return nil
+	}
+
+	if *format != ResponseFormatJSON && *format != ResponseFormatCSV {
+		return errors.Errorf("invalid response format %q", *format)
+	}
+	params["format"] = string(*format)
+
+	if delimiter != nil {
+		if *format != ResponseFormatCSV {
+			return errors.New("delimiter is only supported with the CSV format")
+		}
+
+		if utf8.RuneCountInString(*delimiter) != 1 {
+			return errors.Errorf("delimiter must be a single character, got %q", *delimiter)
+		}
+		params["delimiter"] = *delimiter
+	}
+
+	return nil
+}
+
+func isCSV(format *ResponseFormat) bool {
+	return format != nil && *format == ResponseFormatCSV
+}
+
+// csvDelimiter returns the delimiter rune to decode a CSV response with
+func csvDelimiter(delimiter *string) rune {
+	if delimiter == nil {
+		return csvDelimiterDefault
+	}
+
+	r, _ := utf8.DecodeRuneInString(*delimiter)
+	return r
+}
+
+// csvErrorPayloadLimit bounds the JSON read when a CSV response starts like an error payload
+const csvErrorPayloadLimit = 64 * 1024
+
+// csvRecordReader reads CSV records and exposes their fields by header name
+type csvRecordReader struct {
+	input   *bufio.Reader
+	reader  *csv.Reader
+	columns map[string]int
+	record  []string
+}
+
+func newCSVRecordReader(r io.Reader, delimiter rune) *csvRecordReader {
+	input := bufio.NewReader(r)
+	reader := csv.NewReader(input)
+	reader.Comma = delimiter
+	reader.FieldsPerRecord = -1
+	reader.ReuseRecord = true
+	reader.LazyQuotes = true
+
+	return &csvRecordReader{input: input, reader: reader}
+}
+
+// next advances to the next record, reading the header first. It returns io.EOF at the end of the input, and the
+// *APIError of a JSON error payload the API answered with in place of the CSV data.
+func (r *csvRecordReader) next() error {
+	if r.columns == nil {
+		if err := r.jsonError(); err != nil {
+			return err
+		}
+
+		header, err := r.reader.Read()
+		if err != nil {
+			if err == io.EOF {
+				return io.EOF
+			}
+			return errors.Wrap(err, "failed to read CSV header")
+		}
+
+		r.columns = make(map[string]int, len(header))
+		for i, name := range header {
+			r.columns[strings.TrimSpace(name)] = i
+		}
+	}
+
+	record, err := r.reader.Read()
+	if err != nil {
+		if err == io.EOF {
+			return io.EOF
+		}
+		return errors.Wrap(err, "failed to read CSV record")
+	}
+
+	r.record = record
+	return nil
+}
+
+// jsonError returns the error of a JSON body the API answered with in place of the CSV data, or nil when the input
+// doesn't start with a JSON object
+func (r *csvRecordReader) jsonError() error {
+	for {
+		c, err := r.input.ReadByte()
+		if err != nil {
+			// Read errors and the empty input are reported when reading the header
+			return nil
+		}
+
+		if c == ' ' || c == '\t' || c == '\r' || c == '\n' {
+			continue
+		}
+
+		if err := r.input.UnreadByte(); err != nil || c != '{' {
+			return nil
+		}
+		break
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.input, csvErrorPayloadLimit))
+	if err != nil {
+		return errors.Wrap(err, "failed to read JSON response")
+	}
+
+	if apiErr := apiErrorOf(body); apiErr != nil {
+		return apiErr
+	}
+
+	return errors.New("unexpected JSON response in place of CSV")
+}
+
+// field returns the value of the named column, or "" when the column is absent
+func (r *csvRecordReader) field(name string) string {
+	i, ok := r.columns[name]
+	if !ok || i >= len(r.record) {
+		return ""
+	}
+
+	return strings.TrimSpace(r.record[i])
+}
+
+func (r *csvRecordReader) float(name string) (TDFloat, error) {
+	value := r.field(name)
+	if value == "" {
+		return TDFloat{}, nil
+	}
+
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return TDFloat{}, errors.Wrapf(err, "invalid %s value %q", name, value)
+	}
+
+	return NewTDFloat(parsed), nil
+}
+
+// TimeSeriesCSVDecoder decodes the candles of a CSV time series response one row at a time
+type TimeSeriesCSVDecoder struct {
+	records          *csvRecordReader
+	loc              *time.Location
+	preserveDecimals bool
+}
+
+// NewTimeSeriesCSVDecoder creates a decoder reading from r. CSV responses don't carry the exchange timezone, so
+// datetimes are parsed in loc (UTC when nil).
+func NewTimeSeriesCSVDecoder(r io.Reader, delimiter rune, loc *time.Location) *TimeSeriesCSVDecoder {
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	return &TimeSeriesCSVDecoder{records: newCSVRecordReader(r, delimiter), loc: loc}
+}
+
+// Next returns the next candle, or io.EOF when there are no more
+func (d *TimeSeriesCSVDecoder) Next() (candle TimeSeriesCandle, err error) {
+	if err := d.records.next(); err != nil {
+		return TimeSeriesCandle{}, err
+	}
+
+	parsedTime, err := parseInLocation(d.records.field("datetime"), d.loc)
+	if err != nil {
+		return TimeSeriesCandle{}, err
+	}
+	candle.DateTime = TDZonedTime{Time: parsedTime}
+
+	for _, column := range []struct {
+		name  string
+		value *TDFloat
+	}{
+		{"open", &candle.Open},
+		{"high", &candle.High},
+		{"low", &candle.Low},
+		{"close", &candle.Close},
+		{"volume", &candle.Volume},
+	} {
+		if *column.value, err = d.records.float(column.name); err != nil {
+			return TimeSeriesCandle{}, err
+		}
+
+		if d.preserveDecimals && column.value.Valid {
+			column.value.Decimal = Decimal(d.records.field(column.name))
+		}
+	}
+
+	return candle, nil
+}
+
+// DecodeAll reads the remaining candles
+func (d *TimeSeriesCSVDecoder) DecodeAll() ([]TimeSeriesCandle, error) {
+	var candles []TimeSeriesCandle
+	for {
+		candle, err := d.Next()
+		if err == io.EOF {
+			return candles, nil
+		}
+
+		if err != nil {
+			return nil, wrapElementError(err, "error decoding CSV candle %d", len(candles))
+		}
+
+		candles = append(candles, candle)
+	}
+}
+
+// StocksCSVDecoder decodes a CSV stocks list one row at a time
+type StocksCSVDecoder struct {
+	records *csvRecordReader
+}
+
+func NewStocksCSVDecoder(r io.Reader, delimiter rune) *StocksCSVDecoder {
+	return &StocksCSVDecoder{records: newCSVRecordReader(r, delimiter)}
+}
+
+// Next returns the next stock, or io.EOF when there are no more
+func (d *StocksCSVDecoder) Next() (Stocks, error) {
+	if err := d.records.next(); err != nil {
+		return Stocks{}, err
+	}
+
+	return Stocks{
+		Symbol:   d.records.field("symbol"),
+		Name:     d.records.field("name"),
+		Currency: d.records.field("currency"),
+		Exchange: d.records.field("exchange"),
+		MicCode:  d.records.field("mic_code"),
+		Country:  d.records.field("country"),
+		Type:     d.records.field("type"),
+		FigiCode: d.records.field("figi_code"),
+		CfiCode:  d.records.field("cfi_code"),
+		ISIN:     d.records.field("isin"),
+		CUSIP:    d.records.field("cusip"),
+	}, nil
+}
+
+// DecodeAll reads the remaining stocks
+func (d *StocksCSVDecoder) DecodeAll() ([]Stocks, error) {
+	var stocks []Stocks
+	for {
+		stock, err := d.Next()
+		if err == io.EOF {
+			return stocks, nil
+		}
+
+		if err != nil {
+			return nil, wrapElementError(err, "error decoding CSV stock %d", len(stocks))
+		}
+
+		stocks = append(stocks, stock)
+	}
+}
+
+// CryptoCSVDecoder decodes a CSV cryptocurrencies list one row at a time
+type CryptoCSVDecoder struct {
+	records *csvRecordReader
+}
+
+func NewCryptoCSVDecoder(r io.Reader, delimiter rune) *CryptoCSVDecoder {
+	return &CryptoCSVDecoder{records: newCSVRecordReader(r, delimiter)}
+}
+
+// Next returns the next cryptocurrency, or io.EOF when there are no more
+func (d *CryptoCSVDecoder) Next() (Crypto, error) {
+	if err := d.records.next(); err != nil {
+		return Crypto{}, err
+	}
+
+	// Exchanges are listed in a single column, e.g. "[Binance, Coinbase Pro]"
+	var exchanges []string
+	for _, exchange := range strings.Split(strings.Trim(d.records.field("available_exchanges"), "[]"), ",") {
+		if exchange = strings.Trim(strings.TrimSpace(exchange), `"'`); exchange != "" {
+			exchanges = append(exchanges, exchange)
+		}
+	}
+
+	return Crypto{
+		Symbol:             d.records.field("symbol"),
+		AvailableExchanges: exchanges,
+		CurrencyBase:       d.records.field("currency_base"),
+		CurrencyQuote:      d.records.field("currency_quote"),
+	}, nil
+}
+
+// DecodeAll reads the remaining cryptocurrencies
+func (d *CryptoCSVDecoder) DecodeAll() ([]Crypto, error) {
+	var cryptos []Crypto
+	for {
+		crypto, err := d.Next()
+		if err == io.EOF {
+			return cryptos, nil
+		}
+
+		if err != nil {
+			return nil, wrapElementError(err, "error decoding CSV cryptocurrency %d", len(cryptos))
+		}
+
+		cryptos = append(cryptos, crypto)
+	}
+}
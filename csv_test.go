@@ -0,0 +1,250 @@
+package twelvedata_test
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jonnotjohn/twelvedata-go"
+)
+
+func TestCSVTimeSeriesMeta(t *testing.T) {
+	server, client, _ := serverClient(t, 1)
+	server.SetNow(func() time.Time { return time.Date(2024, 6, 14, 18, 0, 0, 0, time.UTC) })
+
+	tests := []struct {
+		name     string
+		timezone *string
+		location string // Location of the datetimes
+	}{
+		{name: "exchange timezone", location: "America/New_York"},
+		{name: "request timezone", timezone: ptr("Asia/Tokyo"), location: "Asia/Tokyo"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			series, err := client.GetTimeSeries(twelvedata.TimeSeriesRequest{
+				Symbol:     ptr("AAPL"),
+				Interval:   ptr(twelvedata.TimeSeriesInterval1Hour),
+				OutputSize: ptr(2),
+				TimeZone:   tt.timezone,
+				Format:     ptr(twelvedata.ResponseFormatCSV),
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// The meta block names the timezone of the exchange, not the one of the datetimes
+			expect(t, "Meta", series.Meta, twelvedata.TimeSeriesResponseMeta{
+				Symbol:           "AAPL",
+				Interval:         "1h",
+				ExchangeTimezone: "America/New_York",
+			})
+			expect(t, "location", series.Candles[0].DateTime.Location().String(), tt.location)
+			expect(t, "latest candle", series.Candles[0].DateTime.UTC(), time.Date(2024, 6, 14, 17, 30, 0, 0, time.UTC))
+		})
+	}
+}
+
+func TestCSVTimeSeriesTimezoneNotCachedWhenMissing(t *testing.T) {
+	var paths []string
+	client := handlerClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		paths = append(paths, query.Get("format")+" "+query.Get("outputsize"))
+
+		switch {
+		case query.Get("format") == "CSV":
+			_, _ = w.Write([]byte("datetime;open;high;low;close;volume\n2024-06-14 15:30:00;1;2;0.5;1.5;100\n"))
+		case len(paths) == 1:
+			// The first answer names no exchange timezone
+			_, _ = w.Write([]byte(`{"meta":{"symbol":"XYZ","interval":"1h"},"values":[],"status":"ok"}`))
+		default:
+			_, _ = w.Write([]byte(`{"meta":{"symbol":"XYZ","interval":"1h","exchange_timezone":"America/New_York"},"values":[],"status":"ok"}`))
+		}
+	}))
+
+	req := twelvedata.TimeSeriesRequest{Symbol: ptr("XYZ"), Interval: ptr(twelvedata.TimeSeriesInterval1Hour)}
+	if _, err := client.GetTimeSeries(req); err != nil {
+		t.Fatal(err)
+	}
+
+	req.Format = ptr(twelvedata.ResponseFormatCSV)
+	series, err := client.GetTimeSeries(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect(t, "requests", paths, []string{" ", " 1", "CSV "})
+	expect(t, "ExchangeTimezone", series.Meta.ExchangeTimezone, "America/New_York")
+	expect(t, "DateTime", wallClock(series.Candles[0].DateTime.Time), "2024-06-14 15:30:00")
+	expect(t, "location", series.Candles[0].DateTime.Location().String(), "America/New_York")
+}
+
+func TestTimeSeriesCSVDecoder(t *testing.T) {
+	tests := []struct {
+		name      string
+		body      string
+		delimiter rune
+		loc       *time.Location
+		want      []twelvedata.TimeSeriesCandle
+		code      int    // Code of the expected *APIError
+		err       string // Part of the expected error message
+	}{
+		{
+			name:      "datetimes in the exchange timezone across daylight saving time",
+			body:      "datetime;open;high;low;close;volume\n2024-03-11 09:30:00;172.94;173.44;172.69;173.065;\n2024-03-08 15:30:00;170.73;171.23;170.48;170.855;4512200\n",
+			delimiter: ';',
+			loc:       newYork,
+			want: []twelvedata.TimeSeriesCandle{
+				{
+					DateTime: twelvedata.TDZonedTime{Time: at("2024-03-11 09:30")},
+					Open:     twelvedata.NewTDFloat(172.94),
+					High:     twelvedata.NewTDFloat(173.44),
+					Low:      twelvedata.NewTDFloat(172.69),
+					Close:    twelvedata.NewTDFloat(173.065),
+				},
+				{
+					DateTime: twelvedata.TDZonedTime{Time: at("2024-03-08 15:30")},
+					Open:     twelvedata.NewTDFloat(170.73),
+					High:     twelvedata.NewTDFloat(171.23),
+					Low:      twelvedata.NewTDFloat(170.48),
+					Close:    twelvedata.NewTDFloat(170.855),
+					Volume:   twelvedata.NewTDFloat(4512200),
+				},
+			},
+		},
+		{
+			name:      "comma delimiter, dates and a missing volume column",
+			body:      "datetime,open,high,low,close\n2024-06-14,1.07,1.0712,1.0668,1.0703\n",
+			delimiter: ',',
+			want: []twelvedata.TimeSeriesCandle{{
+				DateTime: twelvedata.TDZonedTime{Time: time.Date(2024, 6, 14, 0, 0, 0, 0, time.UTC)},
+				Open:     twelvedata.NewTDFloat(1.07),
+				High:     twelvedata.NewTDFloat(1.0712),
+				Low:      twelvedata.NewTDFloat(1.0668),
+				Close:    twelvedata.NewTDFloat(1.0703),
+			}},
+		},
+		{
+			name:      "only a header",
+			body:      "datetime;open;high;low;close;volume\n",
+			delimiter: ';',
+		},
+		{
+			name:      "JSON error payload",
+			body:      "\n  {\"code\":404,\"message\":\"**symbol** not found: INVALID\",\"status\":\"error\"}",
+			delimiter: ';',
+			code:      404,
+			err:       "not found: INVALID",
+		},
+		{
+			name:      "JSON without error",
+			body:      `{"meta":{},"values":[]}`,
+			delimiter: ';',
+			err:       "unexpected JSON response",
+		},
+		{
+			name:      "invalid price",
+			body:      "datetime;open;high;low;close;volume\n2024-06-14;n/a;1;1;1;1\n",
+			delimiter: ';',
+			err:       `invalid open value "n/a"`,
+		},
+		{
+			name:      "invalid datetime",
+			body:      "datetime;open;high;low;close;volume\n14/06/2024;1;1;1;1;1\n",
+			delimiter: ';',
+			err:       "failed to parse datetime 14/06/2024",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			candles, err := twelvedata.NewTimeSeriesCSVDecoder(strings.NewReader(tt.body), tt.delimiter, tt.loc).DecodeAll()
+			switch {
+			case tt.code != 0:
+				expectAPIError(t, err, tt.code, tt.err)
+				return
+			case tt.err != "":
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("error = %v, want an error containing %q", err, tt.err)
+				}
+				return
+			case err != nil:
+				t.Fatal(err)
+			}
+
+			expect(t, "candles", candles, tt.want)
+			for i, candle := range candles {
+				want := "UTC"
+				if tt.loc != nil {
+					want = tt.loc.String()
+				}
+				expect(t, "location", candle.DateTime.Location().String(), want)
+				if !candle.DateTime.Equal(tt.want[i].DateTime.Time) {
+					t.Errorf("candle %d at %s, want %s", i, candle.DateTime, tt.want[i].DateTime)
+				}
+			}
+		})
+	}
+}
+
+func TestListCSVDecoders(t *testing.T) {
+	stocks, err := twelvedata.NewStocksCSVDecoder(strings.NewReader(
+		"symbol|name|currency|exchange|mic_code|country|type\n"+
+			"AAPL|Apple Inc|USD|NASDAQ|XNGS|United States|Common Stock\n"+
+			"BRK.A|\"Berkshire Hathaway|Inc\"|USD|NYSE|XNYS|United States|Common Stock\n",
+	), '|').DecodeAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect(t, "stocks", stocks, []twelvedata.Stocks{
+		{Symbol: "AAPL", Name: "Apple Inc", Currency: "USD", Exchange: "NASDAQ", MicCode: "XNGS", Country: "United States", Type: "Common Stock"},
+		{Symbol: "BRK.A", Name: "Berkshire Hathaway|Inc", Currency: "USD", Exchange: "NYSE", MicCode: "XNYS", Country: "United States", Type: "Common Stock"},
+	})
+
+	cryptos, err := twelvedata.NewCryptoCSVDecoder(strings.NewReader(
+		"symbol;available_exchanges;currency_base;currency_quote\n"+
+			"BTC/USD;[Binance, Coinbase Pro];Bitcoin;US Dollar\n"+
+			"XYZ/USD;[];XYZ;US Dollar\n",
+	), ';').DecodeAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect(t, "cryptos", cryptos, []twelvedata.Crypto{
+		{Symbol: "BTC/USD", AvailableExchanges: []string{"Binance", "Coinbase Pro"}, CurrencyBase: "Bitcoin", CurrencyQuote: "US Dollar"},
+		{Symbol: "XYZ/USD", CurrencyBase: "XYZ", CurrencyQuote: "US Dollar"},
+	})
+
+	decoder := twelvedata.NewStocksCSVDecoder(strings.NewReader(""), ';')
+	if _, err := decoder.Next(); err != io.EOF {
+		t.Errorf("Next() of an empty list error = %v, want io.EOF", err)
+	}
+}
+
+func TestCSVTimeSeriesPreserveDecimals(t *testing.T) {
+	client := handlerClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("format") != "CSV" {
+			// The lookup of the exchange timezone for the meta block
+			_, _ = w.Write([]byte(`{"meta":{"symbol":"EUR/USD","interval":"1day","exchange_timezone":"UTC"},"values":[],"status":"ok"}`))
+			return
+		}
+		_, _ = w.Write([]byte("datetime;open;high;low;close;volume\n2024-06-14;1.07000;1.07120;1.06680;1.07030;\n"))
+	}))
+
+	series, err := client.GetTimeSeries(twelvedata.TimeSeriesRequest{
+		Symbol:           ptr("EUR/USD"),
+		Interval:         ptr(twelvedata.TimeSeriesInterval1Day),
+		TimeZone:         ptr("UTC"),
+		Format:           ptr(twelvedata.ResponseFormatCSV),
+		PreserveDecimals: ptr(true),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect(t, "Open.Decimal", series.Candles[0].Open.Decimal, twelvedata.Decimal("1.07000"))
+	expect(t, "Close.Decimal", series.Candles[0].Close.Decimal, twelvedata.Decimal("1.07030"))
+	expect(t, "Volume", series.Candles[0].Volume, twelvedata.TDFloat{})
+}
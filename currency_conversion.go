@@ -0,0 +1,80 @@
+package twelvedata
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	urlEndpointCurrencyConversion = "/currency_conversion"
+)
+
+// CurrencyConversionRequest is the available parameters for a currency conversion request
+type CurrencyConversionRequest struct {
+	Symbol           *string    // Required: Currency pair (e.g. "EUR/USD", "BTC/USD")
+	Amount           *float64   // Required: Amount of the base currency to convert
+	Date             *time.Time // Convert at a past rate instead of the latest one
+	DP               *int       // Number of decimal places for float values. Supports 0-11, default is 5
+	TimeZone         *string    // Timezone for the response (e.g. "America/New_York", "UTC"). Defaults to "Exchange"
+	PreserveDecimals *bool      // Keep the exact decimals of the rate and amount in TDFloat.Decimal (defaults to Config.PreserveDecimals)
+}
+
+func (req CurrencyConversionRequest) ToParams() (map[string]string, error) {
+	params := make(map[string]string)
+
+	if req.Symbol == nil {
+		return nil, errors.New("symbol is required")
+	}
+
+	if req.Amount == nil {
+		return nil, errors.New("amount is required")
+	}
+
+	AddStringParam(params, "symbol", req.Symbol)
+	AddStringParam(params, "timezone", req.TimeZone)
+
+	AddFloatParam(params, "amount", req.Amount)
+
+	AddIntParam(params, "dp", req.DP)
+
+	AddDateParam(params, "date", req.Date, "2006-01-02 15:04:05")
+
+	return params, nil
+}
+
+type CurrencyConversion struct {
+	Symbol    string  `json:"symbol"`    // Currency pair (e.g. "EUR/USD")
+	Rate      TDFloat `json:"rate"`      // Units of the quote currency for one unit of the base currency
+	Amount    TDFloat `json:"amount"`    // Converted amount in the quote currency
+	Timestamp TDTime  `json:"timestamp"` // Time of the rate
+}
+
+// GetCurrencyConversion converts an amount of the base currency of a pair to its quote currency
+func (c *APIClient) GetCurrencyConversion(req CurrencyConversionRequest) (conversion *CurrencyConversion, err error) {
+	params, err := req.ToParams()
+	if err != nil {
+		return nil, errors.Wrap(err, "Error converting CurrencyConversionRequest to params")
+	}
+
+	data, err := c.Client.Get(urlEndpointCurrencyConversion, params)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error fetching currency conversion data")
+	}
+
+	err = c.Client.unmarshal(c.jsonAPI(req.PreserveDecimals), data, &conversion, "currency conversion")
+	if err != nil {
+		return nil, err
+	}
+
+	if conversion != nil {
+		loc, err := resolveLocation(req.TimeZone, "")
+		if err != nil {
+			return nil, errors.Wrap(err, "Error localizing currency conversion response")
+		}
+
+		conversion.Timestamp = conversion.Timestamp.Localize(loc)
+	}
+
+	return conversion, nil
+}
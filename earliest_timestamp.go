@@ -0,0 +1,117 @@
+package twelvedata
+
+import (
+	"context"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+)
+
+const (
+	urlEndpointEarliestTimestamp = "/earliest_timestamp"
+)
+
+// EarliestTimestampRequest is the available parameters for an earliest timestamp request
+type EarliestTimestampRequest struct {
+	Symbol   *string             // Required: Symbol of the asset (e.g. "AAPL", "BTC/USD")
+	FIGI     *string             // Financial Instrument Global Identifier
+	ISIN     *string             // International Securities Identification Number
+	CUSIP    *string             // Committee on Uniform Securities Identification Procedures
+	Interval *TimeSeriesInterval // Required: Time interval of the candles (e.g. "1min", "1day")
+	Exchange *string             // Exchange code (e.g. "NASDAQ", "Binance")
+	MicCode  *string             // Market Identifier Code (e.g. "XNAS" for NASDAQ)
+	TimeZone *string             // Timezone for the response (e.g. "America/New_York", "UTC"). Defaults to "Exchange"
+}
+
+func (req EarliestTimestampRequest) ToParams() (map[string]string, error) {
+	params := make(map[string]string)
+
+	if req.Symbol == nil {
+		return nil, errors.New("symbol is required")
+	}
+
+	if req.Interval == nil {
+		return nil, errors.New("interval is required")
+	}
+	params["interval"] = string(*req.Interval)
+
+	AddStringParam(params, "symbol", req.Symbol)
+	AddStringParam(params, "figi", req.FIGI)
+	AddStringParam(params, "isin", req.ISIN)
+	AddStringParam(params, "cusip", req.CUSIP)
+	AddStringParam(params, "exchange", req.Exchange)
+	AddStringParam(params, "mic_code", req.MicCode)
+	AddStringParam(params, "timezone", req.TimeZone)
+
+	return params, nil
+}
+
+type earliestTimestampResponse struct {
+	DateTime string `json:"datetime"`  // Wall clock time or date of the earliest bar in the response timezone
+	UnixTime int64  `json:"unix_time"` // Unix timestamp of the earliest bar
+}
+
+// toTime returns the unix timestamp in loc
+func (r earliestTimestampResponse) toTime(loc *time.Location) time.Time {
+	return time.Unix(r.UnixTime, 0).In(loc)
+}
+
+// GetEarliestTimestamp returns the time of the first available bar for the symbol and interval. The result is in
+// req.TimeZone when set to an IANA name, otherwise in the exchange timezone of the listing.
+//
+// An exchange timezone missing from the cache is looked up with a single candle time series request, which costs one
+// more API credit.
+func (c *APIClient) GetEarliestTimestamp(req EarliestTimestampRequest) (earliest time.Time, err error) {
+	return c.getEarliestTimestamp(context.Background(), req)
+}
+
+// getEarliestTimestamp is GetEarliestTimestamp with a context cancelling the requests
+func (c *APIClient) getEarliestTimestamp(ctx context.Context, req EarliestTimestampRequest) (earliest time.Time, err error) {
+	params, err := req.ToParams()
+	if err != nil {
+		return time.Time{}, errors.Wrap(err, "Error converting EarliestTimestampRequest to params")
+	}
+
+	data, err := c.Client.getContext(ctx, urlEndpointEarliestTimestamp, params)
+	if err != nil {
+		return time.Time{}, errors.Wrap(err, "Error fetching earliest timestamp data")
+	}
+
+	var resp earliestTimestampResponse
+	err = c.Client.decode(data, "earliest timestamp", func(body []byte) error {
+		if err := jsoniter.Unmarshal(body, &resp); err != nil {
+			return err
+		}
+
+		_, err := parseInLocation(resp.DateTime, time.UTC)
+		return err
+	})
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	loc, err := c.earliestTimestampLocation(ctx, req)
+	if err != nil {
+		return time.Time{}, errors.Wrap(err, "Error resolving timezone for earliest timestamp")
+	}
+
+	return resp.toTime(loc), nil
+}
+
+// earliestTimestampLocation resolves the timezone of an earliest timestamp like the one of a time series, see
+// timeSeriesLocation
+func (c *APIClient) earliestTimestampLocation(ctx context.Context, req EarliestTimestampRequest) (*time.Location, error) {
+	series := TimeSeriesRequest{
+		Symbol:   req.Symbol,
+		FIGI:     req.FIGI,
+		ISIN:     req.ISIN,
+		CUSIP:    req.CUSIP,
+		Interval: req.Interval,
+		Exchange: req.Exchange,
+		MicCode:  req.MicCode,
+		TimeZone: req.TimeZone,
+	}
+
+	return c.timeSeriesLocation(ctx, series)
+}
@@ -0,0 +1,123 @@
+package twelvedata_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jonnotjohn/twelvedata-go"
+)
+
+// earliestTimestampServer answers earliest timestamp requests with body and counts the time series lookups
+func earliestTimestampServer(t *testing.T, body string, lookups *int) *twelvedata.APIClient {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/earliest_timestamp", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(body))
+	})
+	mux.HandleFunc("/time_series", func(w http.ResponseWriter, r *http.Request) {
+		*lookups++
+		w.Write([]byte(`{"meta":{"symbol":"AAPL","interval":"1day","exchange_timezone":"America/New_York"},` +
+			`"values":[{"datetime":"2024-01-02","open":"1","high":"1","low":"1","close":"1","volume":"1"}],"status":"ok"}`))
+	})
+
+	return handlerClient(t, mux)
+}
+
+func TestGetEarliestTimestamp(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		interval twelvedata.TimeSeriesInterval
+		timeZone string
+		want     time.Time
+		zone     string
+		lookups  int
+	}{
+		{
+			name:     "date in exchange timezone",
+			body:     `{"datetime":"1980-12-12","unix_time":345479400}`,
+			interval: twelvedata.TimeSeriesInterval1Day,
+			want:     time.Date(1980, 12, 12, 9, 30, 0, 0, newYork),
+			zone:     "America/New_York",
+			lookups:  1,
+		},
+		{
+			name:     "date in request timezone",
+			body:     `{"datetime":"1980-12-12","unix_time":345479400}`,
+			interval: twelvedata.TimeSeriesInterval1Day,
+			timeZone: "UTC",
+			want:     time.Date(1980, 12, 12, 14, 30, 0, 0, time.UTC),
+			zone:     "UTC",
+		},
+		{
+			name:     "wall clock in exchange timezone",
+			body:     `{"datetime":"2020-02-10 04:00:00","unix_time":1581325200}`,
+			interval: twelvedata.TimeSeriesInterval1Min,
+			want:     time.Date(2020, 2, 10, 4, 0, 0, 0, newYork),
+			zone:     "America/New_York",
+			lookups:  1,
+		},
+		{
+			name:     "wall clock in request timezone",
+			body:     `{"datetime":"2020-02-10 18:00:00","unix_time":1581325200}`,
+			interval: twelvedata.TimeSeriesInterval1Min,
+			timeZone: "Asia/Tokyo",
+			want:     time.Date(2020, 2, 10, 18, 0, 0, 0, mustLoadLocation("Asia/Tokyo")),
+			zone:     "Asia/Tokyo",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lookups := 0
+			client := earliestTimestampServer(t, tt.body, &lookups)
+
+			symbol := "AAPL"
+			req := twelvedata.EarliestTimestampRequest{Symbol: &symbol, Interval: &tt.interval}
+			if tt.timeZone != "" {
+				req.TimeZone = &tt.timeZone
+			}
+
+			got, err := client.GetEarliestTimestamp(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !got.Equal(tt.want) {
+				t.Errorf("GetEarliestTimestamp() = %v, want %v", got, tt.want)
+			}
+
+			if tt.zone != "" && got.Location().String() != tt.zone {
+				t.Errorf("GetEarliestTimestamp() location = %s, want %s", got.Location(), tt.zone)
+			}
+
+			if got.Format(time.DateTime) != tt.want.Format(time.DateTime) {
+				t.Errorf("GetEarliestTimestamp() wall clock = %s, want %s", got.Format(time.DateTime), tt.want.Format(time.DateTime))
+			}
+
+			if lookups != tt.lookups {
+				t.Errorf("time series lookups = %d, want %d", lookups, tt.lookups)
+			}
+		})
+	}
+}
+
+func TestGetEarliestTimestampCachesExchangeTimezone(t *testing.T) {
+	lookups := 0
+	client := earliestTimestampServer(t, `{"datetime":"1980-12-12","unix_time":345479400}`, &lookups)
+
+	symbol := "AAPL"
+	interval := twelvedata.TimeSeriesInterval1Day
+	for range 2 {
+		_, err := client.GetEarliestTimestamp(twelvedata.EarliestTimestampRequest{Symbol: &symbol, Interval: &interval})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if lookups != 1 {
+		t.Errorf("time series lookups = %d, want 1", lookups)
+	}
+}
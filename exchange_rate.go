@@ -0,0 +1,72 @@
+package twelvedata
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	urlEndpointExchangeRate = "/exchange_rate"
+)
+
+// ExchangeRateRequest is the available parameters for an exchange rate request
+type ExchangeRateRequest struct {
+	Symbol           *string    // Required: Currency pair (e.g. "EUR/USD", "BTC/USD")
+	Date             *time.Time // Rate at a past time instead of the latest one
+	DP               *int       // Number of decimal places for float values. Supports 0-11, default is 5
+	TimeZone         *string    // Timezone for the response (e.g. "America/New_York", "UTC"). Defaults to "Exchange"
+	PreserveDecimals *bool      // Keep the exact decimals of the rate in TDFloat.Decimal (defaults to Config.PreserveDecimals)
+}
+
+func (req ExchangeRateRequest) ToParams() (map[string]string, error) {
+	params := make(map[string]string)
+
+	if req.Symbol == nil {
+		return nil, errors.New("symbol is required")
+	}
+
+	AddStringParam(params, "symbol", req.Symbol)
+	AddStringParam(params, "timezone", req.TimeZone)
+
+	AddIntParam(params, "dp", req.DP)
+
+	AddDateParam(params, "date", req.Date, "2006-01-02 15:04:05")
+
+	return params, nil
+}
+
+type ExchangeRate struct {
+	Symbol    string  `json:"symbol"`    // Currency pair (e.g. "EUR/USD")
+	Rate      TDFloat `json:"rate"`      // Units of the quote currency for one unit of the base currency
+	Timestamp TDTime  `json:"timestamp"` // Time of the rate
+}
+
+// GetExchangeRate returns the real-time or historical rate of a currency pair
+func (c *APIClient) GetExchangeRate(req ExchangeRateRequest) (exchangeRate *ExchangeRate, err error) {
+	params, err := req.ToParams()
+	if err != nil {
+		return nil, errors.Wrap(err, "Error converting ExchangeRateRequest to params")
+	}
+
+	data, err := c.Client.Get(urlEndpointExchangeRate, params)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error fetching exchange rate data")
+	}
+
+	err = c.Client.unmarshal(c.jsonAPI(req.PreserveDecimals), data, &exchangeRate, "exchange rate")
+	if err != nil {
+		return nil, err
+	}
+
+	if exchangeRate != nil {
+		loc, err := resolveLocation(req.TimeZone, "")
+		if err != nil {
+			return nil, errors.Wrap(err, "Error localizing exchange rate response")
+		}
+
+		exchangeRate.Timestamp = exchangeRate.Timestamp.Localize(loc)
+	}
+
+	return exchangeRate, nil
+}
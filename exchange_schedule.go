@@ -0,0 +1,79 @@
+package twelvedata
+
+import (
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+)
+
+const (
+	urlEndpointExchangeSchedule = "/exchange_schedule"
+)
+
+type ExchangeSessionType string
+
+const (
+	ExchangeSessionTypePre  ExchangeSessionType = "pre"
+	ExchangeSessionTypeCore ExchangeSessionType = "core"
+	ExchangeSessionTypePost ExchangeSessionType = "post"
+)
+
+// ExchangeScheduleRequest is the available parameters for an exchange schedule request
+type ExchangeScheduleRequest struct {
+	MicName *string    // Exchange name (e.g. "NASDAQ")
+	MicCode *string    // Market Identifier Code (e.g. "XNGS")
+	Country *string    // Country code (e.g. "US" or "United States")
+	Date    *time.Time // Day to return the schedule for (time is ignored). Defaults to the regular schedule
+}
+
+func (req ExchangeScheduleRequest) ToParams() (map[string]string, error) {
+	params := make(map[string]string)
+
+	AddStringParam(params, "mic_name", req.MicName)
+	AddStringParam(params, "mic_code", req.MicCode)
+	AddStringParam(params, "country", req.Country)
+
+	AddDateParam(params, "date", req.Date, "2006-01-02")
+
+	return params, nil
+}
+
+type ExchangeSession struct {
+	OpenTime    TDDuration          `json:"open_time"`    // Session open as an offset from midnight in the exchange timezone
+	CloseTime   TDDuration          `json:"close_time"`   // Session close as an offset from midnight in the exchange timezone
+	SessionName string              `json:"session_name"` // Name of the session (e.g. "Core session")
+	SessionType ExchangeSessionType `json:"session_type"` // Type of the session ("pre", "core" or "post")
+}
+
+type ExchangeSchedule struct {
+	Title    string            `json:"title"`     // Full name of the exchange (e.g. "NASDAQ/NGS (Global Select Market)")
+	Name     string            `json:"name"`      // Exchange name (e.g. "NASDAQ")
+	Code     string            `json:"code"`      // Market Identifier Code (e.g. "XNGS")
+	Country  string            `json:"country"`   // Country of the exchange (e.g. "United States")
+	TimeZone string            `json:"time_zone"` // Timezone of the exchange (e.g. "America/New_York")
+	Sessions []ExchangeSession `json:"sessions"`  // Trading sessions of the day. Empty when the exchange is closed
+}
+
+type ExchangeScheduleResponse struct {
+	Data []ExchangeSchedule `json:"data"`
+}
+
+func (c *APIClient) GetExchangeSchedule(req ExchangeScheduleRequest) (exchangeSchedule *ExchangeScheduleResponse, err error) {
+	params, err := req.ToParams()
+	if err != nil {
+		return nil, errors.Wrap(err, "Error converting ExchangeScheduleRequest to params")
+	}
+
+	data, err := c.Client.Get(urlEndpointExchangeSchedule, params)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error fetching exchange schedule data")
+	}
+
+	err = c.Client.unmarshal(jsoniter.ConfigDefault, data, &exchangeSchedule, "exchange schedule")
+	if err != nil {
+		return nil, err
+	}
+
+	return exchangeSchedule, nil
+}
@@ -0,0 +1,309 @@
+package twelvedata
+
+import (
+	"bufio"
+	"encoding/csv"
+	"io"
+	"iter"
+	"strings"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+)
+
+// ExportColumns are the candle columns written by the exporters, in order
+var ExportColumns = []string{"datetime", "open", "high", "low", "close", "volume"}
+
+// exportMetaFields lists the TimeSeriesResponseMeta fields written as file-level metadata, in order
+var exportMetaFields = []struct {
+	key   string
+	value func(m *TimeSeriesResponseMeta) *string
+}{
+	{"symbol", func(m *TimeSeriesResponseMeta) *string { return &m.Symbol }},
+	{"interval", func(m *TimeSeriesResponseMeta) *string { return &m.Interval }},
+	{"currency", func(m *TimeSeriesResponseMeta) *string { return &m.Currency }},
+	{"currency_base", func(m *TimeSeriesResponseMeta) *string { return &m.CurrencyBase }},
+	{"currency_quote", func(m *TimeSeriesResponseMeta) *string { return &m.CurrencyQuote }},
+	{"exchange_timezone", func(m *TimeSeriesResponseMeta) *string { return &m.ExchangeTimezone }},
+	{"exchange", func(m *TimeSeriesResponseMeta) *string { return &m.Exchange }},
+	{"mic_code", func(m *TimeSeriesResponseMeta) *string { return &m.MicCode }},
+	{"type", func(m *TimeSeriesResponseMeta) *string { return &m.Type }},
+}
+
+// MetaToMap returns the meta fields keyed by their JSON names, for formats with key-value file metadata
+func MetaToMap(meta TimeSeriesResponseMeta) map[string]string {
+	values := make(map[string]string, len(exportMetaFields))
+	for _, field := range exportMetaFields {
+		values[field.key] = *field.value(&meta)
+	}
+
+	return values
+}
+
+// MetaFields returns the JSON names and values of the meta fields in a fixed order, for formats with ordered key-value
+// file metadata, so their output is reproducible
+func MetaFields(meta TimeSeriesResponseMeta) (keys, values []string) {
+	keys = make([]string, 0, len(exportMetaFields))
+	values = make([]string, 0, len(exportMetaFields))
+	for _, field := range exportMetaFields {
+		keys = append(keys, field.key)
+		values = append(values, *field.value(&meta))
+	}
+
+	return keys, values
+}
+
+// MetaFromMap is the inverse of MetaToMap. Unknown keys are ignored.
+func MetaFromMap(values map[string]string) TimeSeriesResponseMeta {
+	var meta TimeSeriesResponseMeta
+	for _, field := range exportMetaFields {
+		*field.value(&meta) = values[field.key]
+	}
+
+	return meta
+}
+
+// Location returns the exchange timezone of the meta block, or UTC when it is empty
+func (m TimeSeriesResponseMeta) Location() (*time.Location, error) {
+	if m.ExchangeTimezone == "" {
+		return time.UTC, nil
+	}
+
+	loc, err := time.LoadLocation(m.ExchangeTimezone)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load exchange timezone")
+	}
+
+	return loc, nil
+}
+
+// Seq returns an iterator over the response's candles
+func (r *TimeSeriesResponse) Seq() iter.Seq2[TimeSeriesCandle, error] {
+	return func(yield func(TimeSeriesCandle, error) bool) {
+		for _, candle := range r.Candles {
+			if !yield(candle, nil) {
+				return
+			}
+		}
+	}
+}
+
+func formatExportFloat(f TDFloat) string {
+	return f.String()
+}
+
+// WriteCSV writes the response as CSV, see WriteCandlesCSV
+func (r *TimeSeriesResponse) WriteCSV(w io.Writer) error {
+	return WriteCandlesCSV(w, r.Meta, r.Seq())
+}
+
+// WriteCandlesCSV writes candles as comma separated values with ExportColumns as header. The meta fields are
+// written first as "# key=value" comment lines (read them with comment="#" in pandas and DuckDB). Datetimes are
+// RFC 3339 with the UTC offset of the exchange timezone.
+func WriteCandlesCSV(w io.Writer, meta TimeSeriesResponseMeta, candles iter.Seq2[TimeSeriesCandle, error]) error {
+	bw := bufio.NewWriter(w)
+	for _, field := range exportMetaFields {
+		if _, err := bw.WriteString("# " + field.key + "=" + *field.value(&meta) + "\n"); err != nil {
+			return errors.Wrap(err, "Error writing CSV metadata")
+		}
+	}
+
+	cw := csv.NewWriter(bw)
+	if err := cw.Write(ExportColumns); err != nil {
+		return errors.Wrap(err, "Error writing CSV header")
+	}
+
+	for candle, err := range candles {
+		if err != nil {
+			return errors.Wrap(err, "Error reading candles to export")
+		}
+
+		record := []string{
+			candle.DateTime.Format(time.RFC3339),
+			formatExportFloat(candle.Open),
+			formatExportFloat(candle.High),
+			formatExportFloat(candle.Low),
+			formatExportFloat(candle.Close),
+			formatExportFloat(candle.Volume),
+		}
+
+		if err := cw.Write(record); err != nil {
+			return errors.Wrap(err, "Error writing CSV record")
+		}
+	}
+
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return errors.Wrap(err, "Error writing CSV")
+	}
+
+	return errors.Wrap(bw.Flush(), "Error writing CSV")
+}
+
+// ReadTimeSeriesCSV reads a file written by WriteCandlesCSV back into a response, with datetimes in the exchange
+// timezone
+func ReadTimeSeriesCSV(r io.Reader) (*TimeSeriesResponse, error) {
+	br := bufio.NewReader(r)
+
+	values := make(map[string]string)
+	for {
+		peek, err := br.Peek(1)
+		if err != nil || peek[0] != '#' {
+			break
+		}
+
+		line, err := br.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return nil, errors.Wrap(err, "Error reading CSV metadata")
+		}
+
+		key, value, _ := strings.Cut(strings.TrimSpace(strings.TrimPrefix(line, "#")), "=")
+		values[strings.TrimSpace(key)] = value
+	}
+
+	resp := &TimeSeriesResponse{Meta: MetaFromMap(values)}
+	loc, err := resp.Meta.Location()
+	if err != nil {
+		return nil, err
+	}
+
+	records := newCSVRecordReader(br, ',')
+	for {
+		err := records.next()
+		if err == io.EOF {
+			return resp, nil
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		parsedTime, err := time.Parse(time.RFC3339, records.field("datetime"))
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid datetime in CSV candle %d", len(resp.Candles))
+		}
+
+		candle := TimeSeriesCandle{DateTime: TDZonedTime{Time: parsedTime.In(loc)}}
+		for _, column := range []struct {
+			name  string
+			value *TDFloat
+		}{
+			{"open", &candle.Open},
+			{"high", &candle.High},
+			{"low", &candle.Low},
+			{"close", &candle.Close},
+			{"volume", &candle.Volume},
+		} {
+			if *column.value, err = records.float(column.name); err != nil {
+				return nil, errors.Wrapf(err, "invalid CSV candle %d", len(resp.Candles))
+			}
+		}
+
+		resp.Candles = append(resp.Candles, candle)
+	}
+}
+
+// ndjsonCandle is the line format of exported candles
+type ndjsonCandle struct {
+	DateTime string   `json:"datetime"` // RFC 3339 with the UTC offset of the exchange timezone
+	Open     *float64 `json:"open"`     // Values not provided by the API are null
+	High     *float64 `json:"high"`
+	Low      *float64 `json:"low"`
+	Close    *float64 `json:"close"`
+	Volume   *float64 `json:"volume"`
+}
+
+// ndjsonLine is a line of an exported NDJSON file: the first line carries the meta block, the others a candle
+type ndjsonLine struct {
+	Meta *TimeSeriesResponseMeta `json:"meta,omitempty"`
+	ndjsonCandle
+}
+
+// WriteNDJSON writes the response as JSON Lines, see WriteCandlesNDJSON
+func (r *TimeSeriesResponse) WriteNDJSON(w io.Writer) error {
+	return WriteCandlesNDJSON(w, r.Meta, r.Seq())
+}
+
+// WriteCandlesNDJSON writes candles as newline delimited JSON. The first line is {"meta": {...}} and every following
+// line is one candle with the ExportColumns keys. Datetimes are RFC 3339 with the UTC offset of the exchange timezone.
+func WriteCandlesNDJSON(w io.Writer, meta TimeSeriesResponseMeta, candles iter.Seq2[TimeSeriesCandle, error]) error {
+	bw := bufio.NewWriter(w)
+	stream := jsoniter.NewStream(jsoniter.ConfigCompatibleWithStandardLibrary, bw, 4096)
+
+	stream.WriteVal(map[string]TimeSeriesResponseMeta{"meta": meta})
+	stream.WriteRaw("\n")
+
+	for candle, err := range candles {
+		if err != nil {
+			return errors.Wrap(err, "Error reading candles to export")
+		}
+
+		stream.WriteVal(ndjsonCandle{
+			DateTime: candle.DateTime.Format(time.RFC3339),
+			Open:     candle.Open.Ptr(),
+			High:     candle.High.Ptr(),
+			Low:      candle.Low.Ptr(),
+			Close:    candle.Close.Ptr(),
+			Volume:   candle.Volume.Ptr(),
+		})
+		stream.WriteRaw("\n")
+
+		if stream.Error != nil {
+			return errors.Wrap(stream.Error, "Error writing NDJSON")
+		}
+
+		if stream.Buffered() > 4096 {
+			if err := stream.Flush(); err != nil {
+				return errors.Wrap(err, "Error writing NDJSON")
+			}
+		}
+	}
+
+	if err := stream.Flush(); err != nil {
+		return errors.Wrap(err, "Error writing NDJSON")
+	}
+
+	return errors.Wrap(bw.Flush(), "Error writing NDJSON")
+}
+
+// ReadTimeSeriesNDJSON reads a file written by WriteCandlesNDJSON back into a response, with datetimes in the
+// exchange timezone
+func ReadTimeSeriesNDJSON(r io.Reader) (*TimeSeriesResponse, error) {
+	decoder := jsoniter.ConfigCompatibleWithStandardLibrary.NewDecoder(r)
+
+	resp := &TimeSeriesResponse{}
+	loc := time.UTC
+	for decoder.More() {
+		var line ndjsonLine
+		if err := decoder.Decode(&line); err != nil {
+			return nil, errors.Wrapf(err, "Error decoding NDJSON line %d", len(resp.Candles)+1)
+		}
+
+		if line.Meta != nil {
+			resp.Meta = *line.Meta
+
+			var err error
+			if loc, err = resp.Meta.Location(); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		parsedTime, err := time.Parse(time.RFC3339, line.DateTime)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid datetime in NDJSON candle %d", len(resp.Candles))
+		}
+
+		resp.Candles = append(resp.Candles, TimeSeriesCandle{
+			DateTime: TDZonedTime{Time: parsedTime.In(loc)},
+			Open:     tdFloatFromPtr(line.Open),
+			High:     tdFloatFromPtr(line.High),
+			Low:      tdFloatFromPtr(line.Low),
+			Close:    tdFloatFromPtr(line.Close),
+			Volume:   tdFloatFromPtr(line.Volume),
+		})
+	}
+
+	return resp, nil
+}
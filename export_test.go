@@ -0,0 +1,177 @@
+package twelvedata_test
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jonnotjohn/twelvedata-go"
+)
+
+// exportSeries is an intraday series across the start of daylight saving time, with a candle without volume
+func exportSeries() *twelvedata.TimeSeriesResponse {
+	candle := func(wallClock string, price float64, volume twelvedata.TDFloat) twelvedata.TimeSeriesCandle {
+		return twelvedata.TimeSeriesCandle{
+			DateTime: twelvedata.TDZonedTime{Time: at(wallClock)},
+			Open:     twelvedata.NewTDFloat(price),
+			High:     twelvedata.NewTDFloat(price + 0.5),
+			Low:      twelvedata.NewTDFloat(price - 0.25),
+			Close:    twelvedata.NewTDFloat(price + 0.125),
+			Volume:   volume,
+		}
+	}
+
+	return &twelvedata.TimeSeriesResponse{
+		Meta: twelvedata.TimeSeriesResponseMeta{
+			Symbol:           "AAPL",
+			Interval:         "1h",
+			Currency:         "USD",
+			ExchangeTimezone: "America/New_York",
+			Exchange:         "NASDAQ",
+			MicCode:          "XNGS",
+			Type:             "Common Stock",
+		},
+		Candles: []twelvedata.TimeSeriesCandle{
+			candle("2024-03-08 15:30", 170.73, twelvedata.NewTDFloat(4512200)),
+			candle("2024-03-11 09:30", 172.94, twelvedata.TDFloat{}),
+			candle("2024-03-11 10:30", 173.1, twelvedata.NewTDFloat(0)),
+		},
+	}
+}
+
+// checkRoundTrip compares a series read back from an export with the exported one
+func checkRoundTrip(t *testing.T, got, want *twelvedata.TimeSeriesResponse) {
+	t.Helper()
+
+	expect(t, "Meta", got.Meta, want.Meta)
+	if len(got.Candles) != len(want.Candles) {
+		t.Fatalf("candles = %d, want %d", len(got.Candles), len(want.Candles))
+	}
+
+	for i, candle := range got.Candles {
+		if !candle.DateTime.Equal(want.Candles[i].DateTime.Time) {
+			t.Errorf("candle %d at %s, want %s", i, candle.DateTime, want.Candles[i].DateTime)
+		}
+		expect(t, "location", candle.DateTime.Location().String(), "America/New_York")
+
+		candle.DateTime = want.Candles[i].DateTime
+		expect(t, "candle", candle, want.Candles[i])
+	}
+}
+
+func TestCSVExportRoundTrip(t *testing.T) {
+	series := exportSeries()
+
+	var buf bytes.Buffer
+	if err := series.WriteCSV(&buf); err != nil {
+		t.Fatal(err)
+	}
+	written := buf.String()
+
+	read, err := twelvedata.ReadTimeSeriesCSV(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkRoundTrip(t, read, series)
+
+	// The metadata comes first in a fixed order, followed by the candles with the UTC offset of the time of year
+	expect(t, "lines", strings.Split(strings.TrimSpace(written), "\n"), []string{
+		"# symbol=AAPL",
+		"# interval=1h",
+		"# currency=USD",
+		"# currency_base=",
+		"# currency_quote=",
+		"# exchange_timezone=America/New_York",
+		"# exchange=NASDAQ",
+		"# mic_code=XNGS",
+		"# type=Common Stock",
+		"datetime,open,high,low,close,volume",
+		"2024-03-08T15:30:00-05:00,170.73,171.23,170.48,170.855,4512200",
+		"2024-03-11T09:30:00-04:00,172.94,173.44,172.69,173.065,",
+		"2024-03-11T10:30:00-04:00,173.1,173.6,172.85,173.225,0",
+	})
+}
+
+func TestNDJSONExportRoundTrip(t *testing.T) {
+	series := exportSeries()
+
+	var buf bytes.Buffer
+	if err := series.WriteNDJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	var lines []string
+	for scanner := bufio.NewScanner(bytes.NewReader(buf.Bytes())); scanner.Scan(); {
+		lines = append(lines, scanner.Text())
+	}
+
+	read, err := twelvedata.ReadTimeSeriesNDJSON(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkRoundTrip(t, read, series)
+
+	expect(t, "lines", lines, []string{
+		`{"meta":{"symbol":"AAPL","interval":"1h","currency":"USD","currency_base":"","currency_quote":"",` +
+			`"exchange_timezone":"America/New_York","exchange":"NASDAQ","mic_code":"XNGS","type":"Common Stock"}}`,
+		`{"datetime":"2024-03-08T15:30:00-05:00","open":170.73,"high":171.23,"low":170.48,"close":170.855,"volume":4512200}`,
+		`{"datetime":"2024-03-11T09:30:00-04:00","open":172.94,"high":173.44,"low":172.69,"close":173.065,"volume":null}`,
+		`{"datetime":"2024-03-11T10:30:00-04:00","open":173.1,"high":173.6,"low":172.85,"close":173.225,"volume":0}`,
+	})
+}
+
+func TestExportWithoutExchangeTimezone(t *testing.T) {
+	series := &twelvedata.TimeSeriesResponse{
+		Meta:    twelvedata.TimeSeriesResponseMeta{Symbol: "EUR/USD", Interval: "1day"},
+		Candles: []twelvedata.TimeSeriesCandle{{DateTime: twelvedata.TDZonedTime{Time: time.Date(2024, 6, 14, 0, 0, 0, 0, time.UTC)}}},
+	}
+
+	formats := map[string]struct {
+		write func(*bytes.Buffer) error
+		read  func(*bytes.Buffer) (*twelvedata.TimeSeriesResponse, error)
+	}{
+		"CSV": {
+			write: func(buf *bytes.Buffer) error { return series.WriteCSV(buf) },
+			read: func(buf *bytes.Buffer) (*twelvedata.TimeSeriesResponse, error) {
+				return twelvedata.ReadTimeSeriesCSV(buf)
+			},
+		},
+		"NDJSON": {
+			write: func(buf *bytes.Buffer) error { return series.WriteNDJSON(buf) },
+			read: func(buf *bytes.Buffer) (*twelvedata.TimeSeriesResponse, error) {
+				return twelvedata.ReadTimeSeriesNDJSON(buf)
+			},
+		},
+	}
+
+	for name, format := range formats {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := format.write(&buf); err != nil {
+				t.Fatal(err)
+			}
+
+			read, err := format.read(&buf)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			expect(t, "DateTime", read.Candles[0].DateTime.Time, series.Candles[0].DateTime.Time)
+			expect(t, "Close.Valid", read.Candles[0].Close.Valid, false)
+		})
+	}
+}
+
+func TestMetaFieldsOrder(t *testing.T) {
+	meta := exportSeries().Meta
+
+	keys, values := twelvedata.MetaFields(meta)
+	expect(t, "keys", keys, []string{
+		"symbol", "interval", "currency", "currency_base", "currency_quote", "exchange_timezone", "exchange", "mic_code", "type",
+	})
+	expect(t, "values", values, []string{"AAPL", "1h", "USD", "", "", "America/New_York", "NASDAQ", "XNGS", "Common Stock"})
+
+	expect(t, "MetaFromMap(MetaToMap())", twelvedata.MetaFromMap(twelvedata.MetaToMap(meta)), meta)
+}
@@ -0,0 +1,572 @@
+package twelvedata
+
+import (
+	"context"
+	"iter"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+)
+
+const (
+	// maxBatchSize is the most symbols the API accepts in one batch request
+	maxBatchSize = 120
+
+	// fetchConcurrencyDefault is the default number of FetchMany requests in flight
+	fetchConcurrencyDefault = 4
+
+	// fetchRateLimitWaits is how often a batch answered with 429 is sent again in the next minute
+	fetchRateLimitWaits = 3
+)
+
+// FetchRequest is one request of FetchMany. Exactly one of the fields must be set.
+type FetchRequest struct {
+	Quote      *QuoteRequest
+	TimeSeries *TimeSeriesRequest
+	Price      *PriceRequest
+}
+
+// Symbol returns the symbol of the request, or "" when it has none
+func (r FetchRequest) Symbol() string {
+	var symbol *string
+	switch {
+	case r.Quote != nil:
+		symbol = r.Quote.Symbol
+	case r.TimeSeries != nil:
+		symbol = r.TimeSeries.Symbol
+	case r.Price != nil:
+		symbol = r.Price.Symbol
+	}
+
+	if symbol == nil {
+		return ""
+	}
+
+	return *symbol
+}
+
+// FetchOptions configures FetchMany. The zero value is usable.
+type FetchOptions struct {
+	Concurrency int                 // Most requests in flight at once (default 4)
+	BatchSize   int                 // Most symbols per batch request (default and maximum 120). 1 disables batching
+	Budget      *CreditBudget       // Credits to stay within, which bounds BatchSize too. Nil doesn't pace requests
+	Progress    func(FetchProgress) // Called after each result, from the goroutine iterating the results
+}
+
+// FetchProgress counts the results of FetchMany so far
+type FetchProgress struct {
+	Total   int // Number of requests
+	Done    int // Requests with a result, including failed ones
+	Failed  int // Requests that failed
+	Credits int // Credits spent so far, including the quote timezone lookups of Config.QuoteTimezones (one per listing)
+}
+
+// FetchResult is the outcome of one request of FetchMany
+type FetchResult struct {
+	Index      int // Position of the request in the requests passed to FetchMany
+	Request    FetchRequest
+	Quote      *Quote              // Response of a quote request
+	TimeSeries *TimeSeriesResponse // Response of a time series request
+	Price      *Price              // Response of a price request
+	Err        error
+}
+
+// fetchUnit is a group of FetchMany requests sent with one request
+type fetchUnit struct {
+	endpoint string
+	params   map[string]string // Params of the batch request, without the symbol
+	indexes  []int
+	batch    bool // Sent as a batch request, otherwise indexes holds one request sent with GetTimeSeriesContext
+}
+
+// fetchUnitResults are the results of a fetchUnit with the credits it spent
+type fetchUnitResults struct {
+	results []FetchResult
+	credits int
+}
+
+// FetchMany sends many quote, time series and price requests and returns their results in the order they complete.
+//
+// Requests for different symbols with otherwise equal parameters are grouped into batch requests of up to
+// opts.BatchSize symbols, which cost one credit per symbol. Requests with a FIGI, ISIN or CUSIP are sent alone, CSV
+// time series and time series from the earliest timestamp with GetTimeSeriesContext. At most opts.Concurrency requests are in
+// flight and with opts.Budget set, every request first waits for its credits. A batch answered with 429 is sent again
+// in the next minute.
+//
+// Every request gets exactly one result. Failures (an unknown symbol, an error of the API, the cancellation of ctx) are
+// reported in FetchResult.Err without failing the other requests. Breaking out of the loop cancels the requests in
+// flight.
+//
+// Quotes are localized like in GetQuote. With Config.QuoteTimezones, the exchange timezones missing from the cache are
+// looked up with a batch of single candle time series requests, one credit per listing, counted in
+// FetchProgress.Credits.
+func (c *APIClient) FetchMany(ctx context.Context, requests []FetchRequest, opts FetchOptions) iter.Seq[FetchResult] {
+	return func(yield func(FetchResult) bool) {
+		ctx, cancel := context.WithCancel(ctx)
+		stop := make(chan struct{})
+		defer func() {
+			close(stop)
+			cancel()
+		}()
+
+		concurrency := opts.Concurrency
+		if concurrency < 1 {
+			concurrency = fetchConcurrencyDefault
+		}
+
+		batchSize := opts.BatchSize
+		if batchSize < 1 || batchSize > maxBatchSize {
+			batchSize = maxBatchSize
+		}
+		if opts.Budget != nil {
+			batchSize = min(batchSize, opts.Budget.PerMinute())
+		}
+
+		progress := FetchProgress{Total: len(requests)}
+		report := func(result FetchResult) bool {
+			progress.Done++
+			if result.Err != nil {
+				progress.Failed++
+			}
+			if opts.Progress != nil {
+				opts.Progress(progress)
+			}
+
+			return yield(result)
+		}
+
+		units, invalid := c.planFetch(requests, batchSize)
+		for _, result := range invalid {
+			if !report(result) {
+				return
+			}
+		}
+
+		work := make(chan fetchUnit)
+		go func() {
+			defer close(work)
+			for _, unit := range units {
+				select {
+				case work <- unit:
+				case <-stop:
+					return
+				}
+			}
+		}()
+
+		done := make(chan fetchUnitResults)
+		var workers sync.WaitGroup
+		for range concurrency {
+			workers.Add(1)
+			go func() {
+				defer workers.Done()
+				for unit := range work {
+					select {
+					case done <- c.fetchUnit(ctx, requests, unit, opts.Budget):
+					case <-stop:
+						return
+					}
+				}
+			}()
+		}
+
+		go func() {
+			workers.Wait()
+			close(done)
+		}()
+
+		for unit := range done {
+			progress.Credits += unit.credits
+			for _, result := range unit.results {
+				if !report(result) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// planFetch groups the requests into units of up to batchSize symbols. Requests that can't be converted to params are
+// returned as failed results.
+func (c *APIClient) planFetch(requests []FetchRequest, batchSize int) (units []fetchUnit, invalid []FetchResult) {
+	groups := make(map[string]int) // Index in units of the unit being filled for each group
+	for i, req := range requests {
+		endpoint, params, preserve, batch, err := fetchParams(req)
+		if err != nil {
+			invalid = append(invalid, FetchResult{Index: i, Request: req, Err: err})
+			continue
+		}
+
+		if !batch {
+			units = append(units, fetchUnit{endpoint: endpoint, indexes: []int{i}})
+			continue
+		}
+
+		delete(params, "symbol")
+		key := fetchGroupKey(endpoint, params, c.preservesDecimals(preserve))
+		if params["figi"] != "" || params["isin"] != "" || params["cusip"] != "" {
+			// Identifiers of a single instrument can't be combined with other symbols
+			key += "#" + strconv.Itoa(i)
+		}
+
+		if unit, ok := groups[key]; ok && len(units[unit].indexes) < batchSize {
+			units[unit].indexes = append(units[unit].indexes, i)
+			continue
+		}
+
+		groups[key] = len(units)
+		units = append(units, fetchUnit{endpoint: endpoint, params: params, indexes: []int{i}, batch: true})
+	}
+
+	return units, invalid
+}
+
+// fetchParams validates a request of FetchMany and returns its endpoint and params, and whether it can be batched
+func fetchParams(req FetchRequest) (endpoint string, params map[string]string, preserve *bool, batch bool, err error) {
+	set := 0
+	for _, isSet := range []bool{req.Quote != nil, req.TimeSeries != nil, req.Price != nil} {
+		if isSet {
+			set++
+		}
+	}
+
+	switch {
+	case set != 1:
+		return "", nil, nil, false, errors.New("exactly one of Quote, TimeSeries and Price must be set")
+	case req.Quote != nil:
+		params, err = req.Quote.ToParams()
+		if err != nil {
+			return "", nil, nil, false, errors.Wrap(err, "Error converting QuoteRequest to params")
+		}
+
+		return urlEndpointQuote, params, req.Quote.PreserveDecimals, true, nil
+	case req.TimeSeries != nil:
+		params, err = req.TimeSeries.ToParams()
+		if err != nil {
+			return "", nil, nil, false, errors.Wrap(err, "Error converting TimeSeriesRequest to params")
+		}
+
+		fromEarliest := req.TimeSeries.StartDate == nil && req.TimeSeries.FromEarliest != nil && *req.TimeSeries.FromEarliest
+		batch = !isCSV(req.TimeSeries.Format) && !fromEarliest
+
+		return urlEndpointTimeSeries, params, req.TimeSeries.PreserveDecimals, batch, nil
+	default:
+		params, err = req.Price.ToParams()
+		if err != nil {
+			return "", nil, nil, false, errors.Wrap(err, "Error converting PriceRequest to params")
+		}
+
+		return urlEndpointPrice, params, req.Price.PreserveDecimals, true, nil
+	}
+}
+
+// fetchGroupKey identifies the requests that can be sent in the same batch
+func fetchGroupKey(endpoint string, params map[string]string, preserveDecimals bool) string {
+	keys := make([]string, 0, len(params))
+	for key := range params {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	b.WriteString(endpoint)
+	for _, key := range keys {
+		b.WriteString("&" + key + "=" + params[key])
+	}
+	b.WriteString("&preserve=" + strconv.FormatBool(preserveDecimals))
+
+	return b.String()
+}
+
+// fetchUnit sends the requests of a unit and returns their results. When ctx is done, the requests not sent yet fail
+// with its error.
+func (c *APIClient) fetchUnit(ctx context.Context, requests []FetchRequest, unit fetchUnit, budget *CreditBudget) fetchUnitResults {
+	results := make([]FetchResult, len(unit.indexes))
+	for i, index := range unit.indexes {
+		results[i] = FetchResult{Index: index, Request: requests[index]}
+	}
+
+	fail := func(err error) fetchUnitResults {
+		for i := range results {
+			results[i].Err = err
+		}
+		return fetchUnitResults{results: results}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return fail(err)
+	}
+
+	if !unit.batch {
+		// GetTimeSeriesContext resolves FromEarliest with an extra request
+		credits := 1
+		if req := results[0].Request.TimeSeries; req.StartDate == nil && req.FromEarliest != nil && *req.FromEarliest {
+			credits = 2
+		}
+
+		if budget != nil {
+			if err := budget.Wait(ctx, credits); err != nil {
+				return fail(err)
+			}
+		}
+
+		results[0].TimeSeries, results[0].Err = c.GetTimeSeriesContext(ctx, *results[0].Request.TimeSeries)
+		return fetchUnitResults{results: results, credits: credits}
+	}
+
+	symbols := make([]string, 0, len(results))
+	for _, result := range results {
+		symbols = append(symbols, result.Request.Symbol())
+	}
+
+	response, entries, credits, err := c.fetchBatch(ctx, unit.endpoint, unit.params, symbols, budget)
+	if err != nil {
+		fail(err)
+		return fetchUnitResults{results: results, credits: credits}
+	}
+
+	for i := range results {
+		entry := entries[strings.ToUpper(results[i].Request.Symbol())]
+		if entry.err != nil {
+			results[i].Err = entry.err
+			continue
+		}
+
+		// The part of the batch response answering the symbol
+		part := &Response{StatusCode: response.StatusCode, Header: response.Header, Body: entry.raw, ctx: response.ctx}
+
+		if req := results[i].Request.Quote; req != nil {
+			results[i].Err = c.Client.unmarshal(c.jsonAPI(req.PreserveDecimals), part, &results[i].Quote, "quote")
+			continue
+		}
+
+		if req := results[i].Request.Price; req != nil {
+			results[i].Err = c.Client.unmarshal(c.jsonAPI(req.PreserveDecimals), part, &results[i].Price, "price")
+			continue
+		}
+
+		req := *results[i].Request.TimeSeries
+		_, loc, _ := c.timeSeriesParams(ctx, &req) // Validated by planFetch, without requests for JSON time series
+
+		series := &TimeSeriesResponse{}
+		err = c.Client.decode(part, "time series", func(body []byte) error {
+			return series.decode(c.jsonAPI(req.PreserveDecimals), body, loc)
+		})
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+
+		if series.Meta.ExchangeTimezone != "" {
+			c.timezones.Store(timezoneCacheKey(req), series.Meta.ExchangeTimezone)
+		}
+		results[i].TimeSeries = series
+	}
+
+	if unit.endpoint == urlEndpointQuote {
+		credits += c.localizeQuotes(ctx, results, budget)
+	}
+
+	return fetchUnitResults{results: results, credits: credits}
+}
+
+// localizeQuotes localizes the quotes of a unit like GetQuote. With Config.QuoteTimezones, the exchange timezones
+// missing from the cache are looked up with one batch request. It returns the credits spent on the lookup.
+func (c *APIClient) localizeQuotes(ctx context.Context, results []FetchResult, budget *CreditBudget) (credits int) {
+	var lookup []string
+	var lookupParams map[string]string
+	looked := make(map[string]bool) // Symbols in lookup
+	for _, result := range results {
+		if result.Err != nil {
+			continue
+		}
+
+		series := quoteSeriesRequest(*result.Request.Quote)
+		if _, ok := c.cachedQuoteLocation(series); ok || !c.lookupQuoteTimezone(series) {
+			continue
+		}
+
+		lookup = append(lookup, *series.Symbol)
+		looked[*series.Symbol] = true
+		if lookupParams == nil {
+			// The requests of a unit share everything but the symbol
+			lookupParams, _ = series.ToParams()
+			delete(lookupParams, "symbol")
+		}
+	}
+
+	var entries map[string]fetchEntry
+	var lookupErr error
+	if len(lookup) > 0 {
+		_, entries, credits, lookupErr = c.fetchBatch(ctx, urlEndpointTimeSeries, lookupParams, lookup, budget)
+	}
+
+	for i := range results {
+		if results[i].Err != nil {
+			continue
+		}
+
+		series := quoteSeriesRequest(*results[i].Request.Quote)
+		loc, ok := c.cachedQuoteLocation(series)
+		switch {
+		case ok:
+		case looked[*series.Symbol]:
+			loc = c.lookedUpQuoteLocation(series, entries[strings.ToUpper(*series.Symbol)], lookupErr)
+		default:
+			loc = time.UTC
+		}
+
+		*results[i].Quote = results[i].Quote.Localize(loc)
+	}
+
+	return credits
+}
+
+// lookedUpQuoteLocation caches the exchange timezone of a quote from entry, the answer of the lookup, or lookupErr,
+// the error of the whole lookup batch. A failed lookup is remembered and falls back to UTC, see quoteLocation.
+func (c *APIClient) lookedUpQuoteLocation(series TimeSeriesRequest, entry fetchEntry, lookupErr error) *time.Location {
+	err := lookupErr
+	if err == nil {
+		err = entry.err
+	}
+
+	var resp TimeSeriesResponse
+	if err == nil {
+		err = resp.decode(jsoniter.ConfigDefault, entry.raw, nil)
+	}
+
+	if err == nil && resp.Meta.ExchangeTimezone == "" {
+		err = errNoExchangeTimezone
+	}
+
+	var loc *time.Location
+	if err == nil {
+		loc, err = time.LoadLocation(resp.Meta.ExchangeTimezone)
+	}
+
+	if err != nil {
+		c.quoteTimezoneFailed(series, err)
+		return time.UTC
+	}
+
+	c.timezones.Store(timezoneCacheKey(series), resp.Meta.ExchangeTimezone)
+	return loc
+}
+
+// fetchEntry is the answer of a batch request for one symbol
+type fetchEntry struct {
+	raw jsoniter.RawMessage
+	err error
+}
+
+// fetchBatch sends one request for all symbols, waiting for their credits when budget is set, and returns the answer
+// for each symbol by upper case symbol. A 429 exhausts the budget and sends the request again in the next minute.
+func (c *APIClient) fetchBatch(ctx context.Context, endpoint string, params map[string]string, symbols []string, budget *CreditBudget) (response *Response, entries map[string]fetchEntry, credits int, err error) {
+	unique := make([]string, 0, len(symbols))
+	seen := make(map[string]bool, len(symbols))
+	for _, symbol := range symbols {
+		if upper := strings.ToUpper(symbol); !seen[upper] {
+			seen[upper] = true
+			unique = append(unique, symbol)
+		}
+	}
+
+	for waits := 0; ; waits++ {
+		if budget != nil {
+			if err = budget.Wait(ctx, len(unique)); err != nil {
+				return nil, nil, credits, err
+			}
+		}
+
+		data := make(map[string]string, len(params)+1)
+		for key, value := range params {
+			data[key] = value
+		}
+		data["symbol"] = strings.Join(unique, ",")
+
+		response, err = c.Client.getContext(ctx, endpoint, data)
+		if err != nil {
+			return nil, nil, credits, errors.Wrap(err, "Error fetching batch data")
+		}
+
+		if used := creditsHeader(response.Header, "api-credits-used"); used >= 0 {
+			credits += used
+		} else if response.StatusCode == http.StatusOK {
+			credits += len(unique)
+		}
+
+		if budget != nil {
+			budget.Observe(creditsHeader(response.Header, "api-credits-left"))
+		}
+
+		apiErr := apiErrorOf(response.Body)
+		rateLimited := response.StatusCode == http.StatusTooManyRequests || (apiErr != nil && apiErr.Code == http.StatusTooManyRequests)
+		if !rateLimited {
+			break
+		}
+
+		if waits == fetchRateLimitWaits {
+			if apiErr == nil {
+				apiErr = &APIError{Code: http.StatusTooManyRequests, Message: "rate limited"}
+			}
+			return response, nil, credits, apiErr
+		}
+
+		c.Logger.Warn("Batch request rate limited, waiting for the next minute", "endpoint", endpoint, "symbols", len(unique))
+		if budget != nil {
+			budget.Exhaust()
+			continue
+		}
+
+		now := time.Now()
+		wait := time.NewTimer(now.Truncate(time.Minute).Add(time.Minute).Sub(now))
+		select {
+		case <-wait.C:
+		case <-ctx.Done():
+			wait.Stop()
+			return response, nil, credits, ctx.Err()
+		}
+	}
+
+	if response.StatusCode != http.StatusOK {
+		return response, nil, credits, errors.Errorf("unexpected status code %d", response.StatusCode)
+	}
+
+	if apiErr := apiErrorOf(response.Body); apiErr != nil {
+		return response, nil, credits, apiErr
+	}
+
+	entries = make(map[string]fetchEntry, len(unique))
+	if len(unique) == 1 {
+		// The API answers a single symbol like a request without batch
+		entries[strings.ToUpper(unique[0])] = fetchEntry{raw: response.Body}
+		return response, entries, credits, nil
+	}
+
+	var bySymbol map[string]jsoniter.RawMessage
+	if err = c.Client.unmarshal(jsoniter.ConfigDefault, response, &bySymbol, "batch"); err != nil {
+		return response, nil, credits, err
+	}
+
+	for symbol, raw := range bySymbol {
+		entry := fetchEntry{raw: raw}
+		if apiErr := apiErrorOf(raw); apiErr != nil {
+			entry = fetchEntry{err: apiErr}
+		}
+		entries[strings.ToUpper(symbol)] = entry
+	}
+
+	for _, symbol := range unique {
+		if _, ok := entries[strings.ToUpper(symbol)]; !ok {
+			entries[strings.ToUpper(symbol)] = fetchEntry{err: errors.Errorf("no data for %s in batch response", symbol)}
+		}
+	}
+
+	return response, entries, credits, nil
+}
@@ -0,0 +1,239 @@
+package twelvedata_test
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/jonnotjohn/twelvedata-go"
+	"github.com/jonnotjohn/twelvedata-go/tdtest"
+	"github.com/pkg/errors"
+)
+
+// fetchAll collects the results of FetchMany by request index with the last progress reported
+func fetchAll(t *testing.T, ctx context.Context, client *twelvedata.APIClient, requests []twelvedata.FetchRequest, opts twelvedata.FetchOptions) ([]twelvedata.FetchResult, twelvedata.FetchProgress) {
+	t.Helper()
+
+	var progress twelvedata.FetchProgress
+	opts.Progress = func(p twelvedata.FetchProgress) { progress = p }
+
+	results := make([]twelvedata.FetchResult, len(requests))
+	seen := make([]bool, len(requests))
+	for result := range client.FetchMany(ctx, requests, opts) {
+		if result.Index < 0 || result.Index >= len(requests) || seen[result.Index] {
+			t.Fatalf("result for index %d, want one result per request", result.Index)
+		}
+		seen[result.Index] = true
+
+		expect(t, "Request", result.Request, requests[result.Index])
+		results[result.Index] = result
+	}
+
+	for i, ok := range seen {
+		if !ok {
+			t.Errorf("no result for request %d", i)
+		}
+	}
+
+	return results, progress
+}
+
+// batchRequests returns the symbol params of the requests the server received for path, sorted
+func batchRequests(server *tdtest.Server, path string) []string {
+	var symbols []string
+	for _, request := range server.Requests() {
+		if request.Path == path {
+			symbols = append(symbols, request.Params["symbol"])
+		}
+	}
+	sort.Strings(symbols)
+
+	return symbols
+}
+
+func TestFetchManyBatches(t *testing.T) {
+	server, client, _ := serverClient(t, 1)
+	server.SetNow(func() time.Time { return time.Date(2024, 6, 14, 18, 0, 0, 0, time.UTC) })
+
+	price := func(symbol string) twelvedata.FetchRequest {
+		return twelvedata.FetchRequest{Price: &twelvedata.PriceRequest{Symbol: ptr(symbol)}}
+	}
+	series := func(symbol string) twelvedata.FetchRequest {
+		return twelvedata.FetchRequest{TimeSeries: &twelvedata.TimeSeriesRequest{
+			Symbol:     ptr(symbol),
+			Interval:   ptr(twelvedata.TimeSeriesInterval1Day),
+			OutputSize: ptr(2),
+		}}
+	}
+
+	requests := []twelvedata.FetchRequest{
+		price("AAPL"),
+		series("AAPL"),
+		price("MSFT"),
+		price("NOPE"),
+		series("BTC/USD"),
+		price("IBM"),
+		{Quote: &twelvedata.QuoteRequest{Symbol: ptr("ETH/USD")}},
+		{},
+	}
+
+	results, progress := fetchAll(t, context.Background(), client, requests, twelvedata.FetchOptions{Concurrency: 2, BatchSize: 2})
+
+	// Equal requests are grouped in the order they come, up to BatchSize symbols
+	expect(t, "price batches", batchRequests(server, "/price"), []string{"AAPL,MSFT", "NOPE,IBM"})
+	expect(t, "time series batches", batchRequests(server, "/time_series"), []string{"AAPL,BTC/USD"})
+	expect(t, "quote batches", batchRequests(server, "/quote"), []string{"ETH/USD"})
+
+	for _, i := range []int{0, 2, 5} {
+		if results[i].Err != nil || results[i].Price == nil || results[i].Price.Price.Float64 <= 0 {
+			t.Errorf("price of %s = %+v, %v, want a positive price", requests[i].Symbol(), results[i].Price, results[i].Err)
+		}
+	}
+
+	for _, i := range []int{1, 4} {
+		if results[i].Err != nil {
+			t.Fatalf("time series of %s error = %v", requests[i].Symbol(), results[i].Err)
+		}
+		expect(t, "Meta.Symbol", results[i].TimeSeries.Meta.Symbol, requests[i].Symbol())
+		expect(t, "candles", len(results[i].TimeSeries.Candles), 2)
+	}
+	expect(t, "AAPL timezone", results[1].TimeSeries.Candles[0].DateTime.Location().String(), "America/New_York")
+	expect(t, "BTC/USD timezone", results[4].TimeSeries.Candles[0].DateTime.Location().String(), "UTC")
+
+	if results[6].Err != nil || results[6].Quote.Symbol != "ETH/USD" {
+		t.Errorf("quote = %+v, %v, want the quote of ETH/USD", results[6].Quote, results[6].Err)
+	}
+
+	// An unknown symbol fails alone, the other symbols of its batch succeed
+	expectAPIError(t, results[3].Err, http.StatusNotFound, "not found")
+	if results[7].Err == nil {
+		t.Error("request without Quote, TimeSeries or Price succeeded")
+	}
+
+	expect(t, "progress", progress, twelvedata.FetchProgress{Total: 8, Done: 8, Failed: 2, Credits: 7})
+}
+
+func TestFetchManyBatchFault(t *testing.T) {
+	server, client, _ := serverClient(t, 1)
+	server.Inject(tdtest.Fault{Path: "/price", Code: http.StatusBadRequest, Message: "**symbol** list is invalid"})
+
+	requests := []twelvedata.FetchRequest{
+		{Price: &twelvedata.PriceRequest{Symbol: ptr("AAPL")}},
+		{Price: &twelvedata.PriceRequest{Symbol: ptr("MSFT")}},
+		{Quote: &twelvedata.QuoteRequest{Symbol: ptr("IBM")}},
+	}
+
+	results, progress := fetchAll(t, context.Background(), client, requests, twelvedata.FetchOptions{})
+
+	// The error of a whole batch is the error of each of its requests
+	expectAPIError(t, results[0].Err, http.StatusBadRequest, "list is invalid")
+	expectAPIError(t, results[1].Err, http.StatusBadRequest, "list is invalid")
+	if results[2].Err != nil {
+		t.Errorf("quote of another batch error = %v", results[2].Err)
+	}
+
+	expect(t, "Failed", progress.Failed, 2)
+}
+
+func TestFetchManyOrder(t *testing.T) {
+	server, client, _ := serverClient(t, 1)
+
+	// The first batch answers last, so its results come after those of the second one
+	server.Inject(tdtest.Fault{Path: "/price", Delay: 50 * time.Millisecond})
+
+	requests := []twelvedata.FetchRequest{
+		{Price: &twelvedata.PriceRequest{Symbol: ptr("AAPL")}},
+		{Price: &twelvedata.PriceRequest{Symbol: ptr("MSFT")}},
+		{Quote: &twelvedata.QuoteRequest{Symbol: ptr("IBM")}},
+	}
+
+	var order []int
+	for result := range client.FetchMany(context.Background(), requests, twelvedata.FetchOptions{Concurrency: 2}) {
+		if result.Err != nil {
+			t.Fatal(result.Err)
+		}
+		order = append(order, result.Index)
+	}
+
+	expect(t, "order", order, []int{2, 0, 1})
+}
+
+func TestFetchManyContext(t *testing.T) {
+	server, client, _ := serverClient(t, 1)
+
+	// The CSV time series isn't batched: its timezone lookup hangs until the deadline
+	server.Inject(tdtest.Fault{Path: "/time_series", Delay: time.Minute})
+
+	requests := []twelvedata.FetchRequest{
+		{TimeSeries: &twelvedata.TimeSeriesRequest{
+			Symbol:   ptr("AAPL"),
+			Interval: ptr(twelvedata.TimeSeriesInterval1Day),
+			Format:   ptr(twelvedata.ResponseFormatCSV),
+		}},
+		{Price: &twelvedata.PriceRequest{Symbol: ptr("AAPL")}},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	results, progress := fetchAll(t, ctx, client, requests, twelvedata.FetchOptions{Concurrency: 1})
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("FetchMany took %s, want it to stop at the deadline", elapsed)
+	}
+
+	for i, result := range results {
+		if !errors.Is(result.Err, context.DeadlineExceeded) {
+			t.Errorf("result %d error = %v, want the deadline of the context", i, result.Err)
+		}
+	}
+	expect(t, "Failed", progress.Failed, 2)
+}
+
+func TestCreditBudgetWait(t *testing.T) {
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name    string
+		prepare func(budget *twelvedata.CreditBudget)
+		credits int
+		wait    bool // Whether Wait has to wait for the next minute
+	}{
+		{name: "credits left", prepare: func(b *twelvedata.CreditBudget) { _ = b.Wait(context.Background(), 2) }, credits: 1},
+		{name: "credits used", prepare: func(b *twelvedata.CreditBudget) { _ = b.Wait(context.Background(), 3) }, credits: 1, wait: true},
+		{name: "more than per minute", credits: 10},
+		{name: "observed", prepare: func(b *twelvedata.CreditBudget) { b.Observe(0) }, credits: 1, wait: true},
+		{name: "observed unknown", prepare: func(b *twelvedata.CreditBudget) { b.Observe(-1) }, credits: 3},
+		{name: "observed left", prepare: func(b *twelvedata.CreditBudget) { b.Observe(1) }, credits: 2, wait: true},
+		{name: "exhausted", prepare: func(b *twelvedata.CreditBudget) { b.Exhaust() }, credits: 1, wait: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			budget := twelvedata.NewCreditBudget(3)
+			minute := time.Now().Truncate(time.Minute)
+			if tt.prepare != nil {
+				tt.prepare(budget)
+			}
+
+			// With a cancelled context, Wait fails instead of waiting
+			err := budget.Wait(cancelled, tt.credits)
+			if !time.Now().Truncate(time.Minute).Equal(minute) {
+				t.Skip("the minute changed during the test")
+			}
+
+			if tt.wait {
+				if !errors.Is(err, context.Canceled) {
+					t.Errorf("Wait() error = %v, want it to wait for the next minute", err)
+				}
+			} else if err != nil {
+				t.Errorf("Wait() error = %v, want the credits to be taken", err)
+			}
+		})
+	}
+
+	expect(t, "PerMinute", twelvedata.NewCreditBudget(0).PerMinute(), 1)
+}
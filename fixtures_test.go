@@ -0,0 +1,814 @@
+package twelvedata_test
+
+import (
+	"io/fs"
+	"maps"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jonnotjohn/twelvedata-go"
+	"github.com/jonnotjohn/twelvedata-go/tdtest"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+)
+
+// expect reports a mismatch between a decoded value and the value of the fixture
+func expect(t *testing.T, name string, got, want any) {
+	t.Helper()
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("%s = %#v, want %#v", name, got, want)
+	}
+}
+
+// expectAPIError checks that err carries the error payload of a fixture
+func expectAPIError(t *testing.T, err error, code int, message string) {
+	t.Helper()
+
+	var apiErr *twelvedata.APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("error = %v, want an *APIError", err)
+	}
+
+	expect(t, "APIError.Code", apiErr.Code, code)
+	if !strings.Contains(apiErr.Message, message) {
+		t.Errorf("APIError.Message = %q, want it to contain %q", apiErr.Message, message)
+	}
+}
+
+// wallClock formats t in New York
+func wallClock(t time.Time) string {
+	return t.In(newYork).Format("2006-01-02 15:04:05")
+}
+
+// fixtureCases replay the bundled fixtures through the endpoint methods. Each case lists the fixtures its requests
+// are answered from.
+var fixtureCases = []struct {
+	name     string
+	fixtures []string
+	run      func(t *testing.T, client *twelvedata.APIClient)
+}{
+	{
+		name:     "cryptocurrencies",
+		fixtures: []string{"cryptocurrencies_8bd1bd26.json"},
+		run: func(t *testing.T, client *twelvedata.APIClient) {
+			resp, err := client.GetCryptocurrencies()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			expect(t, "Status", resp.Status, "ok")
+			expect(t, "Data", resp.Data, []twelvedata.Crypto{
+				{Symbol: "BTC/USD", AvailableExchanges: []string{"Binance", "Coinbase Pro", "Kraken"}, CurrencyBase: "Bitcoin", CurrencyQuote: "US Dollar"},
+				{Symbol: "ETH/EUR", AvailableExchanges: []string{"Bitstamp", "Kraken"}, CurrencyBase: "Ethereum", CurrencyQuote: "Euro"},
+			})
+		},
+	},
+	{
+		name:     "currency conversion",
+		fixtures: []string{"currency_conversion_EUR-USD_05136d0f.json"},
+		run: func(t *testing.T, client *twelvedata.APIClient) {
+			resp, err := client.GetCurrencyConversion(twelvedata.CurrencyConversionRequest{Symbol: ptr("EUR/USD"), Amount: ptr(1000.0)})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			expect(t, "Symbol", resp.Symbol, "EUR/USD")
+			expect(t, "Rate", resp.Rate.Float64, 1.07032)
+			expect(t, "Amount", resp.Amount.Float64, 1070.32)
+			expect(t, "Timestamp", resp.Timestamp.Unix(), int64(1718395140))
+		},
+	},
+	{
+		name:     "earliest intraday timestamp",
+		fixtures: []string{"earliest_timestamp_AAPL_55157170.json", "time_series_AAPL_ab377d55.json"},
+		run: func(t *testing.T, client *twelvedata.APIClient) {
+			earliest, err := client.GetEarliestTimestamp(twelvedata.EarliestTimestampRequest{
+				Symbol:   ptr("AAPL"),
+				Interval: ptr(twelvedata.TimeSeriesInterval1Min),
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			expect(t, "unix time", earliest.Unix(), int64(1581325200))
+			expect(t, "location", earliest.Location().String(), "America/New_York")
+			expect(t, "wall clock", earliest.Format("2006-01-02 15:04:05"), "2020-02-10 04:00:00")
+		},
+	},
+	{
+		name:     "earliest daily timestamp",
+		fixtures: []string{"earliest_timestamp_AAPL_da1a63f6.json", "time_series_AAPL_1bc6a860.json"},
+		run: func(t *testing.T, client *twelvedata.APIClient) {
+			earliest, err := client.GetEarliestTimestamp(twelvedata.EarliestTimestampRequest{
+				Symbol:   ptr("AAPL"),
+				Interval: ptr(twelvedata.TimeSeriesInterval1Day),
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			expect(t, "unix time", earliest.Unix(), int64(345479400))
+			expect(t, "location", earliest.Location().String(), "America/New_York")
+			expect(t, "wall clock", earliest.Format("2006-01-02 15:04:05"), "1980-12-12 09:30:00")
+		},
+	},
+	{
+		name:     "ETF",
+		fixtures: []string{"etfs_world_IVV_525b89fe.json"},
+		run: func(t *testing.T, client *twelvedata.APIClient) {
+			fund, err := client.GetFundFull(twelvedata.FundRequest{Type: ptr(twelvedata.FundTypeETF), Symbol: ptr("IVV")})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			expect(t, "Summary.Name", fund.Summary.Name, "iShares Core S&P 500 ETF")
+			expect(t, "Summary.LastPrice", fund.Summary.LastPrice, 544.72)
+			expect(t, "Performance.TrailingReturns", fund.Performance.TrailingReturns, []twelvedata.FundTrailingReturn{
+				{Period: "ytd", ShareClassReturn: 0.1504, CategoryReturn: 0.1287},
+			})
+			expect(t, "Risk.VolatilityMeasures[0].SharpeRatio", fund.Risk.VolatilityMeasures[0].SharpeRatio, 0.47)
+			expect(t, "Ratings", fund.Ratings, twelvedata.FundRatings{})
+		},
+	},
+	{
+		name:     "ETF composition",
+		fixtures: []string{"etfs_world_composition_IVV_a5555bad.json"},
+		run: func(t *testing.T, client *twelvedata.APIClient) {
+			composition, err := client.GetFundComposition(twelvedata.FundRequest{Type: ptr(twelvedata.FundTypeETF), Symbol: ptr("IVV")})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			expect(t, "CountryAllocation", composition.CountryAllocation, []twelvedata.FundCountryAllocation{
+				{Country: "United States", Allocation: 0.9942},
+				{Country: "Ireland", Allocation: 0.0121},
+			})
+			expect(t, "TopHoldings[0]", composition.TopHoldings[0], twelvedata.FundHolding{
+				Symbol: "MSFT", Name: "Microsoft Corp", Exchange: "NASDAQ", MicCode: "XNGS", Weight: 0.0703,
+			})
+			expect(t, "AssetAllocation.Stocks", composition.AssetAllocation.Stocks, 0.9992)
+		},
+	},
+	{
+		name:     "ETF performance",
+		fixtures: []string{"etfs_world_performance_IVV_6014d23e.json"},
+		run: func(t *testing.T, client *twelvedata.APIClient) {
+			performance, err := client.GetFundPerformance(twelvedata.FundRequest{Type: ptr(twelvedata.FundTypeETF), Symbol: ptr("IVV")})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			expect(t, "AnnualTotalReturns", performance.AnnualTotalReturns, []twelvedata.FundAnnualReturn{
+				{Year: 2023, ShareClassReturn: 0.2627, CategoryReturn: 0.2232},
+			})
+			expect(t, "QuarterlyTotalReturns", len(performance.QuarterlyTotalReturns), 0)
+		},
+	},
+	{
+		name:     "ETF risk",
+		fixtures: []string{"etfs_world_risk_IVV_9353f794.json"},
+		run: func(t *testing.T, client *twelvedata.APIClient) {
+			risk, err := client.GetFundRisk(twelvedata.FundRequest{Type: ptr(twelvedata.FundTypeETF), Symbol: ptr("IVV")})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			expect(t, "VolatilityMeasures[0].Period", risk.VolatilityMeasures[0].Period, "3_y")
+			expect(t, "VolatilityMeasures[0].TreynorRatio", risk.VolatilityMeasures[0].TreynorRatio, 7.85)
+			expect(t, "ValuationMetrics.ThreeYearEarningsGrowth", risk.ValuationMetrics.ThreeYearEarningsGrowth, 11.39)
+		},
+	},
+	{
+		name:     "ETF summary",
+		fixtures: []string{"etfs_world_summary_IVV_44b8c2ff.json"},
+		run: func(t *testing.T, client *twelvedata.APIClient) {
+			summary, err := client.GetFundSummary(twelvedata.FundRequest{Type: ptr(twelvedata.FundTypeETF), Symbol: ptr("IVV")})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			expect(t, "FundFamily", summary.FundFamily, "iShares")
+			expect(t, "NetAssets", summary.NetAssets, 1290000000000.0)
+			expect(t, "ShareClassInceptionDate", summary.ShareClassInceptionDate.Format("2006-01-02"), "2000-11-13")
+		},
+	},
+	{
+		name:     "exchange rate",
+		fixtures: []string{"exchange_rate_EUR-USD_0dd7e41b.json"},
+		run: func(t *testing.T, client *twelvedata.APIClient) {
+			rate, err := client.GetExchangeRate(twelvedata.ExchangeRateRequest{Symbol: ptr("EUR/USD")})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			expect(t, "Rate", rate.Rate.Float64, 1.07032)
+			expect(t, "Timestamp", rate.Timestamp.Unix(), int64(1718395140))
+			expect(t, "Timestamp location", rate.Timestamp.Location(), time.UTC)
+		},
+	},
+	{
+		name:     "exchange schedule",
+		fixtures: []string{"exchange_schedule_78e423ee.json"},
+		run: func(t *testing.T, client *twelvedata.APIClient) {
+			schedule, err := client.GetExchangeSchedule(twelvedata.ExchangeScheduleRequest{MicCode: ptr("XNGS")})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			expect(t, "TimeZone", schedule.Data[0].TimeZone, "America/New_York")
+			expect(t, "Sessions", len(schedule.Data[0].Sessions), 3)
+			expect(t, "Sessions[1]", schedule.Data[0].Sessions[1], twelvedata.ExchangeSession{
+				OpenTime:    twelvedata.TDDuration{Duration: 9*time.Hour + 30*time.Minute},
+				CloseTime:   twelvedata.TDDuration{Duration: 16 * time.Hour},
+				SessionName: "Core session",
+				SessionType: twelvedata.ExchangeSessionTypeCore,
+			})
+		},
+	},
+	{
+		name:     "exchange schedule of a half day",
+		fixtures: []string{"exchange_schedule_0e2584af.json"},
+		run: func(t *testing.T, client *twelvedata.APIClient) {
+			schedule, err := client.GetExchangeSchedule(twelvedata.ExchangeScheduleRequest{MicCode: ptr("XNGS"), Date: ptr(at("2024-07-03 00:00"))})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			expect(t, "Sessions[1].CloseTime", schedule.Data[0].Sessions[1].CloseTime.Duration, 13*time.Hour)
+		},
+	},
+	{
+		name:     "exchange schedule of a holiday",
+		fixtures: []string{"exchange_schedule_b7baaba6.json"},
+		run: func(t *testing.T, client *twelvedata.APIClient) {
+			schedule, err := client.GetExchangeSchedule(twelvedata.ExchangeScheduleRequest{MicCode: ptr("XNGS"), Date: ptr(at("2024-07-04 00:00"))})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			expect(t, "Code", schedule.Data[0].Code, "XNGS")
+			expect(t, "Sessions", len(schedule.Data[0].Sessions), 0)
+		},
+	},
+	{
+		name:     "fund holders",
+		fixtures: []string{"fund_holders_AAPL_d03399ca.json"},
+		run: func(t *testing.T, client *twelvedata.APIClient) {
+			resp, err := client.GetFundHolders(twelvedata.FundamentalsRequest{Symbol: ptr("AAPL")})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			expect(t, "Meta.ExchangeTimezone", resp.Meta.ExchangeTimezone, "America/New_York")
+			expect(t, "FundHolders", len(resp.FundHolders), 2)
+			expect(t, "FundHolders[0].EntityName", resp.FundHolders[0].EntityName, "Vanguard Total Stock Market Index Fund")
+			expect(t, "FundHolders[0].Shares", resp.FundHolders[0].Shares, 457365630.0)
+			expect(t, "FundHolders[0].DateReported", wallClock(resp.FundHolders[0].DateReported.Time), "2024-03-31 00:00:00")
+		},
+	},
+	{
+		name:     "insider transactions",
+		fixtures: []string{"insider_transactions_AAPL_1e2ed71d.json"},
+		run: func(t *testing.T, client *twelvedata.APIClient) {
+			resp, err := client.GetInsiderTransactions(twelvedata.FundamentalsRequest{Symbol: ptr("AAPL")})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			expect(t, "InsiderTransactions", len(resp.InsiderTransactions), 3)
+			expect(t, "InsiderTransactions[1].FullName", resp.InsiderTransactions[1].FullName, "LEVINSON ARTHUR D")
+			expect(t, "InsiderTransactions[1].Value", resp.InsiderTransactions[1].Value, 181500.0)
+			expect(t, "InsiderTransactions[1].IsPurchase", resp.InsiderTransactions[1].IsPurchase(), true)
+			expect(t, "InsiderTransactions[0].IsPurchase", resp.InsiderTransactions[0].IsPurchase(), false)
+		},
+	},
+	{
+		name:     "institutional holders",
+		fixtures: []string{"institutional_holders_AAPL_e551c1a5.json"},
+		run: func(t *testing.T, client *twelvedata.APIClient) {
+			resp, err := client.GetInstitutionalHolders(twelvedata.FundamentalsRequest{Symbol: ptr("AAPL")})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			expect(t, "InstitutionalHolders", len(resp.InstitutionalHolders), 3)
+			expect(t, "InstitutionalHolders[2].EntityName", resp.InstitutionalHolders[2].EntityName, "State Street Corporation")
+			expect(t, "InstitutionalHolders[2].PercentOut", resp.InstitutionalHolders[2].PercentOut, 0.038)
+		},
+	},
+	{
+		name:     "key executives",
+		fixtures: []string{"key_executives_AAPL_64784343.json"},
+		run: func(t *testing.T, client *twelvedata.APIClient) {
+			resp, err := client.GetKeyExecutives(twelvedata.FundamentalsRequest{Symbol: ptr("AAPL")})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			expect(t, "KeyExecutives[0]", resp.KeyExecutives[0], twelvedata.KeyExecutive{
+				Name: "Mr. Timothy D. Cook", Title: "CEO & Director", Age: 62, YearBorn: 1961, Pay: 16239562,
+			})
+		},
+	},
+	{
+		name:     "stock logo",
+		fixtures: []string{"logo_AAPL_8018cc47.json"},
+		run: func(t *testing.T, client *twelvedata.APIClient) {
+			logo, err := client.GetLogo(twelvedata.LogoRequest{Symbol: ptr("AAPL")})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			expect(t, "Logo", *logo, twelvedata.Logo{
+				Meta: twelvedata.LogoMeta{Symbol: "AAPL", Exchange: "NASDAQ"},
+				URL:  "https://api.twelvedata.com/logo/apple.com",
+			})
+		},
+	},
+	{
+		name:     "crypto logo",
+		fixtures: []string{"logo_BTC-USD_3870e110.json"},
+		run: func(t *testing.T, client *twelvedata.APIClient) {
+			logo, err := client.GetLogo(twelvedata.LogoRequest{Symbol: ptr("BTC/USD")})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			expect(t, "Logo", *logo, twelvedata.Logo{
+				Meta:      twelvedata.LogoMeta{Symbol: "BTC/USD", Exchange: "Coinbase Pro"},
+				LogoBase:  "https://logo.twelvedata.com/crypto/btc.png",
+				LogoQuote: "https://logo.twelvedata.com/crypto/usd.png",
+			})
+		},
+	},
+	{
+		name:     "market movers",
+		fixtures: []string{"market_movers_stocks_cc62b5a1.json"},
+		run: func(t *testing.T, client *twelvedata.APIClient) {
+			resp, err := client.GetMarketMovers(twelvedata.MarketMoversRequest{Market: ptr(twelvedata.MarketMoversMarketStocks)})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			mover := resp.Values[0]
+			expect(t, "Symbol", mover.Symbol, "NVCR")
+			expect(t, "Last", mover.Last, 23.91)
+			expect(t, "PercentChange", mover.PercentChange, 24.86)
+			expect(t, "DateTime", mover.DateTime.Format("2006-01-02 15:04:05"), "2024-06-14 15:59:59")
+		},
+	},
+	{
+		name:     "market state",
+		fixtures: []string{"market_state_ba28baf4.json"},
+		run: func(t *testing.T, client *twelvedata.APIClient) {
+			states, err := client.GetMarketState(twelvedata.MarketStateRequest{})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			expect(t, "states", len(states), 2)
+			expect(t, "states[1].Code", states[1].Code, "XNGS")
+			expect(t, "states[1].IsMarketOpen", states[1].IsMarketOpen, false)
+			expect(t, "states[1].TimeToOpen", states[1].TimeToOpen.Duration, 65*time.Hour+30*time.Minute)
+		},
+	},
+	{
+		name:     "mutual fund",
+		fixtures: []string{"mutual_funds_world_VFIAX_3307130e.json"},
+		run: func(t *testing.T, client *twelvedata.APIClient) {
+			fund, err := client.GetFundFull(twelvedata.FundRequest{Type: ptr(twelvedata.FundTypeMutualFund), Symbol: ptr("VFIAX")})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			expect(t, "Summary.MinInvestment", fund.Summary.MinInvestment, 3000.0)
+			expect(t, "Summary.People", len(fund.Summary.People), 2)
+			expect(t, "Summary.People[1].Name", fund.Summary.People[1].Name, "Aurelie Denis")
+			expect(t, "PurchaseInfo.Expenses.ExpenseRatioNet", fund.PurchaseInfo.Expenses.ExpenseRatioNet, 0.0004)
+		},
+	},
+	{
+		name:     "mutual fund composition",
+		fixtures: []string{"mutual_funds_world_composition_VFIAX_63e0fd7b.json"},
+		run: func(t *testing.T, client *twelvedata.APIClient) {
+			composition, err := client.GetFundComposition(twelvedata.FundRequest{Type: ptr(twelvedata.FundTypeMutualFund), Symbol: ptr("VFIAX")})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			expect(t, "MajorMarketSectors[1]", composition.MajorMarketSectors[1], twelvedata.FundSectorWeight{Sector: "Healthcare", Weight: 0.1203})
+			expect(t, "TopHoldings[1].Weight", composition.TopHoldings[1].Weight, 0.0661)
+			expect(t, "CountryAllocation", len(composition.CountryAllocation), 0)
+		},
+	},
+	{
+		name:     "mutual fund performance",
+		fixtures: []string{"mutual_funds_world_performance_VFIAX_e60cfb66.json"},
+		run: func(t *testing.T, client *twelvedata.APIClient) {
+			performance, err := client.GetFundPerformance(twelvedata.FundRequest{Type: ptr(twelvedata.FundTypeMutualFund), Symbol: ptr("VFIAX")})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			expect(t, "TrailingReturns[1].RankInCategory", performance.TrailingReturns[1].RankInCategory, 25)
+			expect(t, "QuarterlyTotalReturns", performance.QuarterlyTotalReturns, []twelvedata.FundQuarterlyReturn{
+				{Year: 2023, Q1: 0.075, Q2: 0.0874, Q3: -0.0328, Q4: 0.1168},
+			})
+			expect(t, "LoadAdjustedReturns", performance.LoadAdjustedReturns, []twelvedata.FundLoadAdjustedReturn{
+				{Period: "1_year", Return: 0.2555},
+			})
+		},
+	},
+	{
+		name:     "mutual fund purchase info",
+		fixtures: []string{"mutual_funds_world_purchase_info_VFIAX_6c1f32bc.json"},
+		run: func(t *testing.T, client *twelvedata.APIClient) {
+			info, err := client.GetFundPurchaseInfo(twelvedata.FundRequest{Type: ptr(twelvedata.FundTypeMutualFund), Symbol: ptr("VFIAX")})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			expect(t, "Minimums", info.Minimums, twelvedata.FundMinimums{InitialInvestment: 3000, AdditionalInvestment: 1})
+			expect(t, "Pricing.TwelveMonthHigh", info.Pricing.TwelveMonthHigh, 497.3)
+			expect(t, "Brokerages", info.Brokerages, []string{"Vanguard", "Fidelity Retail FundsNetwork"})
+		},
+	},
+	{
+		name:     "mutual fund ratings",
+		fixtures: []string{"mutual_funds_world_ratings_VFIAX_4cca28b5.json"},
+		run: func(t *testing.T, client *twelvedata.APIClient) {
+			ratings, err := client.GetFundRatings(twelvedata.FundRequest{Type: ptr(twelvedata.FundTypeMutualFund), Symbol: ptr("VFIAX")})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			expect(t, "Ratings", *ratings, twelvedata.FundRatings{PerformanceRating: 4, RiskRating: 3, ReturnRating: 4})
+		},
+	},
+	{
+		name:     "mutual fund risk",
+		fixtures: []string{"mutual_funds_world_risk_VFIAX_1ad3369b.json"},
+		run: func(t *testing.T, client *twelvedata.APIClient) {
+			risk, err := client.GetFundRisk(twelvedata.FundRequest{Type: ptr(twelvedata.FundTypeMutualFund), Symbol: ptr("VFIAX")})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			expect(t, "VolatilityMeasures[0].StdDev", risk.VolatilityMeasures[0].StdDev, 17.61)
+			expect(t, "ValuationMetrics.MedianMarketCapitalization", risk.ValuationMetrics.MedianMarketCapitalization, 247366.0)
+		},
+	},
+	{
+		name:     "mutual fund summary",
+		fixtures: []string{"mutual_funds_world_summary_VFIAX_08515fb1.json"},
+		run: func(t *testing.T, client *twelvedata.APIClient) {
+			summary, err := client.GetFundSummary(twelvedata.FundRequest{Type: ptr(twelvedata.FundTypeMutualFund), Symbol: ptr("VFIAX")})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			expect(t, "Name", summary.Name, "Vanguard 500 Index Fund Admiral Shares")
+			expect(t, "NAV", summary.NAV, 495.22)
+			expect(t, "People[0].Name", summary.People[0].Name, "Michelle Louie")
+		},
+	},
+	{
+		name:     "option chain",
+		fixtures: []string{"options_chain_AAPL_6f302a41.json"},
+		run: func(t *testing.T, client *twelvedata.APIClient) {
+			chain, err := client.GetOptionChain(twelvedata.OptionChainRequest{Symbol: ptr("AAPL")})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			expect(t, "Calls", len(chain.Calls), 4)
+			expect(t, "Puts", len(chain.Puts), 4)
+			expect(t, "Calls[0].ContractName", chain.Calls[0].ContractName, "AAPL240621C00205000")
+			expect(t, "Calls[0].Strike", chain.Calls[0].Strike, 205.0)
+			expect(t, "Calls[0].InTheMoney", chain.Calls[0].InTheMoney, true)
+			expect(t, "Calls[0].LastTradeDate", wallClock(chain.Calls[0].LastTradeDate.Time), "2024-06-14 15:59:58")
+			expect(t, "Calls[0].LastTradeDate location", chain.Calls[0].LastTradeDate.Location().String(), "America/New_York")
+			expect(t, "Puts[0].InTheMoney", chain.Puts[0].InTheMoney, false)
+		},
+	},
+	{
+		name:     "options expiration",
+		fixtures: []string{"options_expiration_AAPL_8fb74132.json"},
+		run: func(t *testing.T, client *twelvedata.APIClient) {
+			resp, err := client.GetOptionsExpiration(twelvedata.OptionsExpirationRequest{Symbol: ptr("AAPL")})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			dates := make([]string, len(resp.Dates))
+			for i, date := range resp.Dates {
+				dates[i] = date.Format("2006-01-02")
+			}
+			expect(t, "Dates", dates, []string{"2024-06-21", "2024-06-28", "2024-07-05", "2024-07-19"})
+		},
+	},
+	{
+		name:     "price",
+		fixtures: []string{"price_AAPL_115a4e11.json"},
+		run: func(t *testing.T, client *twelvedata.APIClient) {
+			price, err := client.GetPrice(twelvedata.PriceRequest{Symbol: ptr("AAPL")})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			expect(t, "Price", price.Price.Float64, 212.49001)
+		},
+	},
+	{
+		name:     "stock quote",
+		fixtures: []string{"quote_AAPL_748a57db.json"},
+		run: func(t *testing.T, client *twelvedata.APIClient) {
+			quote, err := client.GetQuote(twelvedata.QuoteRequest{Symbol: ptr("AAPL")})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			expect(t, "Close", quote.Close.Float64, 212.49001)
+			expect(t, "Volume", quote.Volume.Float64, 70122748.0)
+			expect(t, "FiftyTwoWeek.High", quote.FiftyTwoWeek.High.Float64, 220.2)
+			expect(t, "IsMarketOpen", quote.IsMarketOpen, false)
+			expect(t, "Timestamp", quote.Timestamp.Unix(), int64(1718386200))
+			expect(t, "Timestamp location", quote.Timestamp.Location(), time.UTC)
+		},
+	},
+	{
+		name:     "crypto quote",
+		fixtures: []string{"quote_BTC-USD_090f266d.json"},
+		run: func(t *testing.T, client *twelvedata.APIClient) {
+			quote, err := client.GetQuote(twelvedata.QuoteRequest{Symbol: ptr("BTC/USD")})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			expect(t, "Close", quote.Close.Float64, 66122.42)
+			expect(t, "Volume.Valid", quote.Volume.Valid, false)
+			expect(t, "AverageVolume.Valid", quote.AverageVolume.Valid, false)
+			expect(t, "IsMarketOpen", quote.IsMarketOpen, true)
+		},
+	},
+	{
+		name:     "quote error payload",
+		fixtures: []string{"quote_INVALID_7924c2f9.json"},
+		run: func(t *testing.T, client *twelvedata.APIClient) {
+			quote, err := client.GetQuote(twelvedata.QuoteRequest{Symbol: ptr("INVALID")})
+			expectAPIError(t, err, 404, "not found: INVALID")
+			if quote != nil {
+				t.Errorf("quote = %+v, want nil", quote)
+			}
+		},
+	},
+	{
+		name:     "stocks",
+		fixtures: []string{"stocks_da8c243d.json"},
+		run: func(t *testing.T, client *twelvedata.APIClient) {
+			resp, err := client.GetStocks()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			expect(t, "Count", resp.Count, 2)
+			expect(t, "Data[0]", resp.Data[0], twelvedata.Stocks{
+				Symbol: "AAPL", Name: "Apple Inc", Currency: "USD", Exchange: "NASDAQ", MicCode: "XNGS", Country: "United States",
+				Type: "Common Stock", FigiCode: "BBG000B9Y5X2", CfiCode: "ESVUFR", ISIN: "US0378331005", CUSIP: "037833100",
+			})
+		},
+	},
+	{
+		name:     "CSV stocks",
+		fixtures: []string{"stocks_e54f3961.json"},
+		run: func(t *testing.T, client *twelvedata.APIClient) {
+			req := twelvedata.StocksRequest{Format: ptr(twelvedata.ResponseFormatCSV)}
+			resp, err := client.GetStocksRequest(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			expect(t, "Data", len(resp.Data), 2)
+			expect(t, "Data[1].ISIN", resp.Data[1].ISIN, "US5949181045")
+
+			var streamed []twelvedata.Stocks
+			for stock, err := range client.StreamStocks(req) {
+				if err != nil {
+					t.Fatal(err)
+				}
+				streamed = append(streamed, stock)
+			}
+			expect(t, "streamed", streamed, resp.Data)
+		},
+	},
+	{
+		name:     "symbol search",
+		fixtures: []string{"symbol_search_AA_789013bb.json"},
+		run: func(t *testing.T, client *twelvedata.APIClient) {
+			resp, err := client.GetSymbolSearch(twelvedata.SymbolSearchRequest{Symbol: ptr("AA")})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			expect(t, "Data", len(resp.Data), 3)
+			expect(t, "Data[2].Symbol", resp.Data[2].Symbol, "AAPL")
+			expect(t, "Data[2].ExchangeTimezone", resp.Data[2].ExchangeTimezone, "America/New_York")
+			expect(t, "Data[2].Access", resp.Data[2].Access, (*twelvedata.SymbolSearchAccess)(nil))
+		},
+	},
+	{
+		name:     "daily time series",
+		fixtures: []string{"time_series_AAPL_1bc6a860.json"},
+		run: func(t *testing.T, client *twelvedata.APIClient) {
+			resp, err := client.GetTimeSeries(twelvedata.TimeSeriesRequest{Symbol: ptr("AAPL"), Interval: ptr(twelvedata.TimeSeriesInterval1Day), OutputSize: ptr(1)})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			expect(t, "Meta.Type", resp.Meta.Type, "Common Stock")
+			expect(t, "Candles", len(resp.Candles), 1)
+			expect(t, "Candles[0].DateTime", wallClock(resp.Candles[0].DateTime.Time), "2024-06-14 00:00:00")
+			expect(t, "Candles[0].Close", resp.Candles[0].Close.Float64, 212.49001)
+			expect(t, "Candles[0].Volume", resp.Candles[0].Volume.Float64, 70122748.0)
+		},
+	},
+	{
+		name:     "daily time series of three days",
+		fixtures: []string{"time_series_AAPL_5ac2ebff.json"},
+		run: func(t *testing.T, client *twelvedata.APIClient) {
+			resp, err := client.GetTimeSeries(twelvedata.TimeSeriesRequest{Symbol: ptr("AAPL"), Interval: ptr(twelvedata.TimeSeriesInterval1Day), OutputSize: ptr(3)})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			expect(t, "Candles", len(resp.Candles), 3)
+			expect(t, "Candles[2].DateTime", wallClock(resp.Candles[2].DateTime.Time), "2024-06-12 00:00:00")
+			expect(t, "Candles[2].High", resp.Candles[2].High.Float64, 220.2)
+		},
+	},
+	{
+		name:     "CSV time series",
+		fixtures: []string{"time_series_AAPL_2f02287c.json"},
+		run: func(t *testing.T, client *twelvedata.APIClient) {
+			resp, err := client.GetTimeSeries(twelvedata.TimeSeriesRequest{
+				Symbol:     ptr("AAPL"),
+				Interval:   ptr(twelvedata.TimeSeriesInterval1Min),
+				OutputSize: ptr(5),
+				TimeZone:   ptr("America/New_York"),
+				Format:     ptr(twelvedata.ResponseFormatCSV),
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			expect(t, "Meta.ExchangeTimezone", resp.Meta.ExchangeTimezone, "America/New_York")
+			expect(t, "Candles", len(resp.Candles), 5)
+			expect(t, "Candles[0].DateTime", wallClock(resp.Candles[0].DateTime.Time), "2024-06-14 15:59:00")
+			expect(t, "Candles[0].Close", resp.Candles[0].Close.Float64, 212.49001)
+			expect(t, "Candles[4].Volume", resp.Candles[4].Volume.Float64, 366431.0)
+		},
+	},
+	{
+		name:     "streamed time series",
+		fixtures: []string{"time_series_AAPL_90b7c948.json"},
+		run: func(t *testing.T, client *twelvedata.APIClient) {
+			stream, err := client.StreamTimeSeries(twelvedata.TimeSeriesRequest{Symbol: ptr("AAPL"), Interval: ptr(twelvedata.TimeSeriesInterval1Min), OutputSize: ptr(5)})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var dateTimes []string
+			for candle, err := range stream.Candles() {
+				if err != nil {
+					t.Fatal(err)
+				}
+				dateTimes = append(dateTimes, wallClock(candle.DateTime.Time))
+			}
+
+			expect(t, "Meta.Symbol", stream.Meta.Symbol, "AAPL")
+			expect(t, "candles", dateTimes, []string{
+				"2024-06-14 15:59:00", "2024-06-14 15:58:00", "2024-06-14 15:57:00", "2024-06-14 15:56:00", "2024-06-14 15:55:00",
+			})
+		},
+	},
+	{
+		name:     "intraday time series",
+		fixtures: []string{"time_series_AAPL_ab377d55.json"},
+		run: func(t *testing.T, client *twelvedata.APIClient) {
+			resp, err := client.GetTimeSeries(twelvedata.TimeSeriesRequest{Symbol: ptr("AAPL"), Interval: ptr(twelvedata.TimeSeriesInterval1Min), OutputSize: ptr(1)})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			expect(t, "Candles[0].DateTime", wallClock(resp.Candles[0].DateTime.Time), "2024-06-14 15:59:00")
+			expect(t, "Candles[0].Open", resp.Candles[0].Open.Float64, 212.315)
+		},
+	},
+	{
+		name:     "crypto time series",
+		fixtures: []string{"time_series_BTC-USD_c6e92cb3.json"},
+		run: func(t *testing.T, client *twelvedata.APIClient) {
+			resp, err := client.GetTimeSeries(twelvedata.TimeSeriesRequest{Symbol: ptr("BTC/USD"), Interval: ptr(twelvedata.TimeSeriesInterval1Day), OutputSize: ptr(1)})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			expect(t, "Meta.CurrencyBase", resp.Meta.CurrencyBase, "Bitcoin")
+			expect(t, "Candles[0].DateTime", resp.Candles[0].DateTime.Time, time.Date(2024, 6, 14, 0, 0, 0, 0, time.UTC))
+			expect(t, "Candles[0].Volume.Valid", resp.Candles[0].Volume.Valid, false)
+		},
+	},
+	{
+		name:     "time series error payload",
+		fixtures: []string{"time_series_AAPL_9441f8c7.json"},
+		run: func(t *testing.T, client *twelvedata.APIClient) {
+			_, err := client.GetTimeSeries(twelvedata.TimeSeriesRequest{Symbol: ptr("AAPL"), Interval: ptr(twelvedata.TimeSeriesInterval("2min"))})
+			expectAPIError(t, err, 400, "not supported: 2min")
+		},
+	},
+	{
+		name:     "CSV time series error payload",
+		fixtures: []string{"time_series_INVALID_65bf86d2.json"},
+		run: func(t *testing.T, client *twelvedata.APIClient) {
+			_, err := client.GetTimeSeries(twelvedata.TimeSeriesRequest{
+				Symbol:   ptr("INVALID"),
+				Interval: ptr(twelvedata.TimeSeriesInterval1Day),
+				TimeZone: ptr("America/New_York"),
+				Format:   ptr(twelvedata.ResponseFormatCSV),
+			})
+			expectAPIError(t, err, 404, "not found: INVALID")
+		},
+	},
+}
+
+// fixtureRequest reads the request of a bundled fixture
+func fixtureRequest(t *testing.T, name string) tdtest.FixtureRequest {
+	t.Helper()
+
+	data, err := fs.ReadFile(tdtest.Fixtures(), name)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var fixture tdtest.Fixture
+	if err := jsoniter.Unmarshal(data, &fixture); err != nil {
+		t.Fatal(err)
+	}
+
+	return fixture.Request
+}
+
+func TestFixtures(t *testing.T) {
+	for _, tt := range fixtureCases {
+		t.Run(tt.name, func(t *testing.T) {
+			replayer, err := tdtest.NewReplayer(tdtest.Fixtures(), tdtest.MatchExact)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			client, err := replayer.Client()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			tt.run(t, client)
+
+			// The requests of the case must have been answered by its fixtures
+			calls := replayer.Calls()
+			for _, name := range tt.fixtures {
+				request := fixtureRequest(t, name)
+
+				answered := false
+				for _, call := range calls {
+					answered = answered || (call.Path == request.Path && maps.Equal(call.Params, request.Params))
+				}
+				if !answered {
+					t.Errorf("fixture %s wasn't requested, requests were %v", name, calls)
+				}
+			}
+		})
+	}
+}
+
+func TestFixturesCovered(t *testing.T) {
+	covered := make(map[string]bool)
+	for _, tt := range fixtureCases {
+		for _, name := range tt.fixtures {
+			covered[name] = true
+		}
+	}
+
+	entries, err := fs.ReadDir(tdtest.Fixtures(), ".")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, entry := range entries {
+		if !covered[entry.Name()] {
+			t.Errorf("fixture %s isn't replayed by any case of TestFixtures", entry.Name())
+		}
+	}
+}
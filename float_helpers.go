@@ -0,0 +1,192 @@
+package twelvedata
+
+import (
+	"bytes"
+	"math/big"
+	"strconv"
+	"strings"
+	"unsafe"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/modern-go/reflect2"
+	"github.com/pkg/errors"
+)
+
+// Decimal is a number exactly as the API sent it (e.g. "1.08310"), before any float64 rounding
+type Decimal string
+
+// Float64 converts the decimal to the nearest float64
+func (d Decimal) Float64() (float64, error) {
+	return strconv.ParseFloat(string(d), 64)
+}
+
+// Rat converts the decimal to an exact rational number. ok is false when the decimal is empty or malformed.
+func (d Decimal) Rat() (rat *big.Rat, ok bool) {
+	return new(big.Rat).SetString(string(d))
+}
+
+func (d Decimal) String() string {
+	return string(d)
+}
+
+// TDFloat is a number the TwelveData API may send as a string, a bare number, null or not at all. Valid is false
+// when the value was not provided, so it can be told apart from zero.
+type TDFloat struct {
+	Float64 float64
+	Valid   bool
+	Decimal Decimal // Exact value sent by the API, only kept when decimals are preserved (see Config.PreserveDecimals)
+}
+
+// NewTDFloat returns a provided value
+func NewTDFloat(value float64) TDFloat {
+	return TDFloat{Float64: value, Valid: true}
+}
+
+// tdFloatFromPtr is the inverse of TDFloat.Ptr
+func tdFloatFromPtr(value *float64) TDFloat {
+	if value == nil {
+		return TDFloat{}
+	}
+	return NewTDFloat(*value)
+}
+
+// maxTDFloat returns the largest provided value
+func maxTDFloat(a, b TDFloat) TDFloat {
+	if !a.Valid || (b.Valid && b.Float64 > a.Float64) {
+		return b
+	}
+	return a
+}
+
+// minTDFloat returns the smallest provided value
+func minTDFloat(a, b TDFloat) TDFloat {
+	if !a.Valid || (b.Valid && b.Float64 < a.Float64) {
+		return b
+	}
+	return a
+}
+
+// addTDFloat sums the provided values. The sum is only missing when both values are.
+func addTDFloat(a, b TDFloat) TDFloat {
+	if !a.Valid {
+		return b
+	}
+	if !b.Valid {
+		return a
+	}
+	return NewTDFloat(a.Float64 + b.Float64)
+}
+
+// Or returns the value, or fallback when it was not provided
+func (f TDFloat) Or(fallback float64) float64 {
+	if !f.Valid {
+		return fallback
+	}
+	return f.Float64
+}
+
+// Ptr returns a pointer to the value, or nil when it was not provided
+func (f TDFloat) Ptr() *float64 {
+	if !f.Valid {
+		return nil
+	}
+	value := f.Float64
+	return &value
+}
+
+// String returns the exact decimal when it was kept, otherwise formats the value like the API does. It returns ""
+// when the value was not provided.
+func (f TDFloat) String() string {
+	if !f.Valid {
+		return ""
+	}
+	if f.Decimal != "" {
+		return string(f.Decimal)
+	}
+	return strconv.FormatFloat(f.Float64, 'f', -1, 64)
+}
+
+func (f *TDFloat) UnmarshalJSON(data []byte) error {
+	str := string(data)
+	if len(str) >= 2 && str[0] == '"' && str[len(str)-1] == '"' {
+		str = str[1 : len(str)-1]
+	}
+
+	// Leave the value invalid for missing values
+	if str == "null" || str == "" {
+		*f = TDFloat{}
+		return nil
+	}
+
+	value, err := strconv.ParseFloat(str, 64)
+	if err != nil {
+		return errors.New("invalid number format encountered when parsing response from TwelveData API: " + str)
+	}
+
+	*f = NewTDFloat(value)
+	return nil
+}
+
+// MarshalJSON writes the value as a string, like the API does, or null when it was not provided
+func (f TDFloat) MarshalJSON() ([]byte, error) {
+	if !f.Valid {
+		return []byte("null"), nil
+	}
+	return []byte(strconv.Quote(f.String())), nil
+}
+
+// decimalJSON decodes like jsoniter.ConfigDefault, but keeps the exact decimal of every TDFloat
+var decimalJSON = func() jsoniter.API {
+	api := jsoniter.Config{EscapeHTML: true}.Froze()
+	api.RegisterExtension(&decimalExtension{})
+	return api
+}()
+
+var tdFloatType = reflect2.TypeOf(TDFloat{})
+
+type decimalExtension struct {
+	jsoniter.DummyExtension
+}
+
+func (e *decimalExtension) CreateDecoder(typ reflect2.Type) jsoniter.ValDecoder {
+	if typ == tdFloatType {
+		return decimalDecoder{}
+	}
+	return nil
+}
+
+type decimalDecoder struct{}
+
+func (decimalDecoder) Decode(ptr unsafe.Pointer, iter *jsoniter.Iterator) {
+	raw := bytes.TrimSpace(iter.SkipAndReturnBytes())
+	if iter.Error != nil {
+		return
+	}
+
+	f := (*TDFloat)(ptr)
+	if err := f.UnmarshalJSON(raw); err != nil {
+		iter.ReportError("decode TDFloat", err.Error())
+		return
+	}
+
+	if f.Valid {
+		f.Decimal = Decimal(strings.Trim(string(raw), `"`))
+	}
+}
+
+// preservesDecimals tells whether exact decimals are kept for a request, given its override (nil for the client
+// default)
+func (c *APIClient) preservesDecimals(override *bool) bool {
+	if override != nil {
+		return *override
+	}
+	return c.preserveDecimals
+}
+
+// jsonAPI returns the JSON configuration decoding responses, see preservesDecimals
+func (c *APIClient) jsonAPI(override *bool) jsoniter.API {
+	if c.preservesDecimals(override) {
+		return decimalJSON
+	}
+	return jsoniter.ConfigDefault
+}
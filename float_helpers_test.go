@@ -0,0 +1,221 @@
+package twelvedata_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/jonnotjohn/twelvedata-go"
+	"github.com/jonnotjohn/twelvedata-go/tdtest"
+	jsoniter "github.com/json-iterator/go"
+)
+
+func TestTDFloatUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		json string
+		want twelvedata.TDFloat
+		err  bool
+	}{
+		{json: `"212.49001"`, want: twelvedata.NewTDFloat(212.49001)},
+		{json: `212.49001`, want: twelvedata.NewTDFloat(212.49001)},
+		{json: `"0"`, want: twelvedata.NewTDFloat(0)},
+		{json: `0`, want: twelvedata.NewTDFloat(0)},
+		{json: `"-1.5e-3"`, want: twelvedata.NewTDFloat(-0.0015)},
+		{json: `null`},
+		{json: `""`},
+		{json: `"n/a"`, err: true},
+		{json: `true`, err: true},
+	}
+
+	for _, tt := range tests {
+		// Both the standard library and jsoniter call UnmarshalJSON
+		for name, unmarshal := range map[string]func([]byte, any) error{"encoding/json": json.Unmarshal, "jsoniter": jsoniter.Unmarshal} {
+			// A value left from before has to be reset by null and empty values
+			var got struct {
+				Value twelvedata.TDFloat `json:"value"`
+			}
+			got.Value = twelvedata.NewTDFloat(-1)
+			err := unmarshal([]byte(`{"value":`+tt.json+`}`), &got)
+			if tt.err {
+				if err == nil {
+					t.Errorf("%s: %s decoded to %+v, want an error", name, tt.json, got.Value)
+				}
+				continue
+			}
+			if err != nil {
+				t.Errorf("%s: %s error = %v", name, tt.json, err)
+				continue
+			}
+
+			expect(t, name+": "+tt.json, got.Value, tt.want)
+		}
+	}
+}
+
+func TestTDFloatRoundTrip(t *testing.T) {
+	values := []twelvedata.TDFloat{
+		twelvedata.NewTDFloat(212.49001),
+		twelvedata.NewTDFloat(0),
+		twelvedata.NewTDFloat(-0.0015),
+		twelvedata.NewTDFloat(70122748),
+		{},
+	}
+
+	for _, value := range values {
+		data, err := json.Marshal(value)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var read twelvedata.TDFloat
+		if err := json.Unmarshal(data, &read); err != nil {
+			t.Fatalf("%s: %v", data, err)
+		}
+		expect(t, "read back "+string(data), read, value)
+	}
+}
+
+func TestQuoteMissingNumbers(t *testing.T) {
+	// Crypto quotes have no volume and no extended hours fields
+	client := handlerClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"symbol":"BTC/USD","name":"Bitcoin US Dollar","exchange":"Coinbase Pro","datetime":"2024-06-14",` +
+			`"timestamp":1718323200,"open":"66747.19","high":"67300","low":"65076.31","close":"66011.1","volume":null,` +
+			`"previous_close":"66747.19","change":"-736.09","percent_change":"-1.10280","average_volume":"",` +
+			`"is_market_open":true,"fifty_two_week":{"low":"24763.41","high":"73835.57","low_change":"41247.69",` +
+			`"high_change":null,"range":"24763.41 - 73835.57"}}`))
+	}))
+
+	quote, err := client.GetQuote(twelvedata.QuoteRequest{Symbol: ptr("BTC/USD")})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect(t, "Close", quote.Close, twelvedata.NewTDFloat(66011.1))
+	expect(t, "Change", quote.Change, twelvedata.NewTDFloat(-736.09))
+	for name, value := range map[string]twelvedata.TDFloat{
+		"Volume":                        quote.Volume,
+		"AverageVolume":                 quote.AverageVolume,
+		"ExtendedPrice":                 quote.ExtendedPrice,
+		"ExtendedChange":                quote.ExtendedChange,
+		"FiftyTwoWeek.HighChange":       quote.FiftyTwoWeek.HighChange,
+		"FiftyTwoWeek.LowChangePercent": quote.FiftyTwoWeek.LowChangePercent,
+	} {
+		if value.Valid {
+			t.Errorf("%s = %+v, want it not provided", name, value)
+		}
+	}
+	expect(t, "FiftyTwoWeek.LowChange", quote.FiftyTwoWeek.LowChange, twelvedata.NewTDFloat(41247.69))
+
+	// Missing values are written as null, so the quote reads back the same
+	data, err := json.Marshal(quote)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"volume":null`) {
+		t.Errorf("marshaled quote %s, want a null volume", data)
+	}
+
+	var read twelvedata.Quote
+	if err := json.Unmarshal(data, &read); err != nil {
+		t.Fatal(err)
+	}
+	expect(t, "Volume", read.Volume, quote.Volume)
+	expect(t, "FiftyTwoWeek", read.FiftyTwoWeek, quote.FiftyTwoWeek)
+}
+
+// decimalClient creates an API client keeping exact decimals by default, answering every request with body
+func decimalClient(t *testing.T, body *string) *twelvedata.APIClient {
+	t.Helper()
+
+	retryCount := 1
+	client, err := twelvedata.NewAPIClient(twelvedata.Config{
+		APIKey:           "test",
+		RetryCount:       &retryCount,
+		PreserveDecimals: true,
+		Doer: tdtest.HandlerDoer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(*body))
+		})),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return client
+}
+
+func TestPreserveDecimals(t *testing.T) {
+	body := `{"symbol":"EUR/USD","datetime":"2024-06-14","open":"1.07000","high":1.07120,"low":"1.06680","close":"1.07030",` +
+		`"volume":null,"previous_close":"","is_market_open":true,"fifty_two_week":{"low":"1.04480"}}`
+	client := decimalClient(t, &body)
+
+	quote, err := client.GetQuote(twelvedata.QuoteRequest{Symbol: ptr("EUR/USD")})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Strings and bare numbers keep their trailing zeros, missing values have no decimal
+	expect(t, "Open", quote.Open, twelvedata.TDFloat{Float64: 1.07, Valid: true, Decimal: "1.07000"})
+	expect(t, "High", quote.High, twelvedata.TDFloat{Float64: 1.0712, Valid: true, Decimal: "1.07120"})
+	expect(t, "FiftyTwoWeek.Low", quote.FiftyTwoWeek.Low, twelvedata.TDFloat{Float64: 1.0448, Valid: true, Decimal: "1.04480"})
+	expect(t, "Volume", quote.Volume, twelvedata.TDFloat{})
+	expect(t, "PreviousClose", quote.PreviousClose, twelvedata.TDFloat{})
+	expect(t, "Close.String()", quote.Close.String(), "1.07030")
+	if rat, ok := quote.Close.Decimal.Rat(); !ok || rat.FloatString(5) != "1.07030" {
+		t.Errorf("Close.Decimal.Rat() = %v, %v, want exactly 1.07030", rat, ok)
+	}
+
+	// The decimals are written back as they were read
+	data, err := json.Marshal(quote)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body = string(data)
+
+	read, err := client.GetQuote(twelvedata.QuoteRequest{Symbol: ptr("EUR/USD")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect(t, "read back Open", read.Open, quote.Open)
+	expect(t, "read back High", read.High, quote.High)
+	expect(t, "read back Volume", read.Volume, quote.Volume)
+
+	// Requests can turn decimals off
+	plain, err := client.GetQuote(twelvedata.QuoteRequest{Symbol: ptr("EUR/USD"), PreserveDecimals: ptr(false)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect(t, "Open without decimals", plain.Open, twelvedata.NewTDFloat(1.07))
+}
+
+func TestPreserveDecimalsConversions(t *testing.T) {
+	body := `{"symbol":"BTC/USD","rate":"66011.10000000","amount":"0.00000001000","timestamp":1718323200}`
+	client := decimalClient(t, &body)
+
+	conversion, err := client.GetCurrencyConversion(twelvedata.CurrencyConversionRequest{Symbol: ptr("BTC/USD"), Amount: ptr(1.0)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect(t, "Rate.Decimal", conversion.Rate.Decimal, twelvedata.Decimal("66011.10000000"))
+	expect(t, "Amount.Decimal", conversion.Amount.Decimal, twelvedata.Decimal("0.00000001000"))
+
+	rate, err := client.GetExchangeRate(twelvedata.ExchangeRateRequest{Symbol: ptr("BTC/USD")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect(t, "Rate.Decimal", rate.Rate.Decimal, twelvedata.Decimal("66011.10000000"))
+
+	value, err := rate.Rate.Decimal.Float64()
+	if err != nil || value != 66011.1 {
+		t.Errorf("Rate.Decimal.Float64() = %v, %v, want 66011.1", value, err)
+	}
+
+	body = `{"meta":{"symbol":"BTC/USD","interval":"1day","exchange_timezone":"UTC"},` +
+		`"values":[{"datetime":"2024-06-14","open":"66747.19000","high":"67300.00000","low":"65076.31000","close":"66011.10000"}],"status":"ok"}`
+	series, err := client.GetTimeSeries(twelvedata.TimeSeriesRequest{Symbol: ptr("BTC/USD"), Interval: ptr(twelvedata.TimeSeriesInterval1Day)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect(t, "High.Decimal", series.Candles[0].High.Decimal, twelvedata.Decimal("67300.00000"))
+	expect(t, "Volume", series.Candles[0].Volume, twelvedata.TDFloat{})
+}
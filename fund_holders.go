@@ -0,0 +1,40 @@
+package twelvedata
+
+import (
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+)
+
+const (
+	urlEndpointFundHolders = "/fund_holders"
+)
+
+type FundHoldersResponse struct {
+	Meta        FundamentalsMeta `json:"meta"`
+	FundHolders []Holder         `json:"fund_holders"`
+}
+
+func (c *APIClient) GetFundHolders(req FundamentalsRequest) (fundHolders *FundHoldersResponse, err error) {
+	params, err := req.ToParams()
+	if err != nil {
+		return nil, errors.Wrap(err, "Error converting FundamentalsRequest to params")
+	}
+
+	data, err := c.Client.Get(urlEndpointFundHolders, params)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error fetching fund holders data")
+	}
+
+	err = c.Client.unmarshal(jsoniter.ConfigDefault, data, &fundHolders, "fund holders")
+	if err != nil {
+		return nil, err
+	}
+
+	if fundHolders != nil {
+		if err := localizeHolders(fundHolders.FundHolders, fundHolders.Meta); err != nil {
+			return nil, errors.Wrap(err, "Error localizing fund holders response")
+		}
+	}
+
+	return fundHolders, nil
+}
@@ -0,0 +1,51 @@
+package twelvedata
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// FundamentalsRequest is the available parameters for the fundamentals endpoints
+// (insider transactions, institutional holders, fund holders, key executives)
+type FundamentalsRequest struct {
+	Symbol   *string // Required: Symbol of the asset (e.g. "AAPL")
+	FIGI     *string // Financial Instrument Global Identifier
+	ISIN     *string // International Securities Identification Number
+	CUSIP    *string // Committee on Uniform Securities Identification Procedures
+	Exchange *string // Exchange code (e.g. "NASDAQ")
+	MicCode  *string // Market Identifier Code (e.g. "XNAS" for NASDAQ)
+	Country  *string // Country code (e.g. "US" or "United States")
+}
+
+func (req FundamentalsRequest) ToParams() (map[string]string, error) {
+	params := make(map[string]string)
+
+	if req.Symbol == nil {
+		return nil, errors.New("symbol is required")
+	}
+
+	AddStringParam(params, "symbol", req.Symbol)
+	AddStringParam(params, "figi", req.FIGI)
+	AddStringParam(params, "isin", req.ISIN)
+	AddStringParam(params, "cusip", req.CUSIP)
+	AddStringParam(params, "exchange", req.Exchange)
+	AddStringParam(params, "mic_code", req.MicCode)
+	AddStringParam(params, "country", req.Country)
+
+	return params, nil
+}
+
+type FundamentalsMeta struct {
+	Symbol           string `json:"symbol"`
+	Name             string `json:"name"`
+	Currency         string `json:"currency"`
+	Exchange         string `json:"exchange"`
+	MicCode          string `json:"mic_code"`
+	ExchangeTimezone string `json:"exchange_timezone"`
+}
+
+// Location returns the exchange timezone of the meta block, or UTC when it is empty
+func (m FundamentalsMeta) Location() (*time.Location, error) {
+	return resolveLocation(nil, m.ExchangeTimezone)
+}
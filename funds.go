@@ -0,0 +1,396 @@
+package twelvedata
+
+import (
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+)
+
+const (
+	urlEndpointFundsWorld             = "/world"
+	urlEndpointFundsWorldSummary      = "/world/summary"
+	urlEndpointFundsWorldPerformance  = "/world/performance"
+	urlEndpointFundsWorldRisk         = "/world/risk"
+	urlEndpointFundsWorldRatings      = "/world/ratings"
+	urlEndpointFundsWorldComposition  = "/world/composition"
+	urlEndpointFundsWorldPurchaseInfo = "/world/purchase_info"
+)
+
+// FundType selects the fund family queried by the fund world endpoints
+type FundType string
+
+const (
+	FundTypeMutualFund FundType = "mutual_funds"
+	FundTypeETF        FundType = "etfs"
+)
+
+// ErrNotApplicable is returned for fund data that the API doesn't provide for the fund type, e.g. the ratings of an ETF
+var ErrNotApplicable = errors.New("not available for the fund type")
+
+// FundRequest is the available parameters for the mutual fund and ETF world endpoints
+type FundRequest struct {
+	Type    *FundType // Required: Fund family ("mutual_funds" or "etfs")
+	Symbol  *string   // Required: Symbol of the fund (e.g. "VFIAX", "IVV")
+	FIGI    *string   // Financial Instrument Global Identifier
+	ISIN    *string   // International Securities Identification Number
+	CUSIP   *string   // Committee on Uniform Securities Identification Procedures
+	Country *string   // Country code (e.g. "US" or "United States")
+	DP      *int      // Number of decimal places for float values. Supports 0-11, default is 5
+}
+
+func (req FundRequest) ToParams() (map[string]string, error) {
+	params := make(map[string]string)
+
+	if req.Type == nil {
+		return nil, errors.New("fund type is required")
+	}
+
+	if *req.Type != FundTypeMutualFund && *req.Type != FundTypeETF {
+		return nil, errors.Errorf("invalid fund type %q", *req.Type)
+	}
+
+	if req.Symbol == nil {
+		return nil, errors.New("symbol is required")
+	}
+
+	AddStringParam(params, "symbol", req.Symbol)
+	AddStringParam(params, "figi", req.FIGI)
+	AddStringParam(params, "isin", req.ISIN)
+	AddStringParam(params, "cusip", req.CUSIP)
+	AddStringParam(params, "country", req.Country)
+
+	AddIntParam(params, "dp", req.DP)
+
+	return params, nil
+}
+
+type FundSummary struct {
+	Symbol                  string        `json:"symbol"`
+	Name                    string        `json:"name"`
+	FundFamily              string        `json:"fund_family"`                // Fund family (e.g. "Vanguard")
+	FundType                string        `json:"fund_type"`                  // Morningstar category (e.g. "Large Blend")
+	Currency                string        `json:"currency"`                   // Currency code (e.g. "USD")
+	ShareClassInceptionDate TDTime        `json:"share_class_inception_date"` // Inception date of the share class, see getFund
+	YTDReturn               float64       `json:"ytd_return"`                 // Year to date return (e.g. 0.05 for 5%)
+	ExpenseRatioNet         float64       `json:"expense_ratio_net"`          // Net expense ratio
+	Yield                   float64       `json:"yield"`                      // Trailing twelve month yield
+	NAV                     float64       `json:"nav"`                        // Net asset value
+	LastPrice               float64       `json:"last_price"`                 // Last traded price (ETFs only)
+	MinInvestment           float64       `json:"min_investment"`             // Minimum initial investment (mutual funds only)
+	TurnoverRate            float64       `json:"turnover_rate"`              // Annual portfolio turnover rate
+	NetAssets               float64       `json:"net_assets"`                 // Total net assets of the fund
+	Overview                string        `json:"overview"`                   // Description of the fund's strategy
+	People                  []FundManager `json:"people"`                     // Fund managers (mutual funds only)
+}
+
+type FundManager struct {
+	Name        string `json:"name"`
+	TenureSince TDTime `json:"tenure_since"` // Date the manager started managing the fund, see getFund
+}
+
+type FundPerformance struct {
+	TrailingReturns       []FundTrailingReturn     `json:"trailing_returns"`
+	AnnualTotalReturns    []FundAnnualReturn       `json:"annual_total_returns"`
+	QuarterlyTotalReturns []FundQuarterlyReturn    `json:"quarterly_total_returns"` // Mutual funds only
+	LoadAdjustedReturns   []FundLoadAdjustedReturn `json:"load_adjusted_return"`    // Mutual funds only
+}
+
+type FundTrailingReturn struct {
+	Period           string  `json:"period"`             // Return period (e.g. "ytd", "1_year", "5_year")
+	ShareClassReturn float64 `json:"share_class_return"` // Return of the share class over the period
+	CategoryReturn   float64 `json:"category_return"`    // Average return of the fund's category over the period
+	RankInCategory   int     `json:"rank_in_category"`   // Percentile rank within the category (mutual funds only)
+}
+
+type FundAnnualReturn struct {
+	Year             int     `json:"year"`
+	ShareClassReturn float64 `json:"share_class_return"`
+	CategoryReturn   float64 `json:"category_return"`
+}
+
+type FundQuarterlyReturn struct {
+	Year int     `json:"year"`
+	Q1   float64 `json:"q1"`
+	Q2   float64 `json:"q2"`
+	Q3   float64 `json:"q3"`
+	Q4   float64 `json:"q4"`
+}
+
+type FundLoadAdjustedReturn struct {
+	Period string  `json:"period"` // Return period (e.g. "1_year")
+	Return float64 `json:"return"`
+}
+
+type FundRisk struct {
+	VolatilityMeasures []FundVolatilityMeasure `json:"volatility_measures"`
+	ValuationMetrics   FundValuationMetrics    `json:"valuation_metrics"`
+}
+
+type FundVolatilityMeasure struct {
+	Period                   string  `json:"period"` // Measurement period (e.g. "3_y", "5_y", "10_y")
+	Alpha                    float64 `json:"alpha"`
+	AlphaCategory            float64 `json:"alpha_category"`
+	Beta                     float64 `json:"beta"`
+	BetaCategory             float64 `json:"beta_category"`
+	MeanAnnualReturn         float64 `json:"mean_annual_return"`
+	MeanAnnualReturnCategory float64 `json:"mean_annual_return_category"`
+	RSquared                 float64 `json:"r_squared"`
+	RSquaredCategory         float64 `json:"r_squared_category"`
+	StdDev                   float64 `json:"std"`
+	StdDevCategory           float64 `json:"std_category"`
+	SharpeRatio              float64 `json:"sharpe_ratio"`
+	SharpeRatioCategory      float64 `json:"sharpe_ratio_category"`
+	TreynorRatio             float64 `json:"treynor_ratio"`
+	TreynorRatioCategory     float64 `json:"treynor_ratio_category"`
+}
+
+type FundValuationMetrics struct {
+	PriceToEarnings                    float64 `json:"price_to_earnings"`
+	PriceToEarningsCategory            float64 `json:"price_to_earnings_category"`
+	PriceToBook                        float64 `json:"price_to_book"`
+	PriceToBookCategory                float64 `json:"price_to_book_category"`
+	PriceToSales                       float64 `json:"price_to_sales"`
+	PriceToSalesCategory               float64 `json:"price_to_sales_category"`
+	PriceToCashflow                    float64 `json:"price_to_cashflow"`
+	PriceToCashflowCategory            float64 `json:"price_to_cashflow_category"`
+	MedianMarketCapitalization         float64 `json:"median_market_capitalization"`
+	MedianMarketCapitalizationCategory float64 `json:"median_market_capitalization_category"`
+	ThreeYearEarningsGrowth            float64 `json:"3_year_earnings_growth"`
+	ThreeYearEarningsGrowthCategory    float64 `json:"3_year_earnings_growth_category"`
+}
+
+// FundRatings holds Morningstar style ratings from 1 (worst) to 5 (best). Mutual funds only.
+type FundRatings struct {
+	PerformanceRating int `json:"performance_rating"`
+	RiskRating        int `json:"risk_rating"`
+	ReturnRating      int `json:"return_rating"`
+}
+
+type FundComposition struct {
+	MajorMarketSectors []FundSectorWeight      `json:"major_market_sectors"`
+	CountryAllocation  []FundCountryAllocation `json:"country_allocation"` // ETFs only
+	AssetAllocation    FundAssetAllocation     `json:"asset_allocation"`
+	TopHoldings        []FundHolding           `json:"top_holdings"`
+	BondBreakdown      FundBondBreakdown       `json:"bond_breakdown"`
+}
+
+type FundSectorWeight struct {
+	Sector string  `json:"sector"` // Sector name (e.g. "Technology")
+	Weight float64 `json:"weight"` // Fraction of the portfolio (e.g. 0.29 for 29%)
+}
+
+type FundCountryAllocation struct {
+	Country    string  `json:"country"`
+	Allocation float64 `json:"allocation"` // Fraction of the portfolio
+}
+
+type FundAssetAllocation struct {
+	Cash            float64 `json:"cash"`
+	Stocks          float64 `json:"stocks"`
+	PreferredStocks float64 `json:"preferred_stocks"`
+	Convertibles    float64 `json:"convertables"`
+	Bonds           float64 `json:"bonds"`
+	Others          float64 `json:"others"`
+}
+
+type FundHolding struct {
+	Symbol   string  `json:"symbol"`
+	Name     string  `json:"name"`
+	Exchange string  `json:"exchange"`
+	MicCode  string  `json:"mic_code"`
+	Weight   float64 `json:"weight"` // Fraction of the portfolio
+}
+
+type FundBondBreakdown struct {
+	AverageMaturity FundVersusCategory  `json:"average_maturity"`
+	AverageDuration FundVersusCategory  `json:"average_duration"`
+	CreditQuality   []FundCreditQuality `json:"credit_quality"`
+}
+
+type FundVersusCategory struct {
+	Fund     float64 `json:"fund"`
+	Category float64 `json:"category"`
+}
+
+type FundCreditQuality struct {
+	Grade  string  `json:"grade"` // Credit grade (e.g. "AAA", "U.S. Government")
+	Weight float64 `json:"weight"`
+}
+
+// FundPurchaseInfo holds the purchase conditions of a fund. Mutual funds only.
+type FundPurchaseInfo struct {
+	Expenses   FundExpenses `json:"expenses"`
+	Minimums   FundMinimums `json:"minimums"`
+	Pricing    FundPricing  `json:"pricing"`
+	Brokerages []string     `json:"brokerages"` // Brokerages where the fund can be purchased
+}
+
+type FundExpenses struct {
+	ExpenseRatioGross float64 `json:"expense_ratio_gross"`
+	ExpenseRatioNet   float64 `json:"expense_ratio_net"`
+}
+
+type FundMinimums struct {
+	InitialInvestment       float64 `json:"initial_investment"`
+	AdditionalInvestment    float64 `json:"additional_investment"`
+	InitialIRAInvestment    float64 `json:"initial_ira_investment"`
+	AdditionalIRAInvestment float64 `json:"additional_ira_investment"`
+}
+
+type FundPricing struct {
+	NAV             float64 `json:"nav"`
+	TwelveMonthLow  float64 `json:"12_month_low"`
+	TwelveMonthHigh float64 `json:"12_month_high"`
+	LastMonth       float64 `json:"last_month"`
+}
+
+// FundFull is the complete profile of a fund. Sections that do not apply to the fund type are left empty.
+type FundFull struct {
+	Summary      FundSummary      `json:"summary"`
+	Performance  FundPerformance  `json:"performance"`
+	Risk         FundRisk         `json:"risk"`
+	Ratings      FundRatings      `json:"ratings"`
+	Composition  FundComposition  `json:"composition"`
+	PurchaseInfo FundPurchaseInfo `json:"purchase_info"`
+}
+
+// fundResponse is the envelope shared by the fund world endpoints. Mutual fund data is returned under "mutual_fund"
+// and ETF data under "etf".
+type fundResponse struct {
+	MutualFund *FundFull `json:"mutual_fund"`
+	ETF        *FundFull `json:"etf"`
+	Status     string    `json:"status"`
+}
+
+// getFund fetches one of the fund world endpoints and returns the decoded fund section. Fund responses have no timezone,
+// so their dates are localized to the exchange timezone of the fund when an earlier time series response cached it,
+// or else to UTC, like GetMarketMovers.
+func (c *APIClient) getFund(req FundRequest, endpoint string, description string) (*FundFull, error) {
+	params, err := req.ToParams()
+	if err != nil {
+		return nil, errors.Wrap(err, "Error converting FundRequest to params")
+	}
+
+	data, err := c.Client.Get("/"+string(*req.Type)+endpoint, params)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error fetching %s data", description)
+	}
+
+	var resp fundResponse
+	err = c.Client.unmarshal(jsoniter.ConfigDefault, data, &resp, description)
+	if err != nil {
+		return nil, err
+	}
+
+	fund := resp.MutualFund
+	if *req.Type == FundTypeETF {
+		fund = resp.ETF
+	}
+
+	if fund == nil {
+		return nil, errors.Errorf("Error unmarshalling %s response: missing fund data", description)
+	}
+
+	fund.Summary = fund.Summary.Localize(c.cachedListingLocation(
+		TimeSeriesRequest{Symbol: req.Symbol, Country: req.Country},
+		TimeSeriesRequest{Symbol: req.Symbol},
+	))
+
+	return fund, nil
+}
+
+// Localize returns the summary with its dates in loc, see TDTime.Localize
+func (s FundSummary) Localize(loc *time.Location) FundSummary {
+	s.ShareClassInceptionDate = s.ShareClassInceptionDate.Localize(loc)
+
+	if s.People != nil {
+		people := make([]FundManager, len(s.People))
+		for i, manager := range s.People {
+			manager.TenureSince = manager.TenureSince.Localize(loc)
+			people[i] = manager
+		}
+		s.People = people
+	}
+
+	return s
+}
+
+func (c *APIClient) GetFundSummary(req FundRequest) (*FundSummary, error) {
+	fund, err := c.getFund(req, urlEndpointFundsWorldSummary, "fund summary")
+	if err != nil {
+		return nil, err
+	}
+
+	return &fund.Summary, nil
+}
+
+func (c *APIClient) GetFundPerformance(req FundRequest) (*FundPerformance, error) {
+	fund, err := c.getFund(req, urlEndpointFundsWorldPerformance, "fund performance")
+	if err != nil {
+		return nil, err
+	}
+
+	return &fund.Performance, nil
+}
+
+func (c *APIClient) GetFundRisk(req FundRequest) (*FundRisk, error) {
+	fund, err := c.getFund(req, urlEndpointFundsWorldRisk, "fund risk")
+	if err != nil {
+		return nil, err
+	}
+
+	return &fund.Risk, nil
+}
+
+// GetFundRatings is only available for mutual funds. For ETFs it returns ErrNotApplicable without a request.
+func (c *APIClient) GetFundRatings(req FundRequest) (*FundRatings, error) {
+	if err := mutualFundsOnly(req, "fund ratings"); err != nil {
+		return nil, err
+	}
+
+	fund, err := c.getFund(req, urlEndpointFundsWorldRatings, "fund ratings")
+	if err != nil {
+		return nil, err
+	}
+
+	return &fund.Ratings, nil
+}
+
+func (c *APIClient) GetFundComposition(req FundRequest) (*FundComposition, error) {
+	fund, err := c.getFund(req, urlEndpointFundsWorldComposition, "fund composition")
+	if err != nil {
+		return nil, err
+	}
+
+	return &fund.Composition, nil
+}
+
+// GetFundPurchaseInfo is only available for mutual funds. For ETFs it returns ErrNotApplicable without a request.
+func (c *APIClient) GetFundPurchaseInfo(req FundRequest) (*FundPurchaseInfo, error) {
+	if err := mutualFundsOnly(req, "fund purchase info"); err != nil {
+		return nil, err
+	}
+
+	fund, err := c.getFund(req, urlEndpointFundsWorldPurchaseInfo, "fund purchase info")
+	if err != nil {
+		return nil, err
+	}
+
+	return &fund.PurchaseInfo, nil
+}
+
+// mutualFundsOnly returns ErrNotApplicable for the data of description when req is for an ETF
+func mutualFundsOnly(req FundRequest, description string) error {
+	if req.Type != nil && *req.Type == FundTypeETF {
+		return errors.Wrapf(ErrNotApplicable, "Error fetching %s data for %s", description, *req.Type)
+	}
+
+	return nil
+}
+
+// GetFundFull fetches the complete fund profile (summary, performance, risk, ratings, composition including holdings
+// and sector weights, and purchase info) in a single call.
+func (c *APIClient) GetFundFull(req FundRequest) (*FundFull, error) {
+	return c.getFund(req, urlEndpointFundsWorld, "fund")
+}
@@ -0,0 +1,52 @@
+package twelvedata_test
+
+import (
+	"testing"
+
+	"github.com/jonnotjohn/twelvedata-go"
+	"github.com/jonnotjohn/twelvedata-go/tdtest"
+	"github.com/pkg/errors"
+)
+
+func TestMutualFundsOnly(t *testing.T) {
+	replayer, err := tdtest.NewReplayer(tdtest.Fixtures(), tdtest.MatchExact)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client, err := replayer.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		get  func(req twelvedata.FundRequest) (any, error)
+	}{
+		{name: "ratings", get: func(req twelvedata.FundRequest) (any, error) { return client.GetFundRatings(req) }},
+		{name: "purchase info", get: func(req twelvedata.FundRequest) (any, error) { return client.GetFundPurchaseInfo(req) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			symbol := "IVV"
+			etf := twelvedata.FundTypeETF
+			_, err := tt.get(twelvedata.FundRequest{Type: &etf, Symbol: &symbol})
+			if !errors.Is(err, twelvedata.ErrNotApplicable) {
+				t.Errorf("ETF error = %v, want ErrNotApplicable", err)
+			}
+
+			symbol = "VFIAX"
+			mutualFund := twelvedata.FundTypeMutualFund
+			if _, err := tt.get(twelvedata.FundRequest{Type: &mutualFund, Symbol: &symbol}); err != nil {
+				t.Errorf("mutual fund error = %v", err)
+			}
+		})
+	}
+
+	// Only the mutual fund requests reach the API
+	calls := replayer.Calls()
+	if len(calls) != len(tests) {
+		t.Errorf("requests = %v, want %d mutual fund requests", calls, len(tests))
+	}
+}
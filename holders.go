@@ -0,0 +1,63 @@
+package twelvedata
+
+import (
+	"sort"
+)
+
+type Holder struct {
+	EntityName   string  `json:"entity_name"`   // Name of the holder (e.g. "Vanguard Group, Inc. (The)")
+	DateReported TDTime  `json:"date_reported"` // Date the holding was reported
+	Shares       float64 `json:"shares"`        // Number of shares held
+	Value        float64 `json:"value"`         // Total value of the holding
+	PercentOut   float64 `json:"percent_out"`   // Fraction of shares outstanding held (e.g. 0.0804 for 8.04%)
+}
+
+// localizeHolders localizes the report dates of holders to the exchange timezone of meta
+func localizeHolders(holders []Holder, meta FundamentalsMeta) error {
+	loc, err := meta.Location()
+	if err != nil {
+		return err
+	}
+
+	for i := range holders {
+		holders[i].DateReported = holders[i].DateReported.Localize(loc)
+	}
+
+	return nil
+}
+
+// HolderConcentration is the share of outstanding stock held by the largest holders
+type HolderConcentration struct {
+	Holders    []Holder // Top holders, ordered by PercentOut descending
+	PercentOut float64  // Combined fraction of shares outstanding held by Holders
+	Shares     float64  // Combined number of shares held by Holders
+	Value      float64  // Combined value of the holdings of Holders
+}
+
+// ComputeTopHolderConcentration returns the combined holdings of the n largest holders by PercentOut.
+// The input slice is not modified.
+func ComputeTopHolderConcentration(holders []Holder, n int) HolderConcentration {
+	sorted := make([]Holder, len(holders))
+	copy(sorted, holders)
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].PercentOut > sorted[j].PercentOut
+	})
+
+	if n < 0 {
+		n = 0
+	}
+
+	if n < len(sorted) {
+		sorted = sorted[:n]
+	}
+
+	result := HolderConcentration{Holders: sorted}
+	for _, h := range sorted {
+		result.PercentOut += h.PercentOut
+		result.Shares += h.Shares
+		result.Value += h.Value
+	}
+
+	return result
+}
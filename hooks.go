@@ -0,0 +1,149 @@
+package twelvedata
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Hooks observes the requests sent by the client, e.g. to record metrics or traces. The methods are called
+// synchronously from the goroutine sending the request and must not modify the events. Embed NopHooks to implement
+// only some of them.
+type Hooks interface {
+	// BeforeRequest is called once before the first attempt of a request. The returned context is passed to the
+	// other hooks of the request and sent with the HTTP requests.
+	BeforeRequest(ctx context.Context, event RequestEvent) context.Context
+	// OnRetry is called after a failed attempt that will be retried
+	OnRetry(ctx context.Context, event ResponseEvent)
+	// AfterResponse is called once after the last attempt of a request
+	AfterResponse(ctx context.Context, event ResponseEvent)
+	// OnDecodeError is called when the body of a successful response can't be decoded
+	OnDecodeError(ctx context.Context, event DecodeErrorEvent)
+}
+
+// RequestEvent describes a request about to be sent
+type RequestEvent struct {
+	Endpoint string            // Path of the endpoint (e.g. "/quote")
+	Params   map[string]string // Query parameters without the API key
+}
+
+// ResponseEvent describes the outcome of an attempt
+type ResponseEvent struct {
+	Endpoint    string
+	Params      map[string]string
+	Attempt     int           // Number of the attempt, starting at 1
+	Duration    time.Duration // Time since the request started, including earlier attempts and waits
+	StatusCode  int           // HTTP status, 0 when no response was received
+	Err         error         // Transport error, nil when a response was received
+	APIError    *APIError     // Error payload the API answered with, even with status 200. Nil for streamed bodies
+	CreditsUsed int           // From the api-credits-used header, -1 when missing
+	CreditsLeft int           // From the api-credits-left header, -1 when missing
+}
+
+// DecodeErrorEvent describes a response body that couldn't be decoded
+type DecodeErrorEvent struct {
+	Endpoint string
+	Params   map[string]string
+	Err      error
+}
+
+// NopHooks implements Hooks with methods that do nothing
+type NopHooks struct{}
+
+func (NopHooks) BeforeRequest(ctx context.Context, _ RequestEvent) context.Context { return ctx }
+func (NopHooks) OnRetry(context.Context, ResponseEvent)                            {}
+func (NopHooks) AfterResponse(context.Context, ResponseEvent)                      {}
+func (NopHooks) OnDecodeError(context.Context, DecodeErrorEvent)                   {}
+
+// MultiHooks calls each of hooks in order
+func MultiHooks(hooks ...Hooks) Hooks {
+	return multiHooks(hooks)
+}
+
+type multiHooks []Hooks
+
+func (m multiHooks) BeforeRequest(ctx context.Context, event RequestEvent) context.Context {
+	for _, h := range m {
+		ctx = h.BeforeRequest(ctx, event)
+	}
+	return ctx
+}
+
+func (m multiHooks) OnRetry(ctx context.Context, event ResponseEvent) {
+	for _, h := range m {
+		h.OnRetry(ctx, event)
+	}
+}
+
+func (m multiHooks) AfterResponse(ctx context.Context, event ResponseEvent) {
+	for _, h := range m {
+		h.AfterResponse(ctx, event)
+	}
+}
+
+func (m multiHooks) OnDecodeError(ctx context.Context, event DecodeErrorEvent) {
+	for _, h := range m {
+		h.OnDecodeError(ctx, event)
+	}
+}
+
+// requestContextKey stores the RequestEvent of a request in its context, so decode errors can be reported with it
+type requestContextKey struct{}
+
+// redactParams copies params without the API key
+func redactParams(params map[string]string) map[string]string {
+	copied := make(map[string]string, len(params))
+	for key, value := range params {
+		if key != "apikey" {
+			copied[key] = value
+		}
+	}
+
+	return copied
+}
+
+// responseEvent describes an attempt that returned response and err
+func responseEvent(request RequestEvent, attempt int, start time.Time, response *Response, err error) ResponseEvent {
+	event := ResponseEvent{
+		Endpoint:    request.Endpoint,
+		Params:      request.Params,
+		Attempt:     attempt,
+		Duration:    time.Since(start),
+		Err:         err,
+		CreditsUsed: -1,
+		CreditsLeft: -1,
+	}
+
+	if err == nil && response != nil {
+		event.StatusCode = response.StatusCode
+		event.APIError = apiErrorOf(response.Body)
+		event.CreditsUsed = creditsHeader(response.Header, "api-credits-used")
+		event.CreditsLeft = creditsHeader(response.Header, "api-credits-left")
+	}
+
+	return event
+}
+
+func creditsHeader(header http.Header, name string) int {
+	credits, err := strconv.Atoi(header.Get(name))
+	if err != nil {
+		return -1
+	}
+
+	return credits
+}
+
+// decodeFailed reports a decoding error of the response body sent for ctx to the hooks. Error payloads of the API
+// were decoded fine and aren't reported.
+func (h *HTTPClient) decodeFailed(ctx context.Context, err error) {
+	var apiErr *APIError
+	if h.hooks == nil || ctx == nil || errors.As(err, &apiErr) {
+		return
+	}
+
+	request, _ := ctx.Value(requestContextKey{}).(RequestEvent)
+	h.hooks.OnDecodeError(ctx, DecodeErrorEvent{Endpoint: request.Endpoint, Params: request.Params, Err: err})
+}
@@ -5,60 +5,273 @@
 package twelvedata
 
 import (
-	"github.com/go-resty/resty/v2"
-	"go.uber.org/zap"
-	"go.uber.org/zap/zapcore"
+	"context"
+	"io"
 	"net/http"
+	"net/url"
+	"strings"
 	"time"
+
+	"github.com/go-resty/resty/v2"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
 )
 
+// Doer sends HTTP requests. *http.Client implements it.
+type Doer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// DoerFunc adapts a function to a Doer
+type DoerFunc func(req *http.Request) (*http.Response, error)
+
+func (f DoerFunc) Do(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// RestyDoer sends the requests through client, keeping its transport, middlewares and settings. Its base URL is
+// ignored, as the requests carry absolute URLs.
+func RestyDoer(client *resty.Client) Doer {
+	return DoerFunc(func(req *http.Request) (*http.Response, error) {
+		response, err := client.R().
+			SetContext(req.Context()).
+			SetHeaderMultiValues(req.Header).
+			SetDoNotParseResponse(true).
+			Execute(req.Method, req.URL.String())
+		if err != nil {
+			return nil, err
+		}
+
+		return response.RawResponse, nil
+	})
+}
+
+// Response is the outcome of a request to the API
+type Response struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte // Empty for the responses of GetStream, whose body is returned as a stream
+
+	ctx context.Context // Context of the request, used to report decode errors to the hooks
+}
+
 type HTTPClient struct {
-	logger        *zap.Logger
-	client        *resty.Client
+	logger        Logger
+	doer          Doer
+	baseURL       string
 	apiKey        string
+	debug         bool
 	retryCount    *int
 	retryWaitTime *time.Duration
+	hooks         Hooks
+}
+
+func (h *HTTPClient) Get(endpoint string, data map[string]string) (response *Response, err error) {
+	return h.getContext(context.Background(), endpoint, data)
+}
+
+// GetStream is like Get, but leaves the body unread for the caller to decode and close. A status other than 200 after
+// the retries is returned as an error, see responseError.
+func (h *HTTPClient) GetStream(endpoint string, data map[string]string) (body io.ReadCloser, err error) {
+	response, stream, err := h.send(context.Background(), endpoint, data, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if response == nil {
+		return nil, errors.New("no request was sent")
+	}
+
+	if stream == nil {
+		return nil, responseError(response)
+	}
+
+	return streamBody{ReadCloser: stream, ctx: response.ctx}, nil
+}
+
+// streamBody is a response body returned by GetStream, with the context of its request for reporting decode errors
+type streamBody struct {
+	io.ReadCloser
+	ctx context.Context
+}
+
+// streamDecodeFailed reports a decoding error of a body returned by GetStream, see decodeFailed
+func (h *HTTPClient) streamDecodeFailed(body io.Closer, err error) {
+	if stream, ok := body.(streamBody); ok {
+		h.decodeFailed(stream.ctx, err)
+	}
+}
+
+// decode runs decode on the body of response. Error payloads and statuses other than 200 are returned as errors
+// fetching the data of name, see responseError. A decoding failure is reported to the hooks and wrapped as an error
+// unmarshalling the response of name.
+func (h *HTTPClient) decode(response *Response, name string, decode func(body []byte) error) error {
+	if err := responseError(response); err != nil {
+		return errors.Wrapf(err, "Error fetching %s data", name)
+	}
+
+	if err := decode(response.Body); err != nil {
+		h.decodeFailed(response.ctx, err)
+		return errors.Wrapf(err, "Error unmarshalling %s response", name)
+	}
+
+	return nil
+}
+
+// unmarshal decodes the JSON body of response into v with api, see decode
+func (h *HTTPClient) unmarshal(api jsoniter.API, response *Response, v any, name string) error {
+	return h.decode(response, name, func(body []byte) error {
+		return api.Unmarshal(body, v)
+	})
+}
+
+// getContext is Get with a context cancelling the request and the waits between retries
+func (h *HTTPClient) getContext(ctx context.Context, endpoint string, data map[string]string) (response *Response, err error) {
+	response, _, err = h.send(ctx, endpoint, data, false)
+	return response, err
 }
 
-func (h *HTTPClient) Get(endpoint string, data map[string]string) (response *resty.Response, err error) {
+// send sends the request with retries. When stream is set, the body of a successful response is returned unread in
+// place of Response.Body.
+func (h *HTTPClient) send(ctx context.Context, endpoint string, data map[string]string, stream bool) (response *Response, body io.ReadCloser, err error) {
 	if data == nil {
 		data = make(map[string]string)
 	}
 
-	data["apikey"] = h.apiKey
+	start := time.Now()
+	var request RequestEvent
+	if h.hooks != nil {
+		request = RequestEvent{Endpoint: endpoint, Params: redactParams(data)}
+		ctx = context.WithValue(h.hooks.BeforeRequest(ctx, request), requestContextKey{}, request)
+	}
+
+	query := make(url.Values, len(data)+1)
+	for key, value := range data {
+		query.Set(key, value)
+	}
+	query.Set("apikey", h.apiKey)
+	target := h.baseURL + endpoint + "?" + query.Encode()
 
 	retries := 0
 	for retries < *h.retryCount {
-		response, err = h.client.R().
-			SetQueryParams(data).
-			Get(endpoint)
+		response, body, err = h.do(ctx, target, stream)
 
-		if err != nil || response.StatusCode() != http.StatusOK {
-			time.Sleep(*h.retryWaitTime)
-			retries++
+		if err != nil || response.StatusCode != http.StatusOK {
+			if ctx.Err() != nil {
+				err = ctx.Err()
+				break
+			}
 
 			// response is not valid when there is an error
-			var errOrStatusCode zapcore.Field
+			fields := []any{"attempt", retries + 1, "endpoint", endpoint, "params", redactParams(data)}
 			if err != nil {
-				errOrStatusCode = zap.Error(err)
+				fields = append(fields, "error", err)
 			} else {
-				errOrStatusCode = zap.Int("statusCode", response.StatusCode())
+				fields = append(fields, "statusCode", response.StatusCode)
 			}
 
-			h.logger.Info(
-				"Retry request",
-				zap.Int("retries", retries),
-				errOrStatusCode,
-				zap.String("endpoint", endpoint),
-				zap.Any("data", data),
-			)
+			retries++
+			if retries == *h.retryCount {
+				h.logger.Error("Request failed", fields...)
+				continue
+			}
+
+			h.logger.Warn("Retry request", fields...)
+			if h.hooks != nil {
+				h.hooks.OnRetry(ctx, responseEvent(request, retries, start, response, err))
+			}
 
-			continue
+			wait := time.NewTimer(*h.retryWaitTime)
+			select {
+			case <-wait.C:
+				continue
+			case <-ctx.Done():
+				wait.Stop()
+				err = ctx.Err()
+			}
+			break
 		}
 
 		// If we get here, the request was successful
 		break
 	}
 
-	return response, err
+	if h.debug && response != nil {
+		h.logger.Debug("Response", "endpoint", endpoint, "params", redactParams(data), "statusCode", response.StatusCode,
+			"duration", time.Since(start), "body", string(response.Body))
+	}
+
+	if h.hooks != nil {
+		h.hooks.AfterResponse(ctx, responseEvent(request, min(retries+1, *h.retryCount), start, response, err))
+	}
+
+	return response, body, err
+}
+
+// do sends a single attempt. The body is read, unless stream is set and the status is 200.
+func (h *HTTPClient) do(ctx context.Context, target string, stream bool) (*Response, io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resp, err := h.doer.Do(req)
+	if err != nil {
+		return nil, nil, h.redact(err)
+	}
+
+	response := &Response{StatusCode: resp.StatusCode, Header: resp.Header, ctx: ctx}
+	if stream && resp.StatusCode == http.StatusOK {
+		return response, resp.Body, nil
+	}
+
+	defer resp.Body.Close()
+	response.Body, err = io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, errors.Wrap(h.redact(err), "Error reading response body")
+	}
+
+	return response, nil, nil
+}
+
+// redact hides the API key in err, e.g. in the URL of a *url.Error, so request errors can be logged and printed
+func (h *HTTPClient) redact(err error) error {
+	if h.apiKey == "" || !strings.Contains(err.Error(), h.apiKey) {
+		return err
+	}
+
+	if urlErr, ok := err.(*url.Error); ok {
+		redacted := &url.Error{Op: urlErr.Op, URL: urlErr.URL, Err: urlErr.Err}
+		if u, parseErr := url.Parse(urlErr.URL); parseErr == nil {
+			query := u.Query()
+			query.Set("apikey", redactedAPIKey)
+			u.RawQuery = query.Encode()
+			redacted.URL = u.String()
+		}
+		if urlErr.Err != nil {
+			redacted.Err = h.redact(urlErr.Err)
+		}
+		if !strings.Contains(redacted.Error(), h.apiKey) {
+			return redacted
+		}
+	}
+
+	return redactedError{err: err, apiKey: h.apiKey}
+}
+
+// redactedAPIKey replaces the API key in errors
+const redactedAPIKey = "REDACTED"
+
+// redactedError hides the API key in the message of an error it wraps
+type redactedError struct {
+	err    error
+	apiKey string
+}
+
+func (e redactedError) Error() string {
+	return strings.ReplaceAll(e.err.Error(), e.apiKey, redactedAPIKey)
+}
+
+func (e redactedError) Unwrap() error {
+	return e.err
 }
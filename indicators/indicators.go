@@ -0,0 +1,185 @@
+// Package indicators computes technical indicators locally on candles returned by GetTimeSeries, so they don't cost
+// API credits. Every indicator is available as a streaming type (e.g. SMA) that is updated one closed bar at a time
+// and can preview the value for a still-forming bar with Peek, and as a batch function (e.g. ComputeSMA).
+//
+// Candles must be in chronological order. The API returns the newest candle first by default, so either request
+// with Order "asc" or pass the candles through Chronological first. Values the API did not provide (e.g. the volume
+// of forex pairs) count as zero.
+//
+// Default periods and formulas follow the TwelveData technical indicator endpoints.
+package indicators
+
+import (
+	"math"
+	"sort"
+
+	"github.com/jonnotjohn/twelvedata-go"
+	"github.com/pkg/errors"
+)
+
+// Indicator is implemented by every streaming indicator in this package
+type Indicator[T any] interface {
+	// Update adds a closed bar and returns the indicator value. ok is false while the indicator is warming up.
+	Update(candle twelvedata.TimeSeriesCandle) (value T, ok bool)
+	// Peek returns the value the indicator would have if candle closed now, without changing its state.
+	Peek(candle twelvedata.TimeSeriesCandle) (value T, ok bool)
+}
+
+// Chronological returns a copy of candles sorted from oldest to newest
+func Chronological(candles []twelvedata.TimeSeriesCandle) []twelvedata.TimeSeriesCandle {
+	sorted := make([]twelvedata.TimeSeriesCandle, len(candles))
+	copy(sorted, candles)
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].DateTime.Before(sorted[j].DateTime.Time)
+	})
+
+	return sorted
+}
+
+// compute runs candles through ind and returns one value per candle, using empty for warm-up bars
+func compute[T any](ind Indicator[T], candles []twelvedata.TimeSeriesCandle, empty T) []T {
+	values := make([]T, len(candles))
+	for i, candle := range candles {
+		value, ok := ind.Update(candle)
+		if !ok {
+			value = empty
+		}
+		values[i] = value
+	}
+
+	return values
+}
+
+func validatePeriod(name string, period int) error {
+	if period <= 0 {
+		return errors.Errorf("%s must be positive, got %d", name, period)
+	}
+
+	return nil
+}
+
+// window is a fixed size ring buffer of the most recent values
+type window struct {
+	values []float64
+	next   int
+	count  int
+
+	// The sum is kept with Neumaier's compensated summation, as values leaving the window are subtracted again and
+	// the rounding errors of a plain running sum would build up over long series
+	sum          float64
+	compensation float64
+}
+
+func newWindow(size int) *window {
+	return &window{values: make([]float64, size)}
+}
+
+func (w *window) clone() *window {
+	c := *w
+	c.values = append([]float64(nil), w.values...)
+	return &c
+}
+
+func (w *window) push(v float64) {
+	if w.full() {
+		w.add(-w.values[w.next])
+	} else {
+		w.count++
+	}
+
+	w.values[w.next] = v
+	w.add(v)
+	w.next = (w.next + 1) % len(w.values)
+}
+
+// add adds v to the sum, keeping the low-order bits lost to rounding in compensation
+func (w *window) add(v float64) {
+	t := w.sum + v
+	if math.Abs(w.sum) >= math.Abs(v) {
+		w.compensation += (w.sum - t) + v
+	} else {
+		w.compensation += (v - t) + w.sum
+	}
+	w.sum = t
+}
+
+func (w *window) full() bool {
+	return w.count == len(w.values)
+}
+
+// at returns the i-th value from the oldest (0) to the newest (count-1)
+func (w *window) at(i int) float64 {
+	start := w.next - w.count
+	if start < 0 {
+		start += len(w.values)
+	}
+	return w.values[(start+i)%len(w.values)]
+}
+
+func (w *window) mean() float64 {
+	return (w.sum + w.compensation) / float64(w.count)
+}
+
+// stdDev returns the population standard deviation of the window
+func (w *window) stdDev() float64 {
+	mean := w.mean()
+
+	var variance float64
+	for i := 0; i < w.count; i++ {
+		d := w.at(i) - mean
+		variance += d * d
+	}
+
+	return math.Sqrt(variance / float64(w.count))
+}
+
+func (w *window) max() float64 {
+	m := math.Inf(-1)
+	for i := 0; i < w.count; i++ {
+		m = math.Max(m, w.at(i))
+	}
+	return m
+}
+
+func (w *window) min() float64 {
+	m := math.Inf(1)
+	for i := 0; i < w.count; i++ {
+		m = math.Min(m, w.at(i))
+	}
+	return m
+}
+
+// smoother is an exponential moving average seeded with the simple average of the first period values.
+// alpha is 2/(period+1) for a regular EMA and 1/period for Wilder's smoothing.
+type smoother struct {
+	period int
+	alpha  float64
+	count  int
+	seed   float64
+	value  float64
+}
+
+func newEMASmoother(period int) smoother {
+	return smoother{period: period, alpha: 2 / float64(period+1)}
+}
+
+func newWilderSmoother(period int) smoother {
+	return smoother{period: period, alpha: 1 / float64(period)}
+}
+
+func (s *smoother) update(v float64) (float64, bool) {
+	s.count++
+
+	switch {
+	case s.count < s.period:
+		s.seed += v
+		return 0, false
+	case s.count == s.period:
+		s.value = (s.seed + v) / float64(s.period)
+	default:
+		s.value = s.alpha*v + (1-s.alpha)*s.value
+	}
+
+	return s.value, true
+}
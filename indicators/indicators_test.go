@@ -0,0 +1,194 @@
+package indicators_test
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/jonnotjohn/twelvedata-go"
+	"github.com/jonnotjohn/twelvedata-go/indicators"
+	jsoniter "github.com/json-iterator/go"
+)
+
+// tolerance absorbs the rounding of the reference values to 5 decimals
+const tolerance = 1e-4
+
+// loadCandles reads synthetic candles from testdata, see testdata/reference.py. They have no exchange timezone, so
+// their datetimes are in UTC.
+func loadCandles(t *testing.T, name string) []twelvedata.TimeSeriesCandle {
+	t.Helper()
+
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var series twelvedata.TimeSeriesResponse
+	if err := jsoniter.Unmarshal(data, &series); err != nil {
+		t.Fatal(err)
+	}
+
+	return indicators.Chronological(series.Candles)
+}
+
+// loadReference reads reference indicator values from testdata, keyed by the unix time of each bar. They were computed
+// by testdata/reference.py independently of this package.
+func loadReference(t *testing.T, name string) map[int64]map[string]float64 {
+	t.Helper()
+
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var resp struct {
+		Values []map[string]string `json:"values"`
+	}
+	if err := jsoniter.Unmarshal(data, &resp); err != nil {
+		t.Fatal(err)
+	}
+
+	values := make(map[int64]map[string]float64, len(resp.Values))
+	for _, row := range resp.Values {
+		dateTime, err := time.Parse("2006-01-02 15:04:05", row["datetime"])
+		if err != nil {
+			dateTime, err = time.Parse("2006-01-02", row["datetime"])
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		fields := make(map[string]float64, len(row)-1)
+		for field, value := range row {
+			if field == "datetime" {
+				continue
+			}
+			if fields[field], err = strconv.ParseFloat(value, 64); err != nil {
+				t.Fatal(err)
+			}
+		}
+		values[dateTime.Unix()] = fields
+	}
+
+	return values
+}
+
+// checkReference compares the values computed for candles with the reference values of name. fields returns the
+// fields of a value under the names of the reference. Bars without a reference value aren't compared.
+func checkReference[T any](t *testing.T, name string, candles []twelvedata.TimeSeriesCandle, values []T, fields func(T) map[string]float64) {
+	t.Helper()
+
+	reference := loadReference(t, name)
+
+	compared := 0
+	for i, candle := range candles {
+		want, ok := reference[candle.DateTime.Unix()]
+		if !ok {
+			continue
+		}
+		compared++
+
+		got := fields(values[i])
+		for field, wantValue := range want {
+			if math.Abs(got[field]-wantValue) > tolerance {
+				t.Errorf("%s at %s = %v, want %v", field, candle.DateTime, got[field], wantValue)
+			}
+		}
+	}
+
+	if compared != len(reference) {
+		t.Errorf("compared %d bars, want the %d bars of %s", compared, len(reference), name)
+	}
+}
+
+// checkStreaming feeds candles to ind one at a time and checks that it matches the batch values. Before each update,
+// the still-forming bar is previewed with Peek, which must neither change the state nor differ from Update.
+func checkStreaming[T any](t *testing.T, ind indicators.Indicator[T], candles []twelvedata.TimeSeriesCandle, values []T, fields func(T) map[string]float64) {
+	t.Helper()
+
+	for i, candle := range candles {
+		forming := candle
+		forming.Close = twelvedata.NewTDFloat(candle.Close.Float64 * 1.01)
+		forming.High = twelvedata.NewTDFloat(math.Max(candle.High.Float64, forming.Close.Float64))
+		ind.Peek(forming)
+
+		peeked, peekedOK := ind.Peek(candle)
+		value, ok := ind.Update(candle)
+
+		if peekedOK != ok || !sameFields(fields(peeked), fields(value)) {
+			t.Fatalf("Peek() at %s = %v, %v, want %v, %v as Update()", candle.DateTime, peeked, peekedOK, value, ok)
+		}
+
+		if !ok {
+			for field, batch := range fields(values[i]) {
+				if !math.IsNaN(batch) {
+					t.Fatalf("batch %s at %s = %v during warm-up, want NaN", field, candle.DateTime, batch)
+				}
+			}
+			continue
+		}
+
+		if !sameFields(fields(value), fields(values[i])) {
+			t.Fatalf("Update() at %s = %v, want %v as the batch value", candle.DateTime, value, values[i])
+		}
+	}
+}
+
+func sameFields(a, b map[string]float64) bool {
+	for field, value := range a {
+		if other := b[field]; value != other && !(math.IsNaN(value) && math.IsNaN(other)) {
+			return false
+		}
+	}
+
+	return len(a) == len(b)
+}
+
+// single names the value of an indicator with a single field
+func single(field string) func(float64) map[string]float64 {
+	return func(value float64) map[string]float64 {
+		return map[string]float64{field: value}
+	}
+}
+
+func TestChronological(t *testing.T) {
+	candles := loadCandles(t, "candles_1day.json")
+
+	for i := 1; i < len(candles); i++ {
+		if !candles[i-1].DateTime.Before(candles[i].DateTime.Time) {
+			t.Fatalf("candle %d at %s isn't after %s", i, candles[i].DateTime, candles[i-1].DateTime)
+		}
+	}
+
+	reversed := make([]twelvedata.TimeSeriesCandle, len(candles))
+	for i, candle := range candles {
+		reversed[len(candles)-1-i] = candle
+	}
+
+	sorted := indicators.Chronological(reversed)
+	if !sorted[0].DateTime.Equal(candles[0].DateTime.Time) || !reversed[0].DateTime.Equal(candles[len(candles)-1].DateTime.Time) {
+		t.Error("Chronological() didn't sort a copy of the candles")
+	}
+}
+
+func TestInvalidPeriods(t *testing.T) {
+	constructors := map[string]func() error{
+		"SMA":            func() error { _, err := indicators.NewSMA(0); return err },
+		"EMA":            func() error { _, err := indicators.NewEMA(-1); return err },
+		"WMA":            func() error { _, err := indicators.NewWMA(0); return err },
+		"RSI":            func() error { _, err := indicators.NewRSI(0); return err },
+		"MACD":           func() error { _, err := indicators.NewMACD(12, 0, 9); return err },
+		"Stochastic":     func() error { _, err := indicators.NewStochastic(14, 1, 0); return err },
+		"BollingerBands": func() error { _, err := indicators.NewBollingerBands(0, 2); return err },
+		"ATR":            func() error { _, err := indicators.NewATR(0); return err },
+	}
+
+	for name, construct := range constructors {
+		if construct() == nil {
+			t.Errorf("New%s() accepted a period that isn't positive", name)
+		}
+	}
+}
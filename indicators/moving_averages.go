@@ -0,0 +1,118 @@
+package indicators
+
+import (
+	"math"
+
+	"github.com/jonnotjohn/twelvedata-go"
+)
+
+// SMA is the simple moving average of the close price
+type SMA struct {
+	window *window
+}
+
+func NewSMA(period int) (*SMA, error) {
+	if err := validatePeriod("period", period); err != nil {
+		return nil, err
+	}
+
+	return &SMA{window: newWindow(period)}, nil
+}
+
+func (s *SMA) Update(candle twelvedata.TimeSeriesCandle) (float64, bool) {
+	s.window.push(candle.Close.Float64)
+	if !s.window.full() {
+		return 0, false
+	}
+
+	return s.window.mean(), true
+}
+
+func (s *SMA) Peek(candle twelvedata.TimeSeriesCandle) (float64, bool) {
+	return (&SMA{window: s.window.clone()}).Update(candle)
+}
+
+// ComputeSMA returns the simple moving average for each candle, NaN until period candles have been seen
+func ComputeSMA(candles []twelvedata.TimeSeriesCandle, period int) ([]float64, error) {
+	sma, err := NewSMA(period)
+	if err != nil {
+		return nil, err
+	}
+
+	return compute[float64](sma, candles, math.NaN()), nil
+}
+
+// EMA is the exponential moving average of the close price, seeded with the SMA of the first period candles
+type EMA struct {
+	smoother smoother
+}
+
+func NewEMA(period int) (*EMA, error) {
+	if err := validatePeriod("period", period); err != nil {
+		return nil, err
+	}
+
+	return &EMA{smoother: newEMASmoother(period)}, nil
+}
+
+func (e *EMA) Update(candle twelvedata.TimeSeriesCandle) (float64, bool) {
+	return e.smoother.update(candle.Close.Float64)
+}
+
+func (e *EMA) Peek(candle twelvedata.TimeSeriesCandle) (float64, bool) {
+	c := *e
+	return c.Update(candle)
+}
+
+// ComputeEMA returns the exponential moving average for each candle, NaN until period candles have been seen
+func ComputeEMA(candles []twelvedata.TimeSeriesCandle, period int) ([]float64, error) {
+	ema, err := NewEMA(period)
+	if err != nil {
+		return nil, err
+	}
+
+	return compute[float64](ema, candles, math.NaN()), nil
+}
+
+// WMA is the linearly weighted moving average of the close price, with the newest candle weighted by period
+type WMA struct {
+	window *window
+}
+
+func NewWMA(period int) (*WMA, error) {
+	if err := validatePeriod("period", period); err != nil {
+		return nil, err
+	}
+
+	return &WMA{window: newWindow(period)}, nil
+}
+
+func (w *WMA) Update(candle twelvedata.TimeSeriesCandle) (float64, bool) {
+	w.window.push(candle.Close.Float64)
+	if !w.window.full() {
+		return 0, false
+	}
+
+	var weighted, weights float64
+	for i := 0; i < w.window.count; i++ {
+		weight := float64(i + 1)
+		weighted += weight * w.window.at(i)
+		weights += weight
+	}
+
+	return weighted / weights, true
+}
+
+func (w *WMA) Peek(candle twelvedata.TimeSeriesCandle) (float64, bool) {
+	return (&WMA{window: w.window.clone()}).Update(candle)
+}
+
+// ComputeWMA returns the weighted moving average for each candle, NaN until period candles have been seen
+func ComputeWMA(candles []twelvedata.TimeSeriesCandle, period int) ([]float64, error) {
+	wma, err := NewWMA(period)
+	if err != nil {
+		return nil, err
+	}
+
+	return compute[float64](wma, candles, math.NaN()), nil
+}
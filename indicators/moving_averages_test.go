@@ -0,0 +1,79 @@
+package indicators_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jonnotjohn/twelvedata-go"
+	"github.com/jonnotjohn/twelvedata-go/indicators"
+)
+
+func TestSMA(t *testing.T) {
+	candles := loadCandles(t, "candles_1day.json")
+
+	values, err := indicators.ComputeSMA(candles, 9)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkReference(t, "sma_9.json", candles, values, single("sma"))
+
+	sma, err := indicators.NewSMA(9)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkStreaming[float64](t, sma, candles, values, single("sma"))
+}
+
+func TestSMAPrecision(t *testing.T) {
+	sma, err := indicators.NewSMA(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A plain running sum loses the small values added next to the large one, and keeps the loss after it leaves
+	closes := []float64{1e16, 1, 1, 1, 1}
+	start := time.Date(2024, 6, 14, 0, 0, 0, 0, time.UTC)
+	var value float64
+	for i, close := range closes {
+		value, _ = sma.Update(twelvedata.TimeSeriesCandle{
+			DateTime: twelvedata.TDZonedTime{Time: start.AddDate(0, 0, i)},
+			Close:    twelvedata.NewTDFloat(close),
+		})
+	}
+
+	if value != 1 {
+		t.Errorf("SMA after the large value left = %v, want 1", value)
+	}
+}
+
+func TestEMA(t *testing.T) {
+	candles := loadCandles(t, "candles_1day.json")
+
+	values, err := indicators.ComputeEMA(candles, 9)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkReference(t, "ema_9.json", candles, values, single("ema"))
+
+	ema, err := indicators.NewEMA(9)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkStreaming[float64](t, ema, candles, values, single("ema"))
+}
+
+func TestWMA(t *testing.T) {
+	candles := loadCandles(t, "candles_1day.json")
+
+	values, err := indicators.ComputeWMA(candles, 9)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkReference(t, "wma_9.json", candles, values, single("wma"))
+
+	wma, err := indicators.NewWMA(9)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkStreaming[float64](t, wma, candles, values, single("wma"))
+}
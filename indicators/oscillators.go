@@ -0,0 +1,212 @@
+package indicators
+
+import (
+	"math"
+
+	"github.com/jonnotjohn/twelvedata-go"
+)
+
+// RSI is the relative strength index of the close price using Wilder's smoothing (TwelveData default period is 14)
+type RSI struct {
+	gains     smoother
+	losses    smoother
+	prevClose float64
+	started   bool
+}
+
+func NewRSI(period int) (*RSI, error) {
+	if err := validatePeriod("period", period); err != nil {
+		return nil, err
+	}
+
+	return &RSI{gains: newWilderSmoother(period), losses: newWilderSmoother(period)}, nil
+}
+
+func (r *RSI) Update(candle twelvedata.TimeSeriesCandle) (float64, bool) {
+	if !r.started {
+		r.started = true
+		r.prevClose = candle.Close.Float64
+		return 0, false
+	}
+
+	change := candle.Close.Float64 - r.prevClose
+	r.prevClose = candle.Close.Float64
+
+	avgGain, ok := r.gains.update(math.Max(change, 0))
+	avgLoss, _ := r.losses.update(math.Max(-change, 0))
+	if !ok {
+		return 0, false
+	}
+
+	if avgLoss == 0 {
+		if avgGain == 0 {
+			return 50, true
+		}
+		return 100, true
+	}
+
+	return 100 - 100/(1+avgGain/avgLoss), true
+}
+
+func (r *RSI) Peek(candle twelvedata.TimeSeriesCandle) (float64, bool) {
+	c := *r
+	return c.Update(candle)
+}
+
+// ComputeRSI returns the relative strength index for each candle, NaN until period+1 candles have been seen
+func ComputeRSI(candles []twelvedata.TimeSeriesCandle, period int) ([]float64, error) {
+	rsi, err := NewRSI(period)
+	if err != nil {
+		return nil, err
+	}
+
+	return compute[float64](rsi, candles, math.NaN()), nil
+}
+
+type MACDValue struct {
+	MACD      float64 // Fast EMA minus slow EMA
+	Signal    float64 // EMA of MACD
+	Histogram float64 // MACD minus Signal
+}
+
+// MACD is the moving average convergence divergence of the close price (TwelveData defaults are 12, 26 and 9)
+type MACD struct {
+	fast   smoother
+	slow   smoother
+	signal smoother
+}
+
+func NewMACD(fastPeriod, slowPeriod, signalPeriod int) (*MACD, error) {
+	if err := validatePeriod("fast period", fastPeriod); err != nil {
+		return nil, err
+	}
+
+	if err := validatePeriod("slow period", slowPeriod); err != nil {
+		return nil, err
+	}
+
+	if err := validatePeriod("signal period", signalPeriod); err != nil {
+		return nil, err
+	}
+
+	return &MACD{
+		fast:   newEMASmoother(fastPeriod),
+		slow:   newEMASmoother(slowPeriod),
+		signal: newEMASmoother(signalPeriod),
+	}, nil
+}
+
+func (m *MACD) Update(candle twelvedata.TimeSeriesCandle) (MACDValue, bool) {
+	fast, fastOK := m.fast.update(candle.Close.Float64)
+	slow, slowOK := m.slow.update(candle.Close.Float64)
+	if !fastOK || !slowOK {
+		return MACDValue{}, false
+	}
+
+	macd := fast - slow
+	signal, ok := m.signal.update(macd)
+	if !ok {
+		return MACDValue{}, false
+	}
+
+	return MACDValue{MACD: macd, Signal: signal, Histogram: macd - signal}, true
+}
+
+func (m *MACD) Peek(candle twelvedata.TimeSeriesCandle) (MACDValue, bool) {
+	c := *m
+	return c.Update(candle)
+}
+
+// ComputeMACD returns the MACD for each candle, with NaN fields until the signal line is available
+func ComputeMACD(candles []twelvedata.TimeSeriesCandle, fastPeriod, slowPeriod, signalPeriod int) ([]MACDValue, error) {
+	macd, err := NewMACD(fastPeriod, slowPeriod, signalPeriod)
+	if err != nil {
+		return nil, err
+	}
+
+	nan := math.NaN()
+	return compute[MACDValue](macd, candles, MACDValue{MACD: nan, Signal: nan, Histogram: nan}), nil
+}
+
+type StochasticValue struct {
+	K float64 // Slow %K
+	D float64 // Slow %D, the SMA of slow %K
+}
+
+// Stochastic is the stochastic oscillator (TwelveData defaults are 14, 1 and 3)
+type Stochastic struct {
+	highs *window
+	lows  *window
+	slowK *window
+	slowD *window
+}
+
+func NewStochastic(fastKPeriod, slowKPeriod, slowDPeriod int) (*Stochastic, error) {
+	if err := validatePeriod("fast %K period", fastKPeriod); err != nil {
+		return nil, err
+	}
+
+	if err := validatePeriod("slow %K period", slowKPeriod); err != nil {
+		return nil, err
+	}
+
+	if err := validatePeriod("slow %D period", slowDPeriod); err != nil {
+		return nil, err
+	}
+
+	return &Stochastic{
+		highs: newWindow(fastKPeriod),
+		lows:  newWindow(fastKPeriod),
+		slowK: newWindow(slowKPeriod),
+		slowD: newWindow(slowDPeriod),
+	}, nil
+}
+
+func (s *Stochastic) Update(candle twelvedata.TimeSeriesCandle) (StochasticValue, bool) {
+	s.highs.push(candle.High.Float64)
+	s.lows.push(candle.Low.Float64)
+	if !s.highs.full() {
+		return StochasticValue{}, false
+	}
+
+	highest, lowest := s.highs.max(), s.lows.min()
+
+	fastK := 50.0
+	if highest != lowest {
+		fastK = 100 * (candle.Close.Float64 - lowest) / (highest - lowest)
+	}
+
+	s.slowK.push(fastK)
+	if !s.slowK.full() {
+		return StochasticValue{}, false
+	}
+
+	k := s.slowK.mean()
+	s.slowD.push(k)
+	if !s.slowD.full() {
+		return StochasticValue{}, false
+	}
+
+	return StochasticValue{K: k, D: s.slowD.mean()}, true
+}
+
+func (s *Stochastic) Peek(candle twelvedata.TimeSeriesCandle) (StochasticValue, bool) {
+	c := &Stochastic{
+		highs: s.highs.clone(),
+		lows:  s.lows.clone(),
+		slowK: s.slowK.clone(),
+		slowD: s.slowD.clone(),
+	}
+	return c.Update(candle)
+}
+
+// ComputeStochastic returns the stochastic oscillator for each candle, with NaN fields until %D is available
+func ComputeStochastic(candles []twelvedata.TimeSeriesCandle, fastKPeriod, slowKPeriod, slowDPeriod int) ([]StochasticValue, error) {
+	stoch, err := NewStochastic(fastKPeriod, slowKPeriod, slowDPeriod)
+	if err != nil {
+		return nil, err
+	}
+
+	nan := math.NaN()
+	return compute[StochasticValue](stoch, candles, StochasticValue{K: nan, D: nan}), nil
+}
@@ -0,0 +1,63 @@
+package indicators_test
+
+import (
+	"testing"
+
+	"github.com/jonnotjohn/twelvedata-go/indicators"
+)
+
+func macdFields(value indicators.MACDValue) map[string]float64 {
+	return map[string]float64{"macd": value.MACD, "macd_signal": value.Signal, "macd_hist": value.Histogram}
+}
+
+func stochasticFields(value indicators.StochasticValue) map[string]float64 {
+	return map[string]float64{"slow_k": value.K, "slow_d": value.D}
+}
+
+func TestRSI(t *testing.T) {
+	candles := loadCandles(t, "candles_1day.json")
+
+	values, err := indicators.ComputeRSI(candles, 14)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkReference(t, "rsi_14.json", candles, values, single("rsi"))
+
+	rsi, err := indicators.NewRSI(14)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkStreaming[float64](t, rsi, candles, values, single("rsi"))
+}
+
+func TestMACD(t *testing.T) {
+	candles := loadCandles(t, "candles_1day.json")
+
+	values, err := indicators.ComputeMACD(candles, 12, 26, 9)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkReference(t, "macd_12_26_9.json", candles, values, macdFields)
+
+	macd, err := indicators.NewMACD(12, 26, 9)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkStreaming[indicators.MACDValue](t, macd, candles, values, macdFields)
+}
+
+func TestStochastic(t *testing.T) {
+	candles := loadCandles(t, "candles_1day.json")
+
+	values, err := indicators.ComputeStochastic(candles, 14, 1, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkReference(t, "stoch_14_1_3.json", candles, values, stochasticFields)
+
+	stoch, err := indicators.NewStochastic(14, 1, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkStreaming[indicators.StochasticValue](t, stoch, candles, values, stochasticFields)
+}
@@ -0,0 +1,189 @@
+"""Generates the synthetic candles and the reference indicator values used by the tests of the indicators package.
+
+The candles are a seeded random walk, not market data. The reference values are computed here with plain textbook
+formulas, independently of the Go implementation, using the default parameters of the TwelveData indicator
+endpoints. Run it with python3 from this directory to regenerate the JSON files.
+"""
+import datetime
+import json
+import math
+import random
+
+random.seed(20240614)
+
+
+def fmt(v):
+    return '%.5f' % v
+
+
+def walk(n, price, vol_lo, vol_hi, scale):
+    bars = []
+    for _ in range(n):
+        o = price
+        c = round(o * (1 + random.gauss(0.0008, scale)), 5)
+        h = round(max(o, c) * (1 + abs(random.gauss(0, scale / 2))), 5)
+        l = round(min(o, c) * (1 - abs(random.gauss(0, scale / 2))), 5)
+        bars.append((round(o, 5), h, l, c, random.randint(vol_lo, vol_hi)))
+        price = c
+    return bars
+
+
+def write(name, values):
+    with open(name, 'w') as f:
+        json.dump({"values": values}, f, indent=2)
+        f.write('\n')
+
+
+# 80 daily bars on weekdays, 39 bars of 30 minutes over three sessions
+daily_times = []
+day = datetime.date(2024, 2, 20)
+while len(daily_times) < 80:
+    if day.weekday() < 5:
+        daily_times.append(day.isoformat())
+    day += datetime.timedelta(days=1)
+daily = walk(len(daily_times), 182.52, 38000000, 98000000, 0.013)
+
+intraday_times = []
+for session in (12, 13, 14):
+    t = datetime.datetime(2024, 6, session, 9, 30)
+    for _ in range(13):
+        intraday_times.append(t.strftime('%Y-%m-%d %H:%M:%S'))
+        t += datetime.timedelta(minutes=30)
+intraday = walk(len(intraday_times), 207.37, 2100000, 9800000, 0.003)
+
+
+def write_candles(name, times, bars):
+    write(name, [{"datetime": t, "open": fmt(o), "high": fmt(h), "low": fmt(l), "close": fmt(c), "volume": str(v)}
+                 for t, (o, h, l, c, v) in zip(times, bars)])
+
+
+write_candles('candles_1day.json', daily_times, daily)
+write_candles('candles_30min.json', intraday_times, intraday)
+
+
+# One value per bar, None during the warm-up
+def sma(xs, n):
+    return [None if i < n - 1 else sum(xs[i - n + 1:i + 1]) / n for i in range(len(xs))]
+
+
+def ema(xs, n, alpha=None):
+    alpha = alpha if alpha is not None else 2 / (n + 1)
+    out, prev = [], None
+    for i, x in enumerate(xs):
+        if i < n - 1:
+            out.append(None)
+            continue
+        prev = sum(xs[:n]) / n if i == n - 1 else alpha * x + (1 - alpha) * prev
+        out.append(prev)
+    return out
+
+
+def wma(xs, n):
+    weights = sum(range(1, n + 1))
+    return [None if i < n - 1 else sum(xs[i - n + 1 + k] * (k + 1) for k in range(n)) / weights
+            for i in range(len(xs))]
+
+
+def rsi(xs, n):
+    changes = [xs[i] - xs[i - 1] for i in range(1, len(xs))]
+    gains = ema([max(c, 0) for c in changes], n, 1 / n)
+    losses = ema([max(-c, 0) for c in changes], n, 1 / n)
+    out = [None]
+    for g, l in zip(gains, losses):
+        if g is None:
+            out.append(None)
+        elif l == 0:
+            out.append(100 if g > 0 else 50)
+        else:
+            out.append(100 - 100 / (1 + g / l))
+    return out
+
+
+def macd(xs, fast, slow, signal):
+    f, s = ema(xs, fast), ema(xs, slow)
+    line = [f[i] - s[i] for i in range(slow - 1, len(xs))]
+    out = [None] * (slow - 1)
+    for m, g in zip(line, ema(line, signal)):
+        out.append(None if g is None else (m, g, m - g))
+    return out
+
+
+def bbands(xs, n, k):
+    out = []
+    for i in range(len(xs)):
+        if i < n - 1:
+            out.append(None)
+            continue
+        win = xs[i - n + 1:i + 1]
+        mean = sum(win) / n
+        sd = math.sqrt(sum((x - mean) ** 2 for x in win) / n)
+        out.append((mean + k * sd, mean, mean - k * sd))
+    return out
+
+
+def atr(bars, n):
+    ranges = []
+    for i, (o, h, l, c, v) in enumerate(bars):
+        r = h - l
+        if i > 0:
+            r = max(r, abs(h - bars[i - 1][3]), abs(l - bars[i - 1][3]))
+        ranges.append(r)
+    return ema(ranges, n, 1 / n)
+
+
+def stoch(bars, fast_k, slow_k, slow_d):
+    fk = []
+    for i in range(len(bars)):
+        if i < fast_k - 1:
+            fk.append(None)
+            continue
+        hi = max(b[1] for b in bars[i - fast_k + 1:i + 1])
+        lo = min(b[2] for b in bars[i - fast_k + 1:i + 1])
+        fk.append(50.0 if hi == lo else 100 * (bars[i][3] - lo) / (hi - lo))
+    start = fast_k - 1
+    sk = [None] * start + sma(fk[start:], slow_k)
+    start += slow_k - 1
+    sd = [None] * start + sma(sk[start:], slow_d)
+    return [None if sd[i] is None else (sk[i], sd[i]) for i in range(len(bars))]
+
+
+def vwap(times, bars):
+    out, day, pv, vol = [], None, 0, 0
+    for t, (o, h, l, c, v) in zip(times, bars):
+        if t[:10] != day:
+            day, pv, vol = t[:10], 0, 0
+        pv += (h + l + c) / 3 * v
+        vol += v
+        out.append(pv / vol)
+    return out
+
+
+def obv(bars):
+    out, value = [], 0
+    for i, b in enumerate(bars):
+        if i > 0 and b[3] != bars[i - 1][3]:
+            value += b[4] if b[3] > bars[i - 1][3] else -b[4]
+        out.append(value)
+    return out
+
+
+def write_reference(name, times, values, fields):
+    rows = []
+    for t, v in zip(times, values):
+        if v is not None:
+            v = v if isinstance(v, tuple) else (v,)
+            rows.append({"datetime": t, **{f: fmt(x) for f, x in zip(fields, v)}})
+    write(name, rows)
+
+
+closes = [b[3] for b in daily]
+write_reference('sma_9.json', daily_times, sma(closes, 9), ["sma"])
+write_reference('ema_9.json', daily_times, ema(closes, 9), ["ema"])
+write_reference('wma_9.json', daily_times, wma(closes, 9), ["wma"])
+write_reference('rsi_14.json', daily_times, rsi(closes, 14), ["rsi"])
+write_reference('macd_12_26_9.json', daily_times, macd(closes, 12, 26, 9), ["macd", "macd_signal", "macd_hist"])
+write_reference('bbands_20_2.json', daily_times, bbands(closes, 20, 2), ["upper_band", "middle_band", "lower_band"])
+write_reference('atr_14.json', daily_times, atr(daily, 14), ["atr"])
+write_reference('stoch_14_1_3.json', daily_times, stoch(daily, 14, 1, 3), ["slow_k", "slow_d"])
+write_reference('vwap.json', intraday_times, vwap(intraday_times, intraday), ["vwap"])
+write_reference('obv.json', intraday_times, obv(intraday), ["obv"])
@@ -0,0 +1,97 @@
+package indicators
+
+import (
+	"math"
+
+	"github.com/jonnotjohn/twelvedata-go"
+)
+
+type BollingerBandsValue struct {
+	Upper  float64
+	Middle float64 // SMA of the close price
+	Lower  float64
+}
+
+// BollingerBands are the SMA of the close price plus and minus a multiple of the population standard deviation
+// (TwelveData defaults are a period of 20 and 2 standard deviations)
+type BollingerBands struct {
+	window *window
+	stdDev float64
+}
+
+func NewBollingerBands(period int, stdDev float64) (*BollingerBands, error) {
+	if err := validatePeriod("period", period); err != nil {
+		return nil, err
+	}
+
+	return &BollingerBands{window: newWindow(period), stdDev: stdDev}, nil
+}
+
+func (b *BollingerBands) Update(candle twelvedata.TimeSeriesCandle) (BollingerBandsValue, bool) {
+	b.window.push(candle.Close.Float64)
+	if !b.window.full() {
+		return BollingerBandsValue{}, false
+	}
+
+	middle := b.window.mean()
+	width := b.stdDev * b.window.stdDev()
+
+	return BollingerBandsValue{Upper: middle + width, Middle: middle, Lower: middle - width}, true
+}
+
+func (b *BollingerBands) Peek(candle twelvedata.TimeSeriesCandle) (BollingerBandsValue, bool) {
+	return (&BollingerBands{window: b.window.clone(), stdDev: b.stdDev}).Update(candle)
+}
+
+// ComputeBollingerBands returns the bands for each candle, with NaN fields until period candles have been seen
+func ComputeBollingerBands(candles []twelvedata.TimeSeriesCandle, period int, stdDev float64) ([]BollingerBandsValue, error) {
+	bands, err := NewBollingerBands(period, stdDev)
+	if err != nil {
+		return nil, err
+	}
+
+	nan := math.NaN()
+	return compute[BollingerBandsValue](bands, candles, BollingerBandsValue{Upper: nan, Middle: nan, Lower: nan}), nil
+}
+
+// ATR is the average true range using Wilder's smoothing (TwelveData default period is 14)
+type ATR struct {
+	smoother  smoother
+	prevClose float64
+	started   bool
+}
+
+func NewATR(period int) (*ATR, error) {
+	if err := validatePeriod("period", period); err != nil {
+		return nil, err
+	}
+
+	return &ATR{smoother: newWilderSmoother(period)}, nil
+}
+
+func (a *ATR) Update(candle twelvedata.TimeSeriesCandle) (float64, bool) {
+	trueRange := candle.High.Float64 - candle.Low.Float64
+	if a.started {
+		trueRange = math.Max(trueRange, math.Max(math.Abs(candle.High.Float64-a.prevClose), math.Abs(candle.Low.Float64-a.prevClose)))
+	}
+
+	a.started = true
+	a.prevClose = candle.Close.Float64
+
+	return a.smoother.update(trueRange)
+}
+
+func (a *ATR) Peek(candle twelvedata.TimeSeriesCandle) (float64, bool) {
+	c := *a
+	return c.Update(candle)
+}
+
+// ComputeATR returns the average true range for each candle, NaN until period candles have been seen
+func ComputeATR(candles []twelvedata.TimeSeriesCandle, period int) ([]float64, error) {
+	atr, err := NewATR(period)
+	if err != nil {
+		return nil, err
+	}
+
+	return compute[float64](atr, candles, math.NaN()), nil
+}
@@ -0,0 +1,43 @@
+package indicators_test
+
+import (
+	"testing"
+
+	"github.com/jonnotjohn/twelvedata-go/indicators"
+)
+
+func bandsFields(value indicators.BollingerBandsValue) map[string]float64 {
+	return map[string]float64{"upper_band": value.Upper, "middle_band": value.Middle, "lower_band": value.Lower}
+}
+
+func TestBollingerBands(t *testing.T) {
+	candles := loadCandles(t, "candles_1day.json")
+
+	values, err := indicators.ComputeBollingerBands(candles, 20, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkReference(t, "bbands_20_2.json", candles, values, bandsFields)
+
+	bands, err := indicators.NewBollingerBands(20, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkStreaming[indicators.BollingerBandsValue](t, bands, candles, values, bandsFields)
+}
+
+func TestATR(t *testing.T) {
+	candles := loadCandles(t, "candles_1day.json")
+
+	values, err := indicators.ComputeATR(candles, 14)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkReference(t, "atr_14.json", candles, values, single("atr"))
+
+	atr, err := indicators.NewATR(14)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkStreaming[float64](t, atr, candles, values, single("atr"))
+}
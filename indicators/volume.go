@@ -0,0 +1,80 @@
+package indicators
+
+import (
+	"github.com/jonnotjohn/twelvedata-go"
+)
+
+// VWAP is the volume weighted average of the typical price ((high + low + close) / 3). It resets at the start of each
+// calendar day in the candles' timezone, so intraday candles should carry the exchange timezone.
+type VWAP struct {
+	year, yearDay int
+	priceVolume   float64
+	volume        float64
+}
+
+func NewVWAP() *VWAP {
+	return &VWAP{}
+}
+
+func (v *VWAP) Update(candle twelvedata.TimeSeriesCandle) (float64, bool) {
+	if year, yearDay := candle.DateTime.Year(), candle.DateTime.YearDay(); year != v.year || yearDay != v.yearDay {
+		*v = VWAP{year: year, yearDay: yearDay}
+	}
+
+	typical := (candle.High.Float64 + candle.Low.Float64 + candle.Close.Float64) / 3
+	v.priceVolume += typical * candle.Volume.Float64
+	v.volume += candle.Volume.Float64
+
+	if v.volume == 0 {
+		return typical, true
+	}
+
+	return v.priceVolume / v.volume, true
+}
+
+func (v *VWAP) Peek(candle twelvedata.TimeSeriesCandle) (float64, bool) {
+	c := *v
+	return c.Update(candle)
+}
+
+// ComputeVWAP returns the volume weighted average price for each candle
+func ComputeVWAP(candles []twelvedata.TimeSeriesCandle) []float64 {
+	return compute[float64](NewVWAP(), candles, 0)
+}
+
+// OBV is the on-balance volume, starting from zero at the first candle
+type OBV struct {
+	value     float64
+	prevClose float64
+	started   bool
+}
+
+func NewOBV() *OBV {
+	return &OBV{}
+}
+
+func (o *OBV) Update(candle twelvedata.TimeSeriesCandle) (float64, bool) {
+	if o.started {
+		switch {
+		case candle.Close.Float64 > o.prevClose:
+			o.value += candle.Volume.Float64
+		case candle.Close.Float64 < o.prevClose:
+			o.value -= candle.Volume.Float64
+		}
+	}
+
+	o.started = true
+	o.prevClose = candle.Close.Float64
+
+	return o.value, true
+}
+
+func (o *OBV) Peek(candle twelvedata.TimeSeriesCandle) (float64, bool) {
+	c := *o
+	return c.Update(candle)
+}
+
+// ComputeOBV returns the on-balance volume for each candle
+func ComputeOBV(candles []twelvedata.TimeSeriesCandle) []float64 {
+	return compute[float64](NewOBV(), candles, 0)
+}
@@ -0,0 +1,36 @@
+package indicators_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/jonnotjohn/twelvedata-go/indicators"
+)
+
+func TestVWAP(t *testing.T) {
+	candles := loadCandles(t, "candles_30min.json")
+
+	values := indicators.ComputeVWAP(candles)
+	checkReference(t, "vwap.json", candles, values, single("vwap"))
+	checkStreaming[float64](t, indicators.NewVWAP(), candles, values, single("vwap"))
+
+	// Each session starts over from the typical price of its first bar
+	for i, candle := range candles {
+		if candle.DateTime.Format("15:04") != "09:30" {
+			continue
+		}
+
+		typical := (candle.High.Float64 + candle.Low.Float64 + candle.Close.Float64) / 3
+		if math.Abs(values[i]-typical) > 1e-9 {
+			t.Errorf("VWAP at %s = %v, want the typical price %v", candle.DateTime, values[i], typical)
+		}
+	}
+}
+
+func TestOBV(t *testing.T) {
+	candles := loadCandles(t, "candles_30min.json")
+
+	values := indicators.ComputeOBV(candles)
+	checkReference(t, "obv.json", candles, values, single("obv"))
+	checkStreaming[float64](t, indicators.NewOBV(), candles, values, single("obv"))
+}
@@ -0,0 +1,109 @@
+package twelvedata
+
+import (
+	"strings"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+)
+
+const (
+	urlEndpointInsiderTransactions = "/insider_transactions"
+)
+
+type InsiderTransaction struct {
+	FullName     string  `json:"full_name"`     // Full name of the insider
+	Position     string  `json:"position"`      // Position of the insider in the company (e.g. "Director")
+	DateReported TDTime  `json:"date_reported"` // Date the transaction was reported
+	IsDirect     bool    `json:"is_direct"`     // Whether the insider holds the shares directly
+	Shares       float64 `json:"shares"`        // Number of shares in the transaction
+	Value        float64 `json:"value"`         // Total value of the transaction
+	Description  string  `json:"description"`   // Description of the transaction (e.g. "Sale at price 132.57 per share.")
+}
+
+// IsPurchase reports whether the transaction is an open market purchase
+func (t InsiderTransaction) IsPurchase() bool {
+	return strings.HasPrefix(strings.ToLower(t.Description), "purchase")
+}
+
+// IsSale reports whether the transaction is an open market sale
+func (t InsiderTransaction) IsSale() bool {
+	return strings.HasPrefix(strings.ToLower(t.Description), "sale")
+}
+
+type InsiderTransactionsResponse struct {
+	Meta                FundamentalsMeta     `json:"meta"`
+	InsiderTransactions []InsiderTransaction `json:"insider_transactions"`
+}
+
+// NetInsiderBuying is the result of aggregating insider purchases and sales over a window
+type NetInsiderBuying struct {
+	PurchasedShares float64
+	PurchasedValue  float64
+	SoldShares      float64
+	SoldValue       float64
+	NetShares       float64 // PurchasedShares - SoldShares
+	NetValue        float64 // PurchasedValue - SoldValue
+}
+
+// ComputeNetInsiderBuying sums purchases and sales reported within [from, to]. A zero from or to leaves that side of
+// the window open. Transactions that are neither purchases nor sales (gifts, option exercises, ...) are ignored.
+func ComputeNetInsiderBuying(transactions []InsiderTransaction, from, to time.Time) NetInsiderBuying {
+	var result NetInsiderBuying
+
+	for _, t := range transactions {
+		if !from.IsZero() && t.DateReported.Before(from) {
+			continue
+		}
+
+		if !to.IsZero() && t.DateReported.After(to) {
+			continue
+		}
+
+		switch {
+		case t.IsPurchase():
+			result.PurchasedShares += t.Shares
+			result.PurchasedValue += t.Value
+		case t.IsSale():
+			result.SoldShares += t.Shares
+			result.SoldValue += t.Value
+		}
+	}
+
+	result.NetShares = result.PurchasedShares - result.SoldShares
+	result.NetValue = result.PurchasedValue - result.SoldValue
+
+	return result
+}
+
+func (c *APIClient) GetInsiderTransactions(req FundamentalsRequest) (insiderTransactions *InsiderTransactionsResponse, err error) {
+	params, err := req.ToParams()
+	if err != nil {
+		return nil, errors.Wrap(err, "Error converting FundamentalsRequest to params")
+	}
+
+	data, err := c.Client.Get(urlEndpointInsiderTransactions, params)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error fetching insider transactions data")
+	}
+
+	err = c.Client.unmarshal(jsoniter.ConfigDefault, data, &insiderTransactions, "insider transactions")
+	if err != nil {
+		return nil, err
+	}
+
+	if insiderTransactions != nil {
+		loc, err := insiderTransactions.Meta.Location()
+		if err != nil {
+			return nil, errors.Wrap(err, "Error localizing insider transactions response")
+		}
+
+		for i := range insiderTransactions.InsiderTransactions {
+			transaction := &insiderTransactions.InsiderTransactions[i]
+			transaction.DateReported = transaction.DateReported.Localize(loc)
+		}
+	}
+
+	return insiderTransactions, nil
+}
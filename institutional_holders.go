@@ -0,0 +1,40 @@
+package twelvedata
+
+import (
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+)
+
+const (
+	urlEndpointInstitutionalHolders = "/institutional_holders"
+)
+
+type InstitutionalHoldersResponse struct {
+	Meta                 FundamentalsMeta `json:"meta"`
+	InstitutionalHolders []Holder         `json:"institutional_holders"`
+}
+
+func (c *APIClient) GetInstitutionalHolders(req FundamentalsRequest) (institutionalHolders *InstitutionalHoldersResponse, err error) {
+	params, err := req.ToParams()
+	if err != nil {
+		return nil, errors.Wrap(err, "Error converting FundamentalsRequest to params")
+	}
+
+	data, err := c.Client.Get(urlEndpointInstitutionalHolders, params)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error fetching institutional holders data")
+	}
+
+	err = c.Client.unmarshal(jsoniter.ConfigDefault, data, &institutionalHolders, "institutional holders")
+	if err != nil {
+		return nil, err
+	}
+
+	if institutionalHolders != nil {
+		if err := localizeHolders(institutionalHolders.InstitutionalHolders, institutionalHolders.Meta); err != nil {
+			return nil, errors.Wrap(err, "Error localizing institutional holders response")
+		}
+	}
+
+	return institutionalHolders, nil
+}
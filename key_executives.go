@@ -0,0 +1,42 @@
+package twelvedata
+
+import (
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+)
+
+const (
+	urlEndpointKeyExecutives = "/key_executives"
+)
+
+type KeyExecutive struct {
+	Name     string  `json:"name"`      // Full name of the executive
+	Title    string  `json:"title"`     // Title of the executive (e.g. "CEO & Director")
+	Age      int     `json:"age"`       // Age of the executive
+	YearBorn int     `json:"year_born"` // Year the executive was born
+	Pay      float64 `json:"pay"`       // Annual compensation
+}
+
+type KeyExecutivesResponse struct {
+	Meta          FundamentalsMeta `json:"meta"`
+	KeyExecutives []KeyExecutive   `json:"key_executives"`
+}
+
+func (c *APIClient) GetKeyExecutives(req FundamentalsRequest) (keyExecutives *KeyExecutivesResponse, err error) {
+	params, err := req.ToParams()
+	if err != nil {
+		return nil, errors.Wrap(err, "Error converting FundamentalsRequest to params")
+	}
+
+	data, err := c.Client.Get(urlEndpointKeyExecutives, params)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error fetching key executives data")
+	}
+
+	err = c.Client.unmarshal(jsoniter.ConfigDefault, data, &keyExecutives, "key executives")
+	if err != nil {
+		return nil, err
+	}
+
+	return keyExecutives, nil
+}
@@ -0,0 +1,51 @@
+package twelvedata
+
+import (
+	"log/slog"
+
+	"go.uber.org/zap"
+)
+
+// Logger receives the log events of the client. The level is chosen per event: retried requests are logged at warn
+// and requests that failed after the last attempt at error. keysAndValues alternate keys and values, like in log/slog,
+// so *slog.Logger implements Logger as is.
+type Logger interface {
+	Debug(msg string, keysAndValues ...any)
+	Info(msg string, keysAndValues ...any)
+	Warn(msg string, keysAndValues ...any)
+	Error(msg string, keysAndValues ...any)
+}
+
+var _ Logger = (*slog.Logger)(nil)
+
+// NopLogger discards all log events. It is the default of NewAPIClient without Config.Debug.
+type NopLogger struct{}
+
+func (NopLogger) Debug(string, ...any) {}
+func (NopLogger) Info(string, ...any)  {}
+func (NopLogger) Warn(string, ...any)  {}
+func (NopLogger) Error(string, ...any) {}
+
+// NewSlogLogger returns a Logger writing to logger, or to slog.Default() when logger is nil
+func NewSlogLogger(logger *slog.Logger) Logger {
+	if logger == nil {
+		return slog.Default()
+	}
+
+	return logger
+}
+
+// zapLogger adapts a *zap.Logger to Logger
+type zapLogger struct {
+	sugar *zap.SugaredLogger
+}
+
+// NewZapLogger returns a Logger writing to logger. Keys and values become zap fields.
+func NewZapLogger(logger *zap.Logger) Logger {
+	return zapLogger{sugar: logger.WithOptions(zap.AddCallerSkip(1)).Sugar()}
+}
+
+func (l zapLogger) Debug(msg string, keysAndValues ...any) { l.sugar.Debugw(msg, keysAndValues...) }
+func (l zapLogger) Info(msg string, keysAndValues ...any)  { l.sugar.Infow(msg, keysAndValues...) }
+func (l zapLogger) Warn(msg string, keysAndValues ...any)  { l.sugar.Warnw(msg, keysAndValues...) }
+func (l zapLogger) Error(msg string, keysAndValues ...any) { l.sugar.Errorw(msg, keysAndValues...) }
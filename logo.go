@@ -54,9 +54,9 @@ func (c *APIClient) GetLogo(req LogoRequest) (logo *Logo, err error) {
 		return nil, errors.Wrap(err, "Error fetching logo data")
 	}
 
-	err = jsoniter.Unmarshal(data.Body(), &logo)
+	err = c.Client.unmarshal(jsoniter.ConfigDefault, data, &logo, "logo")
 	if err != nil {
-		return nil, errors.Wrap(err, "Error unmarshalling logo response")
+		return nil, err
 	}
 
 	return logo, nil
@@ -0,0 +1,116 @@
+package twelvedata
+
+import (
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+)
+
+const (
+	urlEndpointMarketMovers = "/market_movers/"
+)
+
+type MarketMoversMarket string
+
+const (
+	MarketMoversMarketStocks      MarketMoversMarket = "stocks"
+	MarketMoversMarketETF         MarketMoversMarket = "etf"
+	MarketMoversMarketMutualFunds MarketMoversMarket = "mutual_funds"
+	MarketMoversMarketForex       MarketMoversMarket = "forex"
+	MarketMoversMarketCrypto      MarketMoversMarket = "crypto"
+)
+
+type MarketMoversDirection string
+
+const (
+	MarketMoversDirectionGainers MarketMoversDirection = "gainers"
+	MarketMoversDirectionLosers  MarketMoversDirection = "losers"
+)
+
+// MarketMoversRequest is the available parameters for a market movers request
+type MarketMoversRequest struct {
+	Market           *MarketMoversMarket    // Required: Market to return movers for (e.g. "stocks", "crypto")
+	Direction        *MarketMoversDirection // Return top gainers or top losers (default is "gainers")
+	OutputSize       *int                   // Number of movers to return (default is 30, max is 50)
+	Country          *string                // Country code, for stocks, ETFs and mutual funds (e.g. "US" or "United States")
+	PriceGreaterThan *float64               // Only return instruments priced above this value
+	DP               *int                   // Number of decimal places for float values. Supports 0-11, default is 5
+}
+
+func (req MarketMoversRequest) ToParams() (map[string]string, error) {
+	params := make(map[string]string)
+
+	if req.Market == nil {
+		return nil, errors.New("market is required")
+	}
+
+	if req.Direction != nil {
+		params["direction"] = string(*req.Direction)
+	}
+
+	AddStringParam(params, "country", req.Country)
+
+	AddIntParam(params, "outputsize", req.OutputSize)
+	AddIntParam(params, "dp", req.DP)
+
+	AddFloatParam(params, "price_greater_than", req.PriceGreaterThan)
+
+	return params, nil
+}
+
+type MarketMover struct {
+	Symbol        string  `json:"symbol"`         // Symbol of the instrument (e.g. "AAPL", "BTC/USD")
+	Name          string  `json:"name"`           // Full name of the instrument
+	Exchange      string  `json:"exchange"`       // Exchange code (e.g. "NASDAQ")
+	MicCode       string  `json:"mic_code"`       // Market Identifier Code (e.g. "XNAS" for NASDAQ)
+	DateTime      TDTime  `json:"datetime"`       // Time of the last price, localized as described by GetMarketMovers
+	Last          float64 `json:"last"`           // Last price
+	High          float64 `json:"high"`           // Highest price of the day
+	Low           float64 `json:"low"`            // Lowest price of the day
+	Volume        float64 `json:"volume"`         // Traded volume of the day
+	Change        float64 `json:"change"`         // Price change since previous close
+	PercentChange float64 `json:"percent_change"` // Percent price change since previous close
+}
+
+type MarketMoversResponse struct {
+	Values []MarketMover `json:"values"`
+	Status string        `json:"status"`
+}
+
+// Localize returns the mover with its time in loc, see TDTime.Localize
+func (m MarketMover) Localize(loc *time.Location) MarketMover {
+	m.DateTime = m.DateTime.Localize(loc)
+	return m
+}
+
+// GetMarketMovers returns the top gainers or losers of a market. Movers have no timezone of their own, so their times
+// are localized to the exchange timezone of their listing when an earlier time series response cached it, or else to
+// UTC. Unlike GetQuote, missing timezones are never looked up, which could cost a credit per mover.
+func (c *APIClient) GetMarketMovers(req MarketMoversRequest) (marketMovers *MarketMoversResponse, err error) {
+	params, err := req.ToParams()
+	if err != nil {
+		return nil, errors.Wrap(err, "Error converting MarketMoversRequest to params")
+	}
+
+	data, err := c.Client.Get(urlEndpointMarketMovers+string(*req.Market), params)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error fetching market movers data")
+	}
+
+	err = c.Client.unmarshal(jsoniter.ConfigDefault, data, &marketMovers, "market movers")
+	if err != nil {
+		return nil, err
+	}
+
+	if marketMovers != nil {
+		for i, mover := range marketMovers.Values {
+			marketMovers.Values[i] = mover.Localize(c.cachedListingLocation(
+				TimeSeriesRequest{Symbol: &mover.Symbol, MicCode: &mover.MicCode},
+				TimeSeriesRequest{Symbol: &mover.Symbol},
+			))
+		}
+	}
+
+	return marketMovers, nil
+}
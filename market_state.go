@@ -0,0 +1,76 @@
+package twelvedata
+
+import (
+	"strings"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+)
+
+const (
+	urlEndpointMarketState = "/market_state"
+)
+
+// MarketStateRequest is the available parameters for a market state request. All fields are optional; without any
+// filter the state of every exchange is returned.
+type MarketStateRequest struct {
+	Exchange *string // Exchange name (e.g. "NYSE", "NASDAQ")
+	Code     *string // Market Identifier Code (e.g. "XNYS")
+	Country  *string // Country code (e.g. "US" or "United States")
+}
+
+func (req MarketStateRequest) ToParams() (map[string]string, error) {
+	params := make(map[string]string)
+
+	AddStringParam(params, "exchange", req.Exchange)
+	AddStringParam(params, "code", req.Code)
+	AddStringParam(params, "country", req.Country)
+
+	return params, nil
+}
+
+type MarketState struct {
+	Name          string     `json:"name"`            // Exchange name (e.g. "NYSE")
+	Code          string     `json:"code"`            // Market Identifier Code (e.g. "XNYS")
+	Country       string     `json:"country"`         // Country of the exchange (e.g. "United States")
+	IsMarketOpen  bool       `json:"is_market_open"`  // Whether the exchange is currently open
+	TimeAfterOpen TDDuration `json:"time_after_open"` // Time elapsed since the market opened (zero when closed)
+	TimeToOpen    TDDuration `json:"time_to_open"`    // Time until the market opens (zero when open)
+	TimeToClose   TDDuration `json:"time_to_close"`   // Time until the market closes (zero when closed)
+}
+
+func (c *APIClient) GetMarketState(req MarketStateRequest) (marketStates []MarketState, err error) {
+	params, err := req.ToParams()
+	if err != nil {
+		return nil, errors.Wrap(err, "Error converting MarketStateRequest to params")
+	}
+
+	data, err := c.Client.Get(urlEndpointMarketState, params)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error fetching market state data")
+	}
+
+	err = c.Client.unmarshal(jsoniter.ConfigDefault, data, &marketStates, "market state")
+	if err != nil {
+		return nil, err
+	}
+
+	return marketStates, nil
+}
+
+// IsExchangeOpen reports whether the given exchange (name or Market Identifier Code, case-insensitive) is currently
+// open, using a single market state call instead of a full quote.
+func (c *APIClient) IsExchangeOpen(exchange string) (bool, error) {
+	marketStates, err := c.GetMarketState(MarketStateRequest{})
+	if err != nil {
+		return false, err
+	}
+
+	for _, state := range marketStates {
+		if strings.EqualFold(state.Code, exchange) || strings.EqualFold(state.Name, exchange) {
+			return state.IsMarketOpen, nil
+		}
+	}
+
+	return false, errors.Errorf("exchange %s not found in market state response", exchange)
+}
@@ -0,0 +1,54 @@
+package twelvedata_test
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestIsExchangeOpen(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/market_state", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		query.Del("apikey")
+		if len(query) != 0 {
+			t.Errorf("market state request filtered by %v", query)
+		}
+
+		w.Write([]byte(`[
+			{"name":"NYSE","code":"XNYS","country":"United States","is_market_open":true},
+			{"name":"LSE","code":"XLON","country":"United Kingdom","is_market_open":false},
+			{"name":"XETR","code":"XETR","country":"Germany","is_market_open":true},
+			{"name":"Euronext","code":"XPAR","country":"France","is_market_open":true}
+		]`))
+	})
+
+	client := handlerClient(t, mux)
+
+	tests := []struct {
+		exchange string
+		want     bool
+		wantErr  bool
+	}{
+		{exchange: "NYSE", want: true},
+		{exchange: "xnys", want: true},
+		{exchange: "lse", want: false},
+		{exchange: "XLON", want: false},
+		{exchange: "XETR", want: true},
+		{exchange: "euronext", want: true},
+		{exchange: "XPAR", want: true},
+		{exchange: "NASDAQ", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.exchange, func(t *testing.T) {
+			got, err := client.IsExchangeOpen(tt.exchange)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("IsExchangeOpen(%q) error = %v, wantErr %v", tt.exchange, err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("IsExchangeOpen(%q) = %v, want %v", tt.exchange, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,279 @@
+package twelvedata
+
+import (
+	"math"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+)
+
+const (
+	urlEndpointOptionsExpiration = "/options/expiration"
+	urlEndpointOptionsChain      = "/options/chain"
+)
+
+type OptionSide string
+
+const (
+	OptionSideCall OptionSide = "call"
+	OptionSidePut  OptionSide = "put"
+)
+
+// OptionsExpirationRequest is the available parameters for an options expiration request
+type OptionsExpirationRequest struct {
+	Symbol   *string // Required: Symbol of the underlying asset (e.g. "AAPL")
+	FIGI     *string // Financial Instrument Global Identifier
+	ISIN     *string // International Securities Identification Number
+	CUSIP    *string // Committee on Uniform Securities Identification Procedures
+	Exchange *string // Exchange code (e.g. "NASDAQ")
+	MicCode  *string // Market Identifier Code (e.g. "XNAS" for NASDAQ)
+	Country  *string // Country code (e.g. "US" or "United States")
+}
+
+func (req OptionsExpirationRequest) ToParams() (map[string]string, error) {
+	params := make(map[string]string)
+
+	if req.Symbol == nil {
+		return nil, errors.New("symbol is required")
+	}
+
+	AddStringParam(params, "symbol", req.Symbol)
+	AddStringParam(params, "figi", req.FIGI)
+	AddStringParam(params, "isin", req.ISIN)
+	AddStringParam(params, "cusip", req.CUSIP)
+	AddStringParam(params, "exchange", req.Exchange)
+	AddStringParam(params, "mic_code", req.MicCode)
+	AddStringParam(params, "country", req.Country)
+
+	return params, nil
+}
+
+type OptionsMeta struct {
+	Symbol           string `json:"symbol"`
+	Name             string `json:"name"`
+	Currency         string `json:"currency"`
+	Exchange         string `json:"exchange"`
+	MicCode          string `json:"mic_code"`
+	ExchangeTimezone string `json:"exchange_timezone"`
+}
+
+// Location returns the exchange timezone of the meta block, or UTC when it is empty
+func (m OptionsMeta) Location() (*time.Location, error) {
+	return resolveLocation(nil, m.ExchangeTimezone)
+}
+
+type OptionsExpirationResponse struct {
+	Meta  OptionsMeta `json:"meta"`
+	Dates []TDTime    `json:"dates"` // Available expiration dates
+}
+
+// OptionChainRequest is the available parameters for an option chain request
+type OptionChainRequest struct {
+	Symbol         *string     // Required: Symbol of the underlying asset (e.g. "AAPL")
+	FIGI           *string     // Financial Instrument Global Identifier
+	ISIN           *string     // International Securities Identification Number
+	CUSIP          *string     // Committee on Uniform Securities Identification Procedures
+	Exchange       *string     // Exchange code (e.g. "NASDAQ")
+	MicCode        *string     // Market Identifier Code (e.g. "XNAS" for NASDAQ)
+	Country        *string     // Country code (e.g. "US" or "United States")
+	ExpirationDate *time.Time  // Expiration date of the contracts (time is ignored). Defaults to the nearest expiration
+	OptionID       *string     // Return only the contract with this ID (e.g. "AAPL210827C00095000")
+	Side           *OptionSide // Return only calls or puts. Defaults to both
+	Strike         *float64    // Return only contracts with this strike price
+	MinStrike      *float64    // Drop contracts with a strike below this value (applied after the response is received)
+	MaxStrike      *float64    // Drop contracts with a strike above this value (applied after the response is received)
+}
+
+func (req OptionChainRequest) ToParams() (map[string]string, error) {
+	params := make(map[string]string)
+
+	if req.Symbol == nil {
+		return nil, errors.New("symbol is required")
+	}
+
+	if req.Side != nil {
+		if *req.Side != OptionSideCall && *req.Side != OptionSidePut {
+			return nil, errors.Errorf("invalid option side %q", *req.Side)
+		}
+		params["side"] = string(*req.Side)
+	}
+
+	if req.MinStrike != nil && req.MaxStrike != nil && *req.MinStrike > *req.MaxStrike {
+		return nil, errors.New("min strike must not be greater than max strike")
+	}
+
+	AddStringParam(params, "symbol", req.Symbol)
+	AddStringParam(params, "figi", req.FIGI)
+	AddStringParam(params, "isin", req.ISIN)
+	AddStringParam(params, "cusip", req.CUSIP)
+	AddStringParam(params, "exchange", req.Exchange)
+	AddStringParam(params, "mic_code", req.MicCode)
+	AddStringParam(params, "country", req.Country)
+	AddStringParam(params, "option_id", req.OptionID)
+
+	AddFloatParam(params, "strike", req.Strike)
+
+	AddDateParam(params, "expiration_date", req.ExpirationDate, "2006-01-02")
+
+	return params, nil
+}
+
+type OptionContract struct {
+	ContractName      string  `json:"contract_name"`      // Name of the contract (e.g. "AAPL210827C00095000")
+	OptionID          string  `json:"option_id"`          // Unique identifier of the contract
+	LastTradeDate     TDTime  `json:"last_trade_date"`    // Time of the last trade
+	Strike            float64 `json:"strike"`             // Strike price
+	LastPrice         float64 `json:"last_price"`         // Last traded price
+	Bid               float64 `json:"bid"`                // Current bid price
+	Ask               float64 `json:"ask"`                // Current ask price
+	Change            float64 `json:"change"`             // Price change since previous close
+	PercentChange     float64 `json:"percent_change"`     // Percent price change since previous close
+	Volume            float64 `json:"volume"`             // Traded volume
+	OpenInterest      float64 `json:"open_interest"`      // Number of open contracts
+	ImpliedVolatility float64 `json:"implied_volatility"` // Implied volatility (e.g. 0.25 for 25%)
+	InTheMoney        bool    `json:"in_the_money"`       // Whether the contract is in the money
+}
+
+type OptionChainResponse struct {
+	Meta  OptionsMeta      `json:"meta"`
+	Calls []OptionContract `json:"calls"`
+	Puts  []OptionContract `json:"puts"`
+}
+
+// filterStrikes drops contracts with a strike outside [minStrike, maxStrike]. Nil bounds are open.
+func (r *OptionChainResponse) filterStrikes(minStrike, maxStrike *float64) {
+	inRange := func(contracts []OptionContract) []OptionContract {
+		filtered := contracts[:0]
+		for _, contract := range contracts {
+			if minStrike != nil && contract.Strike < *minStrike {
+				continue
+			}
+
+			if maxStrike != nil && contract.Strike > *maxStrike {
+				continue
+			}
+
+			filtered = append(filtered, contract)
+		}
+
+		return filtered
+	}
+
+	r.Calls = inRange(r.Calls)
+	r.Puts = inRange(r.Puts)
+}
+
+// AtTheMoneyStrike returns the strike in the chain closest to the quote's close price.
+// ok is false when the chain has no contracts or the quote has no close price.
+func (r *OptionChainResponse) AtTheMoneyStrike(quote Quote) (strike float64, ok bool) {
+	if !quote.Close.Valid {
+		return 0, false
+	}
+	return closestStrike(quote.Close.Float64, r.Calls, r.Puts)
+}
+
+// AtTheMoney returns the call and put at the at-the-money strike relative to the quote's close price.
+// Either contract is nil when the chain has no contract of that side at the strike.
+func (r *OptionChainResponse) AtTheMoney(quote Quote) (call *OptionContract, put *OptionContract) {
+	strike, ok := r.AtTheMoneyStrike(quote)
+	if !ok {
+		return nil, nil
+	}
+
+	for i := range r.Calls {
+		if r.Calls[i].Strike == strike {
+			call = &r.Calls[i]
+			break
+		}
+	}
+
+	for i := range r.Puts {
+		if r.Puts[i].Strike == strike {
+			put = &r.Puts[i]
+			break
+		}
+	}
+
+	return call, put
+}
+
+func closestStrike(price float64, contractSets ...[]OptionContract) (strike float64, ok bool) {
+	bestDistance := math.Inf(1)
+	for _, contracts := range contractSets {
+		for _, contract := range contracts {
+			distance := math.Abs(contract.Strike - price)
+			if distance < bestDistance || (distance == bestDistance && contract.Strike < strike) {
+				bestDistance = distance
+				strike = contract.Strike
+				ok = true
+			}
+		}
+	}
+
+	return strike, ok
+}
+
+func (c *APIClient) GetOptionsExpiration(req OptionsExpirationRequest) (expiration *OptionsExpirationResponse, err error) {
+	params, err := req.ToParams()
+	if err != nil {
+		return nil, errors.Wrap(err, "Error converting OptionsExpirationRequest to params")
+	}
+
+	data, err := c.Client.Get(urlEndpointOptionsExpiration, params)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error fetching options expiration data")
+	}
+
+	err = c.Client.unmarshal(jsoniter.ConfigDefault, data, &expiration, "options expiration")
+	if err != nil {
+		return nil, err
+	}
+
+	if expiration != nil {
+		loc, err := expiration.Meta.Location()
+		if err != nil {
+			return nil, errors.Wrap(err, "Error localizing options expiration response")
+		}
+
+		for i := range expiration.Dates {
+			expiration.Dates[i] = expiration.Dates[i].Localize(loc)
+		}
+	}
+
+	return expiration, nil
+}
+
+func (c *APIClient) GetOptionChain(req OptionChainRequest) (chain *OptionChainResponse, err error) {
+	params, err := req.ToParams()
+	if err != nil {
+		return nil, errors.Wrap(err, "Error converting OptionChainRequest to params")
+	}
+
+	data, err := c.Client.Get(urlEndpointOptionsChain, params)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error fetching option chain data")
+	}
+
+	err = c.Client.unmarshal(jsoniter.ConfigDefault, data, &chain, "option chain")
+	if err != nil {
+		return nil, err
+	}
+
+	if chain != nil {
+		loc, err := chain.Meta.Location()
+		if err != nil {
+			return nil, errors.Wrap(err, "Error localizing option chain response")
+		}
+
+		for _, contracts := range [][]OptionContract{chain.Calls, chain.Puts} {
+			for i := range contracts {
+				contracts[i].LastTradeDate = contracts[i].LastTradeDate.Localize(loc)
+			}
+		}
+
+		chain.filterStrikes(req.MinStrike, req.MaxStrike)
+	}
+
+	return chain, nil
+}
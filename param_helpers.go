@@ -28,3 +28,9 @@ func AddDateParam(params map[string]string, key string, value *time.Time, format
 		params[key] = value.Format(format)
 	}
 }
+
+func AddFloatParam(params map[string]string, key string, value *float64) {
+	if value != nil {
+		params[key] = strconv.FormatFloat(*value, 'f', -1, 64)
+	}
+}
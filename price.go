@@ -0,0 +1,71 @@
+package twelvedata
+
+import (
+	"github.com/pkg/errors"
+)
+
+const (
+	urlEndpointPrice = "/price"
+)
+
+// PriceRequest is the available parameters for a price request
+type PriceRequest struct {
+	Symbol           *string // Required: Symbol of the asset (e.g. "AAPL", "BTC/USD")
+	FIGI             *string // Financial Instrument Global Identifier
+	ISIN             *string // International Securities Identification Number
+	CUSIP            *string // Committee on Uniform Securities Identification Procedures
+	Exchange         *string // Exchange code (e.g. "NASDAQ", "Binance")
+	MicCode          *string // Market Identifier Code (e.g. "XNAS" for NASDAQ)
+	Country          *string // Country code (e.g. "US" or "United States")
+	Type             *string // Type of asset (e.g. "Digital currency", "Common stock")
+	PrePost          *bool   // Include pre/post market data (default is false)
+	DP               *int    // Number of decimal places for float values. Supports 0-11, default is 5
+	PreserveDecimals *bool   // Keep the exact decimals of prices in TDFloat.Decimal (defaults to Config.PreserveDecimals)
+}
+
+func (req PriceRequest) ToParams() (map[string]string, error) {
+	params := make(map[string]string)
+
+	if req.Symbol == nil {
+		return nil, errors.New("symbol is required")
+	}
+
+	AddStringParam(params, "symbol", req.Symbol)
+	AddStringParam(params, "figi", req.FIGI)
+	AddStringParam(params, "isin", req.ISIN)
+	AddStringParam(params, "cusip", req.CUSIP)
+	AddStringParam(params, "exchange", req.Exchange)
+	AddStringParam(params, "mic_code", req.MicCode)
+	AddStringParam(params, "country", req.Country)
+	AddStringParam(params, "type", req.Type)
+
+	AddIntParam(params, "dp", req.DP)
+
+	AddBoolParam(params, "prepost", req.PrePost)
+
+	return params, nil
+}
+
+// Price is the latest price of a symbol, a lighter alternative to a quote
+type Price struct {
+	Price TDFloat `json:"price"`
+}
+
+func (c *APIClient) GetPrice(req PriceRequest) (price *Price, err error) {
+	params, err := req.ToParams()
+	if err != nil {
+		return nil, errors.Wrap(err, "Error converting PriceRequest to params")
+	}
+
+	data, err := c.Client.Get(urlEndpointPrice, params)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error fetching price data")
+	}
+
+	err = c.Client.unmarshal(c.jsonAPI(req.PreserveDecimals), data, &price, "price")
+	if err != nil {
+		return nil, err
+	}
+
+	return price, nil
+}
@@ -1,7 +1,9 @@
 package twelvedata
 
 import (
-	jsoniter "github.com/json-iterator/go"
+	"context"
+	"time"
+
 	"github.com/pkg/errors"
 )
 
@@ -42,6 +44,7 @@ type QuoteRequest struct {
 	RollingPeriod    *int           // Number of hours for calculate rolling change at period
 	DP               *int           // Number of decimal places for float values. Supports 0-11, default is 5
 	TimeZone         *string        // Timezone for the response (e.g. "America/New_York", "UTC"). Defaults to "Exchange"
+	PreserveDecimals *bool          // Keep the exact decimals of prices in TDFloat.Decimal (defaults to Config.PreserveDecimals)
 }
 
 func (req QuoteRequest) ToParams() (map[string]string, error) {
@@ -84,36 +87,52 @@ type Quote struct {
 	DateTime              TDTime            `json:"datetime"`
 	Timestamp             TDTime            `json:"timestamp"`
 	LastQuoteAt           TDTime            `json:"last_quote_at"`
-	Open                  float64           `json:"open,string"`
-	High                  float64           `json:"high,string"`
-	Low                   float64           `json:"low,string"`
-	Close                 float64           `json:"close,string"`
-	Volume                float64           `json:"volume,string"`
-	PreviousClose         float64           `json:"previous_close,string"`
-	Change                float64           `json:"change,string"`
-	PercentChange         float64           `json:"percent_change,string"`
-	AverageVolume         float64           `json:"average_volume,string"`
-	Rolling1DayChange     float64           `json:"rolling_1day_change,string"`
-	Rolling7DayChange     float64           `json:"rolling_7day_change,string"`
-	RollingPeriodChange   float64           `json:"rolling_period_change,string"`
+	Open                  TDFloat           `json:"open"`
+	High                  TDFloat           `json:"high"`
+	Low                   TDFloat           `json:"low"`
+	Close                 TDFloat           `json:"close"`
+	Volume                TDFloat           `json:"volume"`
+	PreviousClose         TDFloat           `json:"previous_close"`
+	Change                TDFloat           `json:"change"`
+	PercentChange         TDFloat           `json:"percent_change"`
+	AverageVolume         TDFloat           `json:"average_volume"`
+	Rolling1DayChange     TDFloat           `json:"rolling_1day_change"`
+	Rolling7DayChange     TDFloat           `json:"rolling_7day_change"`
+	RollingPeriodChange   TDFloat           `json:"rolling_period_change"`
 	IsMarketOpen          bool              `json:"is_market_open"`
 	FiftyTwoWeek          QuoteFiftyTwoWeek `json:"fifty_two_week"`
-	ExtendedChange        float64           `json:"extended_change,string"`
-	ExtendedPercentChange float64           `json:"extended_percent_change,string"`
-	ExtendedPrice         float64           `json:"extended_price,string"`
+	ExtendedChange        TDFloat           `json:"extended_change"`
+	ExtendedPercentChange TDFloat           `json:"extended_percent_change"`
+	ExtendedPrice         TDFloat           `json:"extended_price"`
 	ExtendedTimestamp     int64             `json:"extended_timestamp"`
 }
 
 type QuoteFiftyTwoWeek struct {
-	Low               float64 `json:"low,string"`
-	High              float64 `json:"high,string"`
-	LowChange         float64 `json:"low_change,string"`
-	HighChange        float64 `json:"high_change,string"`
-	LowChangePercent  float64 `json:"low_change_percent,string"`
-	HighChangePercent float64 `json:"high_change_percent,string"`
+	Low               TDFloat `json:"low"`
+	High              TDFloat `json:"high"`
+	LowChange         TDFloat `json:"low_change"`
+	HighChange        TDFloat `json:"high_change"`
+	LowChangePercent  TDFloat `json:"low_change_percent"`
+	HighChangePercent TDFloat `json:"high_change_percent"`
 	Range             string  `json:"range"`
 }
 
+// Localize returns the quote with its times in loc, see TDTime.Localize
+func (q Quote) Localize(loc *time.Location) Quote {
+	q.DateTime = q.DateTime.Localize(loc)
+	q.Timestamp = q.Timestamp.Localize(loc)
+	q.LastQuoteAt = q.LastQuoteAt.Localize(loc)
+
+	return q
+}
+
+// GetQuote returns the latest quote of a symbol. The quote has no timezone of its own, so its times are localized to
+// req.TimeZone, or else to the exchange timezone of the listing when an earlier time series response cached it, or
+// else to UTC.
+//
+// With Config.QuoteTimezones, an exchange timezone missing from the cache is looked up with a single candle time series
+// request, which costs one more API credit on the first quote of each listing. A failed lookup falls back to UTC and
+// isn't repeated for the listing within the next hour.
 func (c *APIClient) GetQuote(req QuoteRequest) (quote *Quote, err error) {
 	params, err := req.ToParams()
 	if err != nil {
@@ -125,10 +144,105 @@ func (c *APIClient) GetQuote(req QuoteRequest) (quote *Quote, err error) {
 		return nil, errors.Wrap(err, "Error fetching quote data")
 	}
 
-	err = jsoniter.Unmarshal(data.Body(), &quote)
+	err = c.Client.unmarshal(c.jsonAPI(req.PreserveDecimals), data, &quote, "quote")
 	if err != nil {
-		return nil, errors.Wrap(err, "Error unmarshalling quote response")
+		return nil, err
+	}
+
+	if quote != nil {
+		*quote = quote.Localize(c.quoteLocation(req))
 	}
 
 	return quote, nil
 }
+
+// timezoneRetryInterval is the time during which a failed quote timezone lookup isn't repeated for the listing
+const timezoneRetryInterval = time.Hour
+
+// quoteLocation resolves the timezone of a quote, see GetQuote
+func (c *APIClient) quoteLocation(req QuoteRequest) *time.Location {
+	series := quoteSeriesRequest(req)
+	if loc, ok := c.cachedQuoteLocation(series); ok {
+		return loc
+	}
+
+	if !c.lookupQuoteTimezone(series) {
+		return time.UTC
+	}
+
+	loc, err := c.timeSeriesLocation(context.Background(), series)
+	if err != nil {
+		c.quoteTimezoneFailed(series, err)
+		return time.UTC
+	}
+
+	return loc
+}
+
+// cachedQuoteLocation returns the timezone of a quote when it is known without a lookup
+func (c *APIClient) cachedQuoteLocation(series TimeSeriesRequest) (*time.Location, bool) {
+	if series.TimeZone != nil && *series.TimeZone != "Exchange" {
+		// The API rejects unknown timezones, so this doesn't fail for a quote it answered
+		loc, err := time.LoadLocation(*series.TimeZone)
+		if err != nil {
+			return time.UTC, true
+		}
+		return loc, true
+	}
+
+	timezone, ok := c.timezones.Load(timezoneCacheKey(series))
+	if !ok {
+		return nil, false
+	}
+
+	loc, err := time.LoadLocation(timezone.(string))
+	return loc, err == nil
+}
+
+// cachedListingLocation returns the exchange timezone of the first listing found in the cache, or UTC. It never looks
+// a timezone up, for responses listing many instruments or without a timezone parameter.
+func (c *APIClient) cachedListingLocation(listings ...TimeSeriesRequest) *time.Location {
+	for _, listing := range listings {
+		if loc, ok := c.cachedQuoteLocation(listing); ok {
+			return loc
+		}
+	}
+
+	return time.UTC
+}
+
+// lookupQuoteTimezone tells whether the exchange timezone of a quote missing from the cache should be looked up
+func (c *APIClient) lookupQuoteTimezone(series TimeSeriesRequest) bool {
+	if !c.quoteTimezones {
+		return false
+	}
+
+	failed, ok := c.timezoneFailures.Load(timezoneCacheKey(series))
+	return !ok || time.Since(failed.(time.Time)) >= timezoneRetryInterval
+}
+
+// quoteTimezoneFailed remembers a failed lookup of the exchange timezone of a quote, so it isn't repeated for a while
+func (c *APIClient) quoteTimezoneFailed(series TimeSeriesRequest, err error) {
+	c.timezoneFailures.Store(timezoneCacheKey(series), time.Now())
+	c.Logger.Warn("Failed to resolve quote timezone, using UTC", "symbol", *series.Symbol, "error", err)
+}
+
+// quoteSeriesRequest returns the single daily candle request for the listing of a quote, used to look up its
+// exchange timezone
+func quoteSeriesRequest(req QuoteRequest) TimeSeriesRequest {
+	interval := TimeSeriesInterval1Day
+	outputSize := 1
+	return TimeSeriesRequest{
+		Symbol:     req.Symbol,
+		FIGI:       req.FIGI,
+		ISIN:       req.ISIN,
+		CUSIP:      req.CUSIP,
+		Interval:   &interval,
+		Exchange:   req.Exchange,
+		MicCode:    req.MicCode,
+		Country:    req.Country,
+		Type:       req.Type,
+		TimeZone:   req.TimeZone,
+		OutputSize: &outputSize,
+	}
+}
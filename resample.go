@@ -0,0 +1,263 @@
+package twelvedata
+
+import (
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ResampleOptions controls how candles are grouped into buckets by Resample and ResampleCandles
+type ResampleOptions struct {
+	SessionOpen          time.Duration // Session open as an offset from midnight in the exchange timezone (e.g. 9h30m). Intraday buckets are aligned to it; zero aligns them to midnight
+	SessionClose         time.Duration // Session close as an offset from midnight in the exchange timezone (e.g. 16h). Zero means the session runs until midnight
+	IncludeExtendedHours bool          // Keep pre/post-market candles outside [SessionOpen, SessionClose). Ignored when no session is set
+	DropPartial          bool          // Drop the first and last buckets when the candles don't cover their whole span
+}
+
+func (o ResampleOptions) hasSession() bool {
+	return o.SessionOpen != 0 || o.SessionClose != 0
+}
+
+// intervalDuration returns the length of intervals up to one week. Months don't have a fixed length.
+func intervalDuration(interval TimeSeriesInterval) (time.Duration, bool) {
+	switch interval {
+	case TimeSeriesInterval1Min:
+		return time.Minute, true
+	case TimeSeriesInterval5Min:
+		return 5 * time.Minute, true
+	case TimeSeriesInterval15Min:
+		return 15 * time.Minute, true
+	case TimeSeriesInterval30Min:
+		return 30 * time.Minute, true
+	case TimeSeriesInterval45Min:
+		return 45 * time.Minute, true
+	case TimeSeriesInterval1Hour:
+		return time.Hour, true
+	case TimeSeriesInterval2Hour:
+		return 2 * time.Hour, true
+	case TimeSeriesInterval4Hour:
+		return 4 * time.Hour, true
+	case TimeSeriesInterval5Hour:
+		return 5 * time.Hour, true
+	case TimeSeriesInterval1Day:
+		return 24 * time.Hour, true
+	case TimeSeriesInterval1Week:
+		return 7 * 24 * time.Hour, true
+	}
+
+	return 0, false
+}
+
+func isIntraday(interval TimeSeriesInterval) bool {
+	d, ok := intervalDuration(interval)
+	return ok && d < 24*time.Hour
+}
+
+// atOffset returns the wall clock time offset from midnight on the day of t, which stays correct across DST changes
+func atOffset(t time.Time, offset time.Duration) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, int(offset), t.Location())
+}
+
+// validateResample checks that every target bucket is made of whole source candles
+func validateResample(from, to TimeSeriesInterval) error {
+	if from == to {
+		return nil
+	}
+
+	fromDuration, fromOK := intervalDuration(from)
+	if !fromOK && from != TimeSeriesInterval1Month {
+		return errors.Errorf("unsupported source interval %s", from)
+	}
+
+	toDuration, toOK := intervalDuration(to)
+	if !toOK && to != TimeSeriesInterval1Month {
+		return errors.Errorf("unsupported target interval %s", to)
+	}
+
+	switch {
+	case from == TimeSeriesInterval1Month:
+		return errors.Errorf("cannot resample %s candles to %s", from, to)
+	case to == TimeSeriesInterval1Month && from == TimeSeriesInterval1Week:
+		return errors.Errorf("cannot resample %s candles to %s: weeks span month boundaries", from, to)
+	case to == TimeSeriesInterval1Month:
+		return nil
+	case toDuration < fromDuration:
+		return errors.Errorf("cannot resample %s candles to the shorter interval %s", from, to)
+	case isIntraday(to) && toDuration%fromDuration != 0:
+		return errors.Errorf("cannot resample %s candles to %s: not a whole multiple", from, to)
+	}
+
+	return nil
+}
+
+// bucketSpan returns the label of the bucket containing t and its span, clipped to the session when one is set and
+// extended hours are excluded. Daily and longer buckets are labelled at midnight like the API does.
+func bucketSpan(t time.Time, to TimeSeriesInterval, opts ResampleOptions) (label, start, end time.Time) {
+	switch to {
+	case TimeSeriesInterval1Day:
+		start = atOffset(t, 0)
+		end = start.AddDate(0, 0, 1)
+	case TimeSeriesInterval1Week:
+		// Weeks start on Monday
+		daysSinceMonday := (int(t.Weekday()) + 6) % 7
+		start = atOffset(t, 0).AddDate(0, 0, -daysSinceMonday)
+		end = start.AddDate(0, 0, 7)
+	case TimeSeriesInterval1Month:
+		y, m, _ := t.Date()
+		start = time.Date(y, m, 1, 0, 0, 0, 0, t.Location())
+		end = start.AddDate(0, 1, 0)
+	default:
+		d, _ := intervalDuration(to)
+		anchor := atOffset(t, opts.SessionOpen)
+		buckets := t.Sub(anchor) / d
+		if t.Before(anchor) && t.Sub(anchor)%d != 0 {
+			buckets--
+		}
+		start = anchor.Add(buckets * d)
+		end = start.Add(d)
+
+		// Buckets never cross midnight
+		if nextDay := atOffset(t, 0).AddDate(0, 0, 1); end.After(nextDay) {
+			end = nextDay
+		}
+	}
+
+	label = start
+
+	if opts.hasSession() && !opts.IncludeExtendedHours && to != TimeSeriesInterval1Week && to != TimeSeriesInterval1Month {
+		if sessionOpen := atOffset(start, opts.SessionOpen); start.Before(sessionOpen) {
+			start = sessionOpen
+		}
+
+		if opts.SessionClose != 0 {
+			if sessionClose := atOffset(start, opts.SessionClose); end.After(sessionClose) {
+				end = sessionClose
+			}
+		}
+	}
+
+	return label, start, end
+}
+
+// inSession reports whether a candle starting at t falls within the regular session
+func inSession(t time.Time, opts ResampleOptions) bool {
+	if !opts.hasSession() || opts.IncludeExtendedHours {
+		return true
+	}
+
+	if t.Before(atOffset(t, opts.SessionOpen)) {
+		return false
+	}
+
+	return opts.SessionClose == 0 || t.Before(atOffset(t, opts.SessionClose))
+}
+
+// candleEnd returns the end of the candle starting at t
+func candleEnd(t time.Time, interval TimeSeriesInterval) time.Time {
+	switch interval {
+	case TimeSeriesInterval1Day:
+		return t.AddDate(0, 0, 1)
+	case TimeSeriesInterval1Week:
+		return t.AddDate(0, 0, 7)
+	case TimeSeriesInterval1Month:
+		return t.AddDate(0, 1, 0)
+	}
+
+	d, _ := intervalDuration(interval)
+	return t.Add(d)
+}
+
+// ResampleCandles aggregates candles of the from interval into candles of the to interval. Candle times are
+// converted to loc (the exchange timezone) before bucketing, and each resulting candle is stamped with the start of
+// its bucket. Open is taken from the first candle, close from the last, high and low are the extremes and volume is
+// summed. The result keeps the ordering of the input (ascending or descending).
+func ResampleCandles(candles []TimeSeriesCandle, from, to TimeSeriesInterval, loc *time.Location, opts ResampleOptions) ([]TimeSeriesCandle, error) {
+	if err := validateResample(from, to); err != nil {
+		return nil, err
+	}
+
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	descending := len(candles) > 1 && candles[0].DateTime.After(candles[len(candles)-1].DateTime.Time)
+
+	sorted := make([]TimeSeriesCandle, 0, len(candles))
+	for _, candle := range candles {
+		candle.DateTime = TDZonedTime{Time: candle.DateTime.In(loc)}
+		if inSession(candle.DateTime.Time, opts) {
+			sorted = append(sorted, candle)
+		}
+	}
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].DateTime.Before(sorted[j].DateTime.Time)
+	})
+
+	type bucket struct {
+		candle            TimeSeriesCandle
+		label, start, end time.Time
+	}
+
+	var buckets []bucket
+	for _, candle := range sorted {
+		label, start, end := bucketSpan(candle.DateTime.Time, to, opts)
+
+		if n := len(buckets); n > 0 && buckets[n-1].label.Equal(label) {
+			b := &buckets[n-1].candle
+			b.High = maxTDFloat(b.High, candle.High)
+			b.Low = minTDFloat(b.Low, candle.Low)
+			b.Close = candle.Close
+			b.Volume = addTDFloat(b.Volume, candle.Volume)
+			continue
+		}
+
+		candle.DateTime = TDZonedTime{Time: label}
+		buckets = append(buckets, bucket{candle: candle, label: label, start: start, end: end})
+	}
+
+	if opts.DropPartial && len(sorted) > 0 {
+		coveredFrom := sorted[0].DateTime.Time
+		coveredTo := candleEnd(sorted[len(sorted)-1].DateTime.Time, from)
+
+		if len(buckets) > 0 && coveredFrom.After(buckets[0].start) {
+			buckets = buckets[1:]
+		}
+
+		if n := len(buckets); n > 0 && coveredTo.Before(buckets[n-1].end) {
+			buckets = buckets[:n-1]
+		}
+	}
+
+	resampled := make([]TimeSeriesCandle, len(buckets))
+	for i, b := range buckets {
+		if descending {
+			resampled[len(buckets)-1-i] = b.candle
+		} else {
+			resampled[i] = b.candle
+		}
+	}
+
+	return resampled, nil
+}
+
+// Resample aggregates the response's candles into the given interval, bucketing in the exchange timezone from Meta.
+// The returned response is a copy with Meta.Interval updated.
+func (r *TimeSeriesResponse) Resample(interval TimeSeriesInterval, opts ResampleOptions) (*TimeSeriesResponse, error) {
+	loc, err := time.LoadLocation(r.Meta.ExchangeTimezone)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load exchange timezone")
+	}
+
+	candles, err := ResampleCandles(r.Candles, TimeSeriesInterval(r.Meta.Interval), interval, loc, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	resampled := &TimeSeriesResponse{Meta: r.Meta, Candles: candles}
+	resampled.Meta.Interval = string(interval)
+
+	return resampled, nil
+}
@@ -0,0 +1,184 @@
+package twelvedata_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jonnotjohn/twelvedata-go"
+)
+
+// ohlcv lists candles with all their values as readable lines
+func ohlcv(candles []twelvedata.TimeSeriesCandle) []string {
+	lines := make([]string, len(candles))
+	for i, c := range candles {
+		lines[i] = fmt.Sprintf("%s %g/%g/%g/%g %g", c.DateTime.In(newYork).Format("2006-01-02 15:04"),
+			c.Open.Float64, c.High.Float64, c.Low.Float64, c.Close.Float64, c.Volume.Float64)
+	}
+
+	return lines
+}
+
+// weekdayBars returns daily bars from the first to the last date, skipping weekends, priced 100, 101 and so on
+func weekdayBars(first, last string) []twelvedata.TimeSeriesCandle {
+	var candles []twelvedata.TimeSeriesCandle
+	price := 100.0
+	for day := at(first + " 00:00"); !day.After(at(last + " 00:00")); day = day.AddDate(0, 0, 1) {
+		if day.Weekday() == time.Saturday || day.Weekday() == time.Sunday {
+			continue
+		}
+		candles = append(candles, bar(day.Format("2006-01-02 15:04"), price))
+		price++
+	}
+
+	return candles
+}
+
+func TestResample(t *testing.T) {
+	// Wednesday 2024-05-29 to Wednesday 2024-06-12
+	daily := weekdayBars("2024-05-29", "2024-06-12")
+	reversed := make([]twelvedata.TimeSeriesCandle, len(daily))
+	for i, candle := range daily {
+		reversed[len(daily)-1-i] = candle
+	}
+
+	halfHours := []twelvedata.TimeSeriesCandle{
+		bar("2024-06-14 09:00", 99), // Pre-market
+		bar("2024-06-14 09:30", 100),
+		bar("2024-06-14 10:00", 101),
+		bar("2024-06-14 10:30", 102),
+		bar("2024-06-14 11:00", 103),
+		bar("2024-06-14 11:30", 104),
+	}
+
+	tests := []struct {
+		name     string
+		candles  []twelvedata.TimeSeriesCandle
+		from, to twelvedata.TimeSeriesInterval
+		opts     twelvedata.ResampleOptions
+		want     []string
+	}{
+		{
+			name:    "weeks starting on Monday",
+			candles: daily,
+			from:    twelvedata.TimeSeriesInterval1Day,
+			to:      twelvedata.TimeSeriesInterval1Week,
+			want: []string{
+				"2024-05-27 00:00 100/103/99/102 300",
+				"2024-06-03 00:00 103/108/102/107 500",
+				"2024-06-10 00:00 108/111/107/110 300",
+			},
+		},
+		{
+			name:    "months",
+			candles: daily,
+			from:    twelvedata.TimeSeriesInterval1Day,
+			to:      twelvedata.TimeSeriesInterval1Month,
+			want: []string{
+				"2024-05-01 00:00 100/103/99/102 300",
+				"2024-06-01 00:00 103/111/102/110 800",
+			},
+		},
+		{
+			name:    "partial first and last weeks dropped",
+			candles: daily,
+			from:    twelvedata.TimeSeriesInterval1Day,
+			to:      twelvedata.TimeSeriesInterval1Week,
+			opts:    twelvedata.ResampleOptions{DropPartial: true},
+			want:    []string{"2024-06-03 00:00 103/108/102/107 500"},
+		},
+		{
+			name:    "descending order kept",
+			candles: reversed,
+			from:    twelvedata.TimeSeriesInterval1Day,
+			to:      twelvedata.TimeSeriesInterval1Month,
+			want: []string{
+				"2024-06-01 00:00 103/111/102/110 800",
+				"2024-05-01 00:00 100/103/99/102 300",
+			},
+		},
+		{
+			name:    "hours aligned to the session open",
+			candles: halfHours,
+			from:    twelvedata.TimeSeriesInterval30Min,
+			to:      twelvedata.TimeSeriesInterval1Hour,
+			opts:    regularSession,
+			want: []string{
+				"2024-06-14 09:30 100/102/99/101 200",
+				"2024-06-14 10:30 102/104/101/103 200",
+				"2024-06-14 11:30 104/105/103/104 100",
+			},
+		},
+		{
+			name:    "partial last hour dropped",
+			candles: halfHours,
+			from:    twelvedata.TimeSeriesInterval30Min,
+			to:      twelvedata.TimeSeriesInterval1Hour,
+			opts:    twelvedata.ResampleOptions{SessionOpen: regularSession.SessionOpen, SessionClose: regularSession.SessionClose, DropPartial: true},
+			want: []string{
+				"2024-06-14 09:30 100/102/99/101 200",
+				"2024-06-14 10:30 102/104/101/103 200",
+			},
+		},
+		{
+			name:    "hours aligned to midnight with extended hours",
+			candles: halfHours,
+			from:    twelvedata.TimeSeriesInterval30Min,
+			to:      twelvedata.TimeSeriesInterval1Hour,
+			want: []string{
+				"2024-06-14 09:00 99/101/98/100 200",
+				"2024-06-14 10:00 101/103/100/102 200",
+				"2024-06-14 11:00 103/105/102/104 200",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resampled, err := twelvedata.ResampleCandles(tt.candles, tt.from, tt.to, newYork, tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			expect(t, "candles", ohlcv(resampled), tt.want)
+			for _, candle := range resampled {
+				expect(t, "location", candle.DateTime.Location().String(), "America/New_York")
+			}
+		})
+	}
+}
+
+func TestResampleInExchangeTimezone(t *testing.T) {
+	// 22:00 in New York on the last day of May is already June in UTC
+	candle := bar("2024-05-31 22:00", 100)
+	candle.DateTime.Time = candle.DateTime.UTC()
+
+	resampled, err := series(twelvedata.TimeSeriesInterval1Hour, candle).Resample(twelvedata.TimeSeriesInterval1Month, twelvedata.ResampleOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect(t, "Meta.Interval", resampled.Meta.Interval, "1month")
+	expect(t, "candles", ohlcv(resampled.Candles), []string{"2024-05-01 00:00 100/101/99/100 100"})
+}
+
+func TestResampleInvalidIntervals(t *testing.T) {
+	tests := []struct {
+		from, to twelvedata.TimeSeriesInterval
+		err      string
+	}{
+		{twelvedata.TimeSeriesInterval1Hour, twelvedata.TimeSeriesInterval15Min, "shorter interval"},
+		{twelvedata.TimeSeriesInterval45Min, twelvedata.TimeSeriesInterval1Hour, "not a whole multiple"},
+		{twelvedata.TimeSeriesInterval1Week, twelvedata.TimeSeriesInterval1Month, "weeks span month boundaries"},
+		{twelvedata.TimeSeriesInterval1Month, twelvedata.TimeSeriesInterval1Week, "cannot resample"},
+		{twelvedata.TimeSeriesInterval("2min"), twelvedata.TimeSeriesInterval1Hour, "unsupported source interval"},
+	}
+
+	for _, tt := range tests {
+		_, err := twelvedata.ResampleCandles([]twelvedata.TimeSeriesCandle{bar("2024-06-14 09:30", 100)}, tt.from, tt.to, newYork, twelvedata.ResampleOptions{})
+		if err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("ResampleCandles(%s to %s) error = %v, want an error containing %q", tt.from, tt.to, err, tt.err)
+		}
+	}
+}
@@ -0,0 +1,198 @@
+package twelvedata_test
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jonnotjohn/twelvedata-go"
+	"github.com/jonnotjohn/twelvedata-go/tdtest"
+	"github.com/pkg/errors"
+)
+
+// recordingHooks records the retries and responses reported by a client
+type recordingHooks struct {
+	twelvedata.NopHooks
+
+	mu        sync.Mutex
+	retries   []twelvedata.ResponseEvent
+	responses []twelvedata.ResponseEvent
+}
+
+func (h *recordingHooks) OnRetry(_ context.Context, event twelvedata.ResponseEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.retries = append(h.retries, event)
+}
+
+func (h *recordingHooks) AfterResponse(_ context.Context, event twelvedata.ResponseEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.responses = append(h.responses, event)
+}
+
+// serverClient starts a fake server and creates a client for it retrying retryCount times in total
+func serverClient(t *testing.T, retryCount int) (*tdtest.Server, *twelvedata.APIClient, *recordingHooks) {
+	t.Helper()
+
+	server := tdtest.NewServer()
+	t.Cleanup(server.Close)
+
+	hooks := &recordingHooks{}
+	retryWaitTime := time.Nanosecond
+	client, err := twelvedata.NewAPIClient(twelvedata.Config{
+		APIKey:        "test",
+		APIUrl:        twelvedata.APIUrl(server.URL),
+		RetryCount:    &retryCount,
+		RetryWaitTime: &retryWaitTime,
+		Hooks:         hooks,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return server, client, hooks
+}
+
+func TestServerRetriesFaults(t *testing.T) {
+	tests := []struct {
+		name     string
+		fault    tdtest.Fault
+		requests int
+		retries  int
+		code     int // Code of the returned *APIError, 0 when the request succeeds
+	}{
+		{
+			name:     "server error recovered",
+			fault:    tdtest.Fault{Path: "/price", Status: http.StatusInternalServerError, Times: 2},
+			requests: 3,
+			retries:  2,
+		},
+		{
+			name:     "server error persisting",
+			fault:    tdtest.Fault{Path: "/price", Status: http.StatusServiceUnavailable, Times: -1},
+			requests: 3,
+			retries:  2,
+			code:     http.StatusServiceUnavailable,
+		},
+		{
+			name:     "error payload with status 200",
+			fault:    tdtest.Fault{Path: "/price", Code: http.StatusNotFound, Message: "**symbol** not found"},
+			requests: 1,
+			code:     http.StatusNotFound,
+		},
+		{
+			name:     "fault of another endpoint",
+			fault:    tdtest.Fault{Path: "/quote", Status: http.StatusInternalServerError},
+			requests: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, client, hooks := serverClient(t, 3)
+			server.Inject(tt.fault)
+
+			price, err := client.GetPrice(twelvedata.PriceRequest{Symbol: ptr("AAPL")})
+			if tt.code == 0 {
+				if err != nil {
+					t.Fatal(err)
+				}
+				if price.Price.Float64 <= 0 {
+					t.Errorf("Price = %v, want a positive price", price.Price.Float64)
+				}
+			} else {
+				var apiErr *twelvedata.APIError
+				if !errors.As(err, &apiErr) {
+					t.Fatalf("error = %v, want an *APIError", err)
+				}
+				expect(t, "APIError.Code", apiErr.Code, tt.code)
+			}
+
+			expect(t, "requests", len(server.Requests()), tt.requests)
+			expect(t, "retries", len(hooks.retries), tt.retries)
+			for i, retry := range hooks.retries {
+				expect(t, "retry attempt", retry.Attempt, i+1)
+				expect(t, "retry status", retry.StatusCode, tt.fault.Status)
+			}
+
+			expect(t, "responses", len(hooks.responses), 1)
+			expect(t, "response attempt", hooks.responses[0].Attempt, tt.requests)
+		})
+	}
+}
+
+func TestServerFaultDelay(t *testing.T) {
+	server, client, hooks := serverClient(t, 1)
+	server.Inject(tdtest.Fault{Path: "/time_series", Delay: 20 * time.Millisecond})
+
+	_, err := client.GetTimeSeries(twelvedata.TimeSeriesRequest{Symbol: ptr("AAPL"), Interval: ptr(twelvedata.TimeSeriesInterval1Day)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if duration := hooks.responses[0].Duration; duration < 20*time.Millisecond {
+		t.Errorf("Duration = %s, want at least the delay of the fault", duration)
+	}
+}
+
+func TestServerCreditHeaders(t *testing.T) {
+	server, client, hooks := serverClient(t, 1)
+	server.SetCreditsPerMinute(3)
+
+	now := time.Date(2024, 6, 14, 15, 0, 10, 0, time.UTC)
+	server.SetNow(func() time.Time { return now })
+
+	for range 3 {
+		if _, err := client.GetPrice(twelvedata.PriceRequest{Symbol: ptr("AAPL")}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	_, err := client.GetPrice(twelvedata.PriceRequest{Symbol: ptr("AAPL")})
+	var apiErr *twelvedata.APIError
+	if !errors.As(err, &apiErr) || apiErr.Code != http.StatusTooManyRequests {
+		t.Fatalf("error over budget = %v, want an *APIError with code 429", err)
+	}
+
+	// The budget is renewed every minute
+	now = now.Add(time.Minute)
+	if _, err := client.GetPrice(twelvedata.PriceRequest{Symbol: ptr("AAPL")}); err != nil {
+		t.Fatal(err)
+	}
+
+	var used, left, statuses []int
+	for _, response := range hooks.responses {
+		used = append(used, response.CreditsUsed)
+		left = append(left, response.CreditsLeft)
+		statuses = append(statuses, response.StatusCode)
+	}
+
+	expect(t, "credits used", used, []int{1, 1, 1, 1, 1})
+	expect(t, "credits left", left, []int{2, 1, 0, 0, 2})
+	expect(t, "statuses", statuses, []int{200, 200, 200, 429, 200})
+}
+
+func TestServerAPIKey(t *testing.T) {
+	server, client, _ := serverClient(t, 1)
+	server.SetAPIKey("secret")
+
+	_, err := client.GetQuote(twelvedata.QuoteRequest{Symbol: ptr("AAPL")})
+	var apiErr *twelvedata.APIError
+	if !errors.As(err, &apiErr) || apiErr.Code != http.StatusUnauthorized {
+		t.Fatalf("error = %v, want an *APIError with code 401", err)
+	}
+
+	client, err = server.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	quote, err := client.GetQuote(twelvedata.QuoteRequest{Symbol: ptr("AAPL")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect(t, "Symbol", quote.Symbol, "AAPL")
+}
@@ -1,6 +1,8 @@
 package twelvedata
 
 import (
+	"bytes"
+
 	jsoniter "github.com/json-iterator/go"
 	"github.com/pkg/errors"
 )
@@ -9,6 +11,39 @@ const (
 	urlEndpointStocks = "/stocks"
 )
 
+// StocksRequest is the available parameters for a stocks list request. All fields are optional.
+type StocksRequest struct {
+	Symbol    *string         // Symbol of the stock (e.g. "AAPL")
+	FIGI      *string         // Financial Instrument Global Identifier
+	ISIN      *string         // International Securities Identification Number
+	CUSIP     *string         // Committee on Uniform Securities Identification Procedures
+	Exchange  *string         // Exchange code (e.g. "NASDAQ")
+	MicCode   *string         // Market Identifier Code (e.g. "XNAS" for NASDAQ)
+	Country   *string         // Country code (e.g. "US" or "United States")
+	Type      *string         // Type of the stock (e.g. "Common Stock", "ETF")
+	Format    *ResponseFormat // Response format ("JSON" or "CSV"). CSV responses are smaller for the full list
+	Delimiter *string         // Delimiter of CSV responses (default is ";")
+}
+
+func (req StocksRequest) ToParams() (map[string]string, error) {
+	params := make(map[string]string)
+
+	AddStringParam(params, "symbol", req.Symbol)
+	AddStringParam(params, "figi", req.FIGI)
+	AddStringParam(params, "isin", req.ISIN)
+	AddStringParam(params, "cusip", req.CUSIP)
+	AddStringParam(params, "exchange", req.Exchange)
+	AddStringParam(params, "mic_code", req.MicCode)
+	AddStringParam(params, "country", req.Country)
+	AddStringParam(params, "type", req.Type)
+
+	if err := addFormatParams(params, req.Format, req.Delimiter); err != nil {
+		return nil, err
+	}
+
+	return params, nil
+}
+
 type Stocks struct {
 	Symbol   string `json:"symbol"`    // Stock symbol (e.g. "AAPL")
 	Name     string `json:"name"`      // Full name of the stock (e.g. "Apple Inc.")
@@ -29,15 +64,39 @@ type StocksResponse struct {
 	Status string   `json:"status"`
 }
 
+// GetStocks returns the list of every stock
 func (c *APIClient) GetStocks() (stocksResponse *StocksResponse, err error) {
-	data, err := c.Client.Get(urlEndpointStocks, nil)
+	return c.GetStocksRequest(StocksRequest{})
+}
+
+// GetStocksRequest returns the list of stocks, filtered by req
+func (c *APIClient) GetStocksRequest(req StocksRequest) (stocksResponse *StocksResponse, err error) {
+	params, err := req.ToParams()
+	if err != nil {
+		return nil, errors.Wrap(err, "Error converting StocksRequest to params")
+	}
+
+	data, err := c.Client.Get(urlEndpointStocks, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "Error fetching stocks data")
 	}
 
-	err = jsoniter.Unmarshal(data.Body(), &stocksResponse)
+	if isCSV(req.Format) {
+		var stocks []Stocks
+		err = c.Client.decode(data, "CSV stocks", func(body []byte) (err error) {
+			stocks, err = NewStocksCSVDecoder(bytes.NewReader(body), csvDelimiter(req.Delimiter)).DecodeAll()
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		return &StocksResponse{Data: stocks, Count: len(stocks), Status: "ok"}, nil
+	}
+
+	err = c.Client.unmarshal(jsoniter.ConfigDefault, data, &stocksResponse, "stocks")
 	if err != nil {
-		return nil, errors.Wrap(err, "Error unmarshalling stocks response")
+		return nil, err
 	}
 
 	return stocksResponse, nil
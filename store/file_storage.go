@@ -0,0 +1,77 @@
+package store
+
+import (
+	"context"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"github.com/jonnotjohn/twelvedata-go"
+	"github.com/pkg/errors"
+)
+
+// FileStorage stores each series as an NDJSON file (see twelvedata.WriteCandlesNDJSON) in a directory
+type FileStorage struct {
+	dir string
+}
+
+// NewFileStorage creates a FileStorage in dir, creating the directory if needed
+func NewFileStorage(dir string) (*FileStorage, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, errors.Wrap(err, "Error creating storage directory")
+	}
+
+	return &FileStorage{dir: dir}, nil
+}
+
+func (f *FileStorage) path(key Key) string {
+	name := url.PathEscape(key.Symbol) + "_" + string(key.Interval)
+	if key.Adjust != "" {
+		name += "_" + url.PathEscape(key.Adjust)
+	}
+
+	return filepath.Join(f.dir, name+".ndjson")
+}
+
+func (f *FileStorage) Read(_ context.Context, key Key) (*twelvedata.TimeSeriesResponse, error) {
+	file, err := os.Open(f.path(key))
+	if os.IsNotExist(err) {
+		return nil, ErrNotFound
+	}
+
+	if err != nil {
+		return nil, errors.Wrap(err, "Error opening stored series")
+	}
+	defer file.Close()
+
+	series, err := twelvedata.ReadTimeSeriesNDJSON(file)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error reading stored series %s", file.Name())
+	}
+
+	return series, nil
+}
+
+// Write replaces the stored series atomically, so a failed write never leaves a truncated file behind
+func (f *FileStorage) Write(_ context.Context, key Key, series *twelvedata.TimeSeriesResponse) error {
+	tmp, err := os.CreateTemp(f.dir, ".tmp-*")
+	if err != nil {
+		return errors.Wrap(err, "Error creating temporary file")
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := series.WriteNDJSON(tmp); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return errors.Wrap(err, "Error closing temporary file")
+	}
+
+	if err := os.Rename(tmp.Name(), f.path(key)); err != nil {
+		return errors.Wrap(err, "Error replacing stored series")
+	}
+
+	return nil
+}
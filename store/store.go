@@ -0,0 +1,168 @@
+// Package store keeps a local copy of time series and keeps it up to date incrementally, so overlapping history is
+// only downloaded once.
+package store
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/jonnotjohn/twelvedata-go"
+	"github.com/pkg/errors"
+)
+
+// maxOutputSize is the largest number of candles the API returns per request
+const maxOutputSize = 5000
+
+// ErrNotFound is returned by Storage.Read when nothing is stored for a key
+var ErrNotFound = errors.New("series not found in storage")
+
+// Key identifies a stored series
+type Key struct {
+	Symbol   string
+	Interval twelvedata.TimeSeriesInterval
+	Adjust   string // Adjusting mode of the prices ("none", "dividends", "splits", "all"). Empty is the API default
+}
+
+// Storage persists series. Implementations must be safe for concurrent use with different keys.
+type Storage interface {
+	// Read returns the stored series in ascending order, or ErrNotFound
+	Read(ctx context.Context, key Key) (*twelvedata.TimeSeriesResponse, error)
+	// Write replaces the stored series
+	Write(ctx context.Context, key Key, series *twelvedata.TimeSeriesResponse) error
+}
+
+// Store synchronises series from the API into a Storage
+type Store struct {
+	client  twelvedata.Client
+	storage Storage
+
+	locksMu sync.Mutex
+	locks   map[Key]*sync.Mutex
+}
+
+func New(client twelvedata.Client, storage Storage) *Store {
+	return &Store{client: client, storage: storage, locks: make(map[Key]*sync.Mutex)}
+}
+
+func (s *Store) lock(key Key) func() {
+	s.locksMu.Lock()
+	lock, ok := s.locks[key]
+	if !ok {
+		lock = &sync.Mutex{}
+		s.locks[key] = lock
+	}
+	s.locksMu.Unlock()
+
+	lock.Lock()
+	return lock.Unlock
+}
+
+// Load returns the stored series without contacting the API. The key of a series synced with SyncRequest includes its
+// adjusting mode.
+func (s *Store) Load(ctx context.Context, key Key) (*twelvedata.TimeSeriesResponse, error) {
+	return s.storage.Read(ctx, key)
+}
+
+// Sync fetches the candles newer than the last stored one and returns the updated series, see SyncRequest
+func (s *Store) Sync(ctx context.Context, symbol string, interval twelvedata.TimeSeriesInterval) (*twelvedata.TimeSeriesResponse, error) {
+	return s.SyncRequest(ctx, twelvedata.TimeSeriesRequest{Symbol: &symbol, Interval: &interval})
+}
+
+// SyncRequest brings the stored series for req's symbol, interval and adjusting mode up to date. Fetching starts at
+// the last stored candle, so a bar that was still forming at the previous sync is replaced by its final values.
+// Without stored candles, req.StartDate (or req.FromEarliest) decides where the series starts, otherwise the API
+// default applies. Order, OutputSize and EndDate of req are managed by the store.
+func (s *Store) SyncRequest(ctx context.Context, req twelvedata.TimeSeriesRequest) (*twelvedata.TimeSeriesResponse, error) {
+	if req.Symbol == nil || req.Interval == nil {
+		return nil, errors.New("symbol and interval are required")
+	}
+
+	key := Key{Symbol: *req.Symbol, Interval: *req.Interval}
+	if req.Adjust != nil {
+		key.Adjust = *req.Adjust
+	}
+
+	defer s.lock(key)()
+
+	stored, err := s.storage.Read(ctx, key)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return nil, errors.Wrap(err, "Error reading stored series")
+	}
+
+	if stored != nil && len(stored.Candles) > 0 {
+		last := stored.Candles[len(stored.Candles)-1].DateTime.Time
+		req.StartDate = &last
+		req.FromEarliest = nil
+		req.Date = nil
+	}
+
+	order := "asc"
+	outputSize := maxOutputSize
+	req.Order = &order
+	req.OutputSize = &outputSize
+	req.EndDate = nil
+
+	var fetched []twelvedata.TimeSeriesCandle
+	var meta *twelvedata.TimeSeriesResponseMeta
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		page, err := s.client.GetTimeSeriesContext(ctx, req)
+		if err != nil {
+			return nil, errors.Wrap(err, "Error fetching new candles")
+		}
+
+		meta = &page.Meta
+		fetched = append(fetched, page.Candles...)
+
+		// A full page means there may be more candles after it
+		if len(page.Candles) < maxOutputSize {
+			break
+		}
+
+		next := page.Candles[len(page.Candles)-1].DateTime.Time
+		if req.StartDate != nil && !next.After(*req.StartDate) {
+			break
+		}
+		req.StartDate = &next
+		req.FromEarliest = nil
+	}
+
+	merged := &twelvedata.TimeSeriesResponse{Meta: *meta}
+	if stored != nil {
+		merged.Candles = stored.Candles
+	}
+	merged.Candles = Merge(merged.Candles, fetched)
+
+	if err := s.storage.Write(ctx, key, merged); err != nil {
+		return nil, errors.Wrap(err, "Error writing series to storage")
+	}
+
+	return merged, nil
+}
+
+// Merge combines two series into one ascending series without duplicate timestamps. Candles of newer replace candles
+// of older with the same timestamp.
+func Merge(older, newer []twelvedata.TimeSeriesCandle) []twelvedata.TimeSeriesCandle {
+	byTime := make(map[int64]twelvedata.TimeSeriesCandle, len(older)+len(newer))
+	for _, candle := range older {
+		byTime[candle.DateTime.UnixNano()] = candle
+	}
+	for _, candle := range newer {
+		byTime[candle.DateTime.UnixNano()] = candle
+	}
+
+	merged := make([]twelvedata.TimeSeriesCandle, 0, len(byTime))
+	for _, candle := range byTime {
+		merged = append(merged, candle)
+	}
+
+	sort.Slice(merged, func(i, j int) bool {
+		return merged[i].DateTime.Before(merged[j].DateTime.Time)
+	})
+
+	return merged
+}
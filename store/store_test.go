@@ -0,0 +1,198 @@
+package store_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jonnotjohn/twelvedata-go"
+	"github.com/jonnotjohn/twelvedata-go/store"
+	"github.com/jonnotjohn/twelvedata-go/tdtest"
+	"github.com/pkg/errors"
+)
+
+// newStore creates a store syncing from a fake server whose clock is *now, into a temporary directory
+func newStore(t *testing.T, now *time.Time) (*store.Store, *tdtest.Server) {
+	t.Helper()
+
+	server := tdtest.NewServer()
+	t.Cleanup(server.Close)
+	server.SetNow(func() time.Time { return *now })
+
+	client, err := server.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	storage, err := store.NewFileStorage(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return store.New(client, storage), server
+}
+
+// checkContiguous checks that candles are ascending one minute apart, from first to last
+func checkContiguous(t *testing.T, candles []twelvedata.TimeSeriesCandle, first, last time.Time) {
+	t.Helper()
+
+	if len(candles) == 0 {
+		t.Fatal("no candles")
+	}
+
+	for i := 1; i < len(candles); i++ {
+		if step := candles[i].DateTime.Sub(candles[i-1].DateTime.Time); step != time.Minute {
+			t.Fatalf("candle %d at %s follows %s after %s, want 1m0s", i, candles[i].DateTime, candles[i-1].DateTime, step)
+		}
+	}
+
+	if !candles[0].DateTime.Equal(first) {
+		t.Errorf("first candle at %s, want %s", candles[0].DateTime, first)
+	}
+	if got := candles[len(candles)-1].DateTime; !got.Equal(last) {
+		t.Errorf("last candle at %s, want %s", got, last)
+	}
+}
+
+func TestSyncPaginates(t *testing.T) {
+	now := time.Date(2024, 6, 14, 12, 0, 30, 0, time.UTC)
+	s, server := newStore(t, &now)
+
+	symbol := "BTC/USD"
+	interval := twelvedata.TimeSeriesInterval1Min
+	start := now.Add(-5 * 24 * time.Hour).Truncate(time.Minute)
+
+	// Five days of minutes take two pages of 5000 candles
+	series, err := s.SyncRequest(context.Background(), twelvedata.TimeSeriesRequest{Symbol: &symbol, Interval: &interval, StartDate: &start})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	checkContiguous(t, series.Candles, start, now.Truncate(time.Minute))
+	if len(series.Candles) != 5*24*60+1 {
+		t.Errorf("candles = %d, want %d", len(series.Candles), 5*24*60+1)
+	}
+
+	requests := server.Requests()
+	if len(requests) != 2 {
+		t.Fatalf("requests = %d, want 2 pages", len(requests))
+	}
+
+	// The second page starts at the last candle of the first one
+	pageEnd := start.Add(4999 * time.Minute).Format("2006-01-02 15:04:05")
+	if got := requests[1].Params["start_date"]; got != pageEnd {
+		t.Errorf("start_date of the second page = %s, want %s", got, pageEnd)
+	}
+	for _, request := range requests {
+		if request.Params["order"] != "asc" || request.Params["outputsize"] != "5000" {
+			t.Errorf("page params = %v, want ascending pages of 5000 candles", request.Params)
+		}
+	}
+
+	// A later sync only fetches the new candles, starting again at the last stored one
+	now = now.Add(30 * time.Minute)
+	synced, err := s.Sync(context.Background(), symbol, interval)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	checkContiguous(t, synced.Candles, start, now.Truncate(time.Minute))
+
+	requests = server.Requests()
+	if len(requests) != 3 {
+		t.Fatalf("requests = %d, want a single request for the new candles", len(requests))
+	}
+	if got, want := requests[2].Params["start_date"], series.Candles[len(series.Candles)-1].DateTime.Format("2006-01-02 15:04:05"); got != want {
+		t.Errorf("start_date of the incremental sync = %s, want %s", got, want)
+	}
+
+	loaded, err := s.Load(context.Background(), store.Key{Symbol: symbol, Interval: interval})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded.Candles) != len(synced.Candles) {
+		t.Errorf("loaded candles = %d, want %d", len(loaded.Candles), len(synced.Candles))
+	}
+}
+
+func TestSyncFaultKeepsStoredSeries(t *testing.T) {
+	now := time.Date(2024, 6, 14, 12, 0, 30, 0, time.UTC)
+	s, server := newStore(t, &now)
+
+	symbol := "BTC/USD"
+	interval := twelvedata.TimeSeriesInterval1Min
+	start := now.Add(-4 * 24 * time.Hour).Truncate(time.Minute)
+
+	// The first page is only delayed, the second one fails
+	server.Inject(tdtest.Fault{Path: "/time_series", Delay: time.Millisecond})
+	server.Inject(tdtest.Fault{Path: "/time_series", Code: 429, Message: "You have run out of API credits for the current minute."})
+
+	_, err := s.SyncRequest(context.Background(), twelvedata.TimeSeriesRequest{Symbol: &symbol, Interval: &interval, StartDate: &start})
+	var apiErr *twelvedata.APIError
+	if !errors.As(err, &apiErr) || apiErr.Code != 429 {
+		t.Fatalf("SyncRequest() error = %v, want an *APIError with code 429", err)
+	}
+	if requests := len(server.Requests()); requests != 2 {
+		t.Errorf("requests = %d, want the failure on the second page", requests)
+	}
+
+	if _, err := s.Load(context.Background(), store.Key{Symbol: symbol, Interval: interval}); !errors.Is(err, store.ErrNotFound) {
+		t.Errorf("Load() error = %v, want ErrNotFound after a failed sync", err)
+	}
+
+	// Once the faults are consumed, the sync fetches both pages again
+	series, err := s.SyncRequest(context.Background(), twelvedata.TimeSeriesRequest{Symbol: &symbol, Interval: &interval, StartDate: &start})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkContiguous(t, series.Candles, start, now.Truncate(time.Minute))
+}
+
+func TestSyncContextCancelsRequest(t *testing.T) {
+	now := time.Date(2024, 6, 14, 12, 0, 30, 0, time.UTC)
+	s, server := newStore(t, &now)
+
+	symbol := "BTC/USD"
+	interval := twelvedata.TimeSeriesInterval1Min
+	server.Inject(tdtest.Fault{Path: "/time_series", Delay: time.Minute})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	// The deadline stops the request in flight instead of the next page
+	start := time.Now()
+	_, err := s.Sync(ctx, symbol, interval)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Sync() error = %v, want the deadline of the context", err)
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("Sync() took %s, want it to stop at the deadline", elapsed)
+	}
+}
+
+func TestLoadAdjustedSeries(t *testing.T) {
+	now := time.Date(2024, 6, 14, 12, 0, 30, 0, time.UTC)
+	s, _ := newStore(t, &now)
+
+	symbol := "AAPL"
+	interval := twelvedata.TimeSeriesInterval1Day
+	adjust := "splits"
+
+	synced, err := s.SyncRequest(context.Background(), twelvedata.TimeSeriesRequest{Symbol: &symbol, Interval: &interval, Adjust: &adjust})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := s.Load(context.Background(), store.Key{Symbol: symbol, Interval: interval, Adjust: adjust})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded.Candles) != len(synced.Candles) {
+		t.Errorf("loaded candles = %d, want %d", len(loaded.Candles), len(synced.Candles))
+	}
+
+	// Series of other adjusting modes are stored apart
+	if _, err := s.Load(context.Background(), store.Key{Symbol: symbol, Interval: interval}); !errors.Is(err, store.ErrNotFound) {
+		t.Errorf("Load() of the unadjusted series error = %v, want ErrNotFound", err)
+	}
+}
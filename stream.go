@@ -0,0 +1,455 @@
+package twelvedata
+
+import (
+	"context"
+	"io"
+	"iter"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+)
+
+// streamBufferSize is the read buffer of the streaming JSON decoders, which bounds their memory use together with
+// the size of a single list element
+const streamBufferSize = 4096
+
+// errValuesBeforeMeta is returned when a time series has to be decoded in the exchange timezone, but its values come
+// before the meta block naming it
+var errValuesBeforeMeta = errors.New("time series values precede the meta block")
+
+// jsonArrayStream walks the fields of a top-level JSON object and stops at each element of the array under key, so
+// the elements can be decoded one at a time. Other fields are passed to onField, or skipped when it returns false.
+type jsonArrayStream struct {
+	iter    *jsoniter.Iterator
+	key     string
+	onField func(field string) bool
+
+	inArray bool // The array under key was reached
+	done    bool // The end of the object was reached
+
+	status  string // Fields of error responses
+	code    int
+	message string
+}
+
+func newJSONArrayStream(it *jsoniter.Iterator, key string, onField func(field string) bool) *jsonArrayStream {
+	return &jsonArrayStream{iter: it, key: key, onField: onField}
+}
+
+// err returns the error of the iterator. The end of the input before the end of the object is unexpected.
+func (s *jsonArrayStream) err() error {
+	if s.iter.Error == nil {
+		return nil
+	}
+
+	if s.iter.Error == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+
+	return errors.Wrap(s.iter.Error, "invalid JSON")
+}
+
+// end returns io.EOF, or the error of an error response
+func (s *jsonArrayStream) end() error {
+	if s.status == "error" {
+		return &APIError{Code: s.code, Message: s.message}
+	}
+
+	return io.EOF
+}
+
+// wrapElementError adds the index of the list element to decoding errors. Errors of the response itself are returned
+// unchanged.
+func wrapElementError(err error, format string, index int) error {
+	if _, ok := err.(*APIError); ok {
+		return err
+	}
+
+	return errors.Wrapf(err, format, index)
+}
+
+// enterArray reads fields until the array under key is reached, or returns end() at the end of the object
+func (s *jsonArrayStream) enterArray() error {
+	for !s.inArray {
+		if s.done {
+			return s.end()
+		}
+
+		field := s.iter.ReadObject()
+		if err := s.err(); err != nil {
+			return err
+		}
+
+		switch field {
+		case "":
+			s.done = true
+		case s.key:
+			s.inArray = true
+		case "status":
+			s.status = s.iter.ReadString()
+		case "code":
+			s.code = s.iter.ReadInt()
+		case "message":
+			s.message = s.iter.ReadString()
+		default:
+			if s.onField == nil || !s.onField(field) {
+				s.iter.Skip()
+			}
+		}
+
+		if err := s.err(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// next positions the iterator on the next array element, or returns end() after the last one
+func (s *jsonArrayStream) next() error {
+	for {
+		if err := s.enterArray(); err != nil {
+			return err
+		}
+
+		if s.iter.ReadArray() {
+			return s.err()
+		}
+
+		if err := s.err(); err != nil {
+			return err
+		}
+
+		// The fields after the array may still report an error
+		s.inArray = false
+		s.key = ""
+	}
+}
+
+// StocksJSONDecoder decodes a JSON stocks list one stock at a time, without holding the response in memory
+type StocksJSONDecoder struct {
+	stream *jsonArrayStream
+}
+
+func NewStocksJSONDecoder(r io.Reader) *StocksJSONDecoder {
+	it := jsoniter.Parse(jsoniter.ConfigDefault, r, streamBufferSize)
+	return &StocksJSONDecoder{stream: newJSONArrayStream(it, "data", nil)}
+}
+
+// Next returns the next stock, or io.EOF when there are no more
+func (d *StocksJSONDecoder) Next() (stock Stocks, err error) {
+	if err := d.stream.next(); err != nil {
+		return Stocks{}, err
+	}
+
+	d.stream.iter.ReadVal(&stock)
+	if err := d.stream.err(); err != nil {
+		return Stocks{}, err
+	}
+
+	return stock, nil
+}
+
+// TimeSeriesJSONDecoder decodes the candles of a JSON time series response one at a time, without holding the
+// response in memory
+type TimeSeriesJSONDecoder struct {
+	stream   *jsonArrayStream
+	meta     TimeSeriesResponseMeta
+	metaSeen bool
+	loc      *time.Location
+}
+
+// NewTimeSeriesJSONDecoder creates a decoder reading from r. Datetimes are parsed in loc, or in the exchange timezone
+// of the meta block when loc is nil.
+func NewTimeSeriesJSONDecoder(r io.Reader, loc *time.Location) *TimeSeriesJSONDecoder {
+	return newTimeSeriesJSONDecoder(jsoniter.Parse(jsoniter.ConfigDefault, r, streamBufferSize), loc)
+}
+
+func newTimeSeriesJSONDecoder(it *jsoniter.Iterator, loc *time.Location) *TimeSeriesJSONDecoder {
+	d := &TimeSeriesJSONDecoder{loc: loc}
+	d.stream = newJSONArrayStream(it, "values", func(field string) bool {
+		if field != "meta" {
+			return false
+		}
+
+		it.ReadVal(&d.meta)
+		d.metaSeen = true
+		return true
+	})
+
+	return d
+}
+
+// Meta reads up to the candles and returns the meta block. Responses without candles are read to the end.
+func (d *TimeSeriesJSONDecoder) Meta() (TimeSeriesResponseMeta, error) {
+	if err := d.stream.enterArray(); err != nil && err != io.EOF {
+		return TimeSeriesResponseMeta{}, err
+	}
+
+	return d.meta, nil
+}
+
+// Next returns the next candle, or io.EOF when there are no more
+func (d *TimeSeriesJSONDecoder) Next() (candle TimeSeriesCandle, err error) {
+	if err := d.stream.next(); err != nil {
+		return TimeSeriesCandle{}, err
+	}
+
+	if d.loc == nil {
+		if !d.metaSeen {
+			return TimeSeriesCandle{}, errValuesBeforeMeta
+		}
+
+		if d.loc, err = resolveLocation(nil, d.meta.ExchangeTimezone); err != nil {
+			return TimeSeriesCandle{}, err
+		}
+	}
+
+	var temp struct {
+		DateTime string  `json:"datetime"`
+		Open     TDFloat `json:"open"`
+		Close    TDFloat `json:"close"`
+		High     TDFloat `json:"high"`
+		Low      TDFloat `json:"low"`
+		Volume   TDFloat `json:"volume"`
+	}
+
+	d.stream.iter.ReadVal(&temp)
+	if err := d.stream.err(); err != nil {
+		return TimeSeriesCandle{}, err
+	}
+
+	parsedTime, err := parseInLocation(temp.DateTime, d.loc)
+	if err != nil {
+		// Candles written by TimeSeriesCandle.MarshalJSON carry their offset
+		offsetTime, offsetErr := time.Parse(time.RFC3339Nano, temp.DateTime)
+		if offsetErr != nil {
+			return TimeSeriesCandle{}, err
+		}
+
+		parsedTime = offsetTime.In(d.loc)
+	}
+
+	return TimeSeriesCandle{
+		DateTime: TDZonedTime{Time: parsedTime},
+		Open:     temp.Open,
+		Close:    temp.Close,
+		High:     temp.High,
+		Low:      temp.Low,
+		Volume:   temp.Volume,
+	}, nil
+}
+
+// seqOf adapts the Next method of a decoder to an iterator. io.EOF ends the iteration, other errors are yielded once.
+func seqOf[T any](next func() (T, error)) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for {
+			value, err := next()
+			if err == io.EOF {
+				return
+			}
+
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+
+			if !yield(value, nil) {
+				return
+			}
+		}
+	}
+}
+
+// StreamStocks returns the list of stocks like GetStocksRequest, decoding one stock at a time while iterating instead
+// of holding the whole list in memory. The request is sent when the iteration starts.
+func (c *APIClient) StreamStocks(req StocksRequest) iter.Seq2[Stocks, error] {
+	return func(yield func(Stocks, error) bool) {
+		params, err := req.ToParams()
+		if err != nil {
+			yield(Stocks{}, errors.Wrap(err, "Error converting StocksRequest to params"))
+			return
+		}
+
+		body, err := c.Client.GetStream(urlEndpointStocks, params)
+		if err != nil {
+			yield(Stocks{}, errors.Wrap(err, "Error fetching stocks data"))
+			return
+		}
+		defer body.Close()
+
+		next := NewStocksJSONDecoder(body).Next
+		if isCSV(req.Format) {
+			next = NewStocksCSVDecoder(body, csvDelimiter(req.Delimiter)).Next
+		}
+
+		count := 0
+		for stock, err := range seqOf(next) {
+			if err != nil {
+				c.Client.streamDecodeFailed(body, err)
+				yield(Stocks{}, errors.Wrap(wrapElementError(err, "error decoding stock %d", count), "Error decoding stocks response"))
+				return
+			}
+
+			count++
+			if !yield(stock, nil) {
+				return
+			}
+		}
+	}
+}
+
+// TimeSeriesStream is a time series response whose candles are decoded while iterating. Close it when the candles
+// are not iterated to the end.
+type TimeSeriesStream struct {
+	Meta TimeSeriesResponseMeta
+
+	next         func() (TimeSeriesCandle, error)
+	body         io.Closer
+	decodeFailed func(body io.Closer, err error) // Reports decoding errors to the client hooks, nil for other streams
+}
+
+// NewTimeSeriesStream creates a stream over candles, e.g. to return from a mock client
+func NewTimeSeriesStream(meta TimeSeriesResponseMeta, candles iter.Seq2[TimeSeriesCandle, error]) *TimeSeriesStream {
+	next, stop := iter.Pull2(candles)
+	return &TimeSeriesStream{
+		Meta: meta,
+		next: func() (TimeSeriesCandle, error) {
+			candle, err, ok := next()
+			if !ok {
+				return TimeSeriesCandle{}, io.EOF
+			}
+
+			return candle, err
+		},
+		body: closerFunc(func() error {
+			stop()
+			return nil
+		}),
+	}
+}
+
+// closerFunc adapts a function to an io.Closer
+type closerFunc func() error
+
+func (f closerFunc) Close() error {
+	return f()
+}
+
+// Candles returns an iterator over the candles of the stream. The stream is closed when the iteration ends.
+func (s *TimeSeriesStream) Candles() iter.Seq2[TimeSeriesCandle, error] {
+	return func(yield func(TimeSeriesCandle, error) bool) {
+		defer s.Close()
+
+		count := 0
+		for candle, err := range seqOf(s.next) {
+			if err != nil {
+				if s.decodeFailed != nil {
+					s.decodeFailed(s.body, err)
+				}
+
+				yield(TimeSeriesCandle{}, wrapElementError(err, "error decoding candle %d", count))
+				return
+			}
+
+			count++
+			if !yield(candle, nil) {
+				return
+			}
+		}
+	}
+}
+
+// Close releases the response body
+func (s *TimeSeriesStream) Close() error {
+	return s.body.Close()
+}
+
+// StreamTimeSeries sends a time series request like GetTimeSeries and reads up to the first candle. The candles are
+// decoded one at a time while iterating TimeSeriesStream.Candles, so memory use doesn't grow with OutputSize.
+func (c *APIClient) StreamTimeSeries(req TimeSeriesRequest) (*TimeSeriesStream, error) {
+	params, loc, err := c.timeSeriesParams(context.Background(), &req)
+	if err != nil {
+		return nil, err
+	}
+
+	var csvMeta TimeSeriesResponseMeta
+	if isCSV(req.Format) {
+		if csvMeta, err = c.csvTimeSeriesMeta(context.Background(), req); err != nil {
+			return nil, err
+		}
+	}
+
+	body, err := c.Client.GetStream(urlEndpointTimeSeries, params)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error fetching time series data")
+	}
+
+	if isCSV(req.Format) {
+		decoder := NewTimeSeriesCSVDecoder(body, csvDelimiter(req.Delimiter), loc)
+		decoder.preserveDecimals = c.preservesDecimals(req.PreserveDecimals)
+
+		return &TimeSeriesStream{
+			Meta:         csvMeta,
+			next:         decoder.Next,
+			body:         body,
+			decodeFailed: c.Client.streamDecodeFailed,
+		}, nil
+	}
+
+	decoder := newTimeSeriesJSONDecoder(jsoniter.Parse(c.jsonAPI(req.PreserveDecimals), body, streamBufferSize), loc)
+	meta, err := decoder.Meta()
+	if err != nil {
+		c.Client.streamDecodeFailed(body, err)
+		body.Close()
+		return nil, errors.Wrap(err, "Error decoding time series response")
+	}
+
+	if req.Symbol != nil && meta.ExchangeTimezone != "" {
+		c.timezones.Store(timezoneCacheKey(req), meta.ExchangeTimezone)
+	}
+
+	return &TimeSeriesStream{Meta: meta, next: decoder.Next, body: body, decodeFailed: c.Client.streamDecodeFailed}, nil
+}
+
+// decodeTimeSeriesJSON decodes a whole JSON time series response with the streaming decoder, so the candles are
+// decoded in place instead of from copies of their raw JSON
+func decodeTimeSeriesJSON(api jsoniter.API, data []byte, loc *time.Location) (*TimeSeriesResponse, error) {
+	decoder := newTimeSeriesJSONDecoder(jsoniter.ParseBytes(api, data), loc)
+
+	resp := &TimeSeriesResponse{}
+	for {
+		candle, err := decoder.Next()
+		if err == io.EOF {
+			break
+		}
+
+		if err == errValuesBeforeMeta {
+			// Look up the meta block first and start over
+			var head struct {
+				Meta TimeSeriesResponseMeta `json:"meta"`
+			}
+
+			if err := api.Unmarshal(data, &head); err != nil {
+				return nil, errors.Wrap(err, "failed to unmarshal time series meta")
+			}
+
+			if loc, err = resolveLocation(nil, head.Meta.ExchangeTimezone); err != nil {
+				return nil, err
+			}
+
+			return decodeTimeSeriesJSON(api, data, loc)
+		}
+
+		if err != nil {
+			return nil, wrapElementError(err, "error unmarshaling value %d", len(resp.Candles))
+		}
+
+		resp.Candles = append(resp.Candles, candle)
+	}
+
+	resp.Meta = decoder.meta
+	return resp, nil
+}
@@ -0,0 +1,250 @@
+package twelvedata_test
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jonnotjohn/twelvedata-go"
+)
+
+// valuesBeforeMeta is a time series response whose values come before the meta block naming the exchange timezone
+const valuesBeforeMeta = `{"values":[` +
+	`{"datetime":"2024-06-14 15:59:00","open":"212.3","high":"212.5","low":"212.2","close":"212.49","volume":"100"},` +
+	`{"datetime":"2024-06-14 15:58:00","open":"212.1","high":"212.4","low":"212","close":"212.3","volume":null}],` +
+	`"meta":{"symbol":"AAPL","interval":"1min","exchange_timezone":"America/New_York"},"status":"ok"}`
+
+func TestStreamStocks(t *testing.T) {
+	apple := `{"symbol":"AAPL","name":"Apple Inc","currency":"USD","exchange":"NASDAQ","mic_code":"XNGS","country":"United States","type":"Common Stock"}`
+	microsoft := `{"symbol":"MSFT","name":"Microsoft Corp","currency":"USD","exchange":"NASDAQ","mic_code":"XNGS","country":"United States","type":"Common Stock"}`
+
+	tests := []struct {
+		name    string
+		body    string
+		symbols []string
+		code    int    // Code of the expected *APIError
+		err     string // Part of the expected error message
+	}{
+		{
+			name:    "list before the status",
+			body:    `{"data":[` + apple + `,` + microsoft + `],"count":2,"status":"ok"}`,
+			symbols: []string{"AAPL", "MSFT"},
+		},
+		{
+			name:    "list after other fields",
+			body:    `{"count":2,"extra":{"nested":[1,2]},"data":[` + apple + `,` + microsoft + `],"status":"ok"}`,
+			symbols: []string{"AAPL", "MSFT"},
+		},
+		{
+			name: "empty list",
+			body: `{"data":[],"count":0,"status":"ok"}`,
+		},
+		{
+			name: "error payload",
+			body: `{"code":401,"message":"**apikey** parameter is incorrect","status":"error"}`,
+			code: 401,
+			err:  "parameter is incorrect",
+		},
+		{
+			name:    "error after the list",
+			body:    `{"data":[` + apple + `],"status":"error","code":500,"message":"list truncated"}`,
+			symbols: []string{"AAPL"},
+			code:    500,
+			err:     "list truncated",
+		},
+		{
+			name:    "truncated element",
+			body:    `{"data":[` + apple + `,{"symbol":"MS`,
+			symbols: []string{"AAPL"},
+			err:     "error decoding stock 1: invalid JSON",
+		},
+		{
+			name:    "truncated list",
+			body:    `{"data":[` + apple + `]`,
+			symbols: []string{"AAPL"},
+			err:     "error decoding stock 1: invalid JSON",
+		},
+		{
+			name:    "invalid element",
+			body:    `{"data":[` + apple + `,{"symbol":1}],"status":"ok"}`,
+			symbols: []string{"AAPL"},
+			err:     "error decoding stock 1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := handlerClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(tt.body))
+			}))
+
+			var symbols []string
+			var err error
+			for stock, stockErr := range client.StreamStocks(twelvedata.StocksRequest{}) {
+				if stockErr != nil {
+					err = stockErr
+					break
+				}
+				symbols = append(symbols, stock.Symbol)
+			}
+
+			expect(t, "symbols", symbols, tt.symbols)
+			switch {
+			case tt.code != 0:
+				expectAPIError(t, err, tt.code, tt.err)
+			case tt.err != "":
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Errorf("error = %v, want an error containing %q", err, tt.err)
+				}
+			case err != nil:
+				t.Errorf("error = %v", err)
+			}
+		})
+	}
+}
+
+func TestStreamStocksStopsEarly(t *testing.T) {
+	client := handlerClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"data":[{"symbol":"AAPL"},{"symbol":"MSFT"},{"symbol":"IBM"}],"status":"ok"}`))
+	}))
+
+	var symbols []string
+	for stock, err := range client.StreamStocks(twelvedata.StocksRequest{}) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		symbols = append(symbols, stock.Symbol)
+		if len(symbols) == 2 {
+			break
+		}
+	}
+
+	expect(t, "symbols", symbols, []string{"AAPL", "MSFT"})
+}
+
+func TestTimeSeriesJSONDecoder(t *testing.T) {
+	tokyo := mustLoadLocation("Asia/Tokyo")
+
+	tests := []struct {
+		name      string
+		body      string
+		loc       *time.Location
+		meta      twelvedata.TimeSeriesResponseMeta
+		wallClock []string // "2006-01-02 15:04:05" wall clocks of the candles
+		location  string   // Location of the datetimes
+		err       string   // Part of the expected error message of Next
+	}{
+		{
+			name: "meta before values",
+			body: `{"meta":{"symbol":"AAPL","interval":"1min","exchange_timezone":"America/New_York"},` +
+				`"values":[{"datetime":"2024-06-14 15:59:00","open":"1","high":"1","low":"1","close":"1","volume":"1"}],"status":"ok"}`,
+			meta:      twelvedata.TimeSeriesResponseMeta{Symbol: "AAPL", Interval: "1min", ExchangeTimezone: "America/New_York"},
+			wallClock: []string{"2024-06-14 15:59:00"},
+			location:  "America/New_York",
+		},
+		{
+			name:      "values before meta in the request timezone",
+			body:      valuesBeforeMeta,
+			loc:       tokyo,
+			wallClock: []string{"2024-06-14 15:59:00", "2024-06-14 15:58:00"},
+			location:  "Asia/Tokyo",
+		},
+		{
+			name: "values before meta in the exchange timezone",
+			body: valuesBeforeMeta,
+			err:  "values precede the meta block",
+		},
+		{
+			name: "candles written with their offset",
+			body: `{"meta":{"symbol":"AAPL","interval":"1h","exchange_timezone":"America/New_York"},` +
+				`"values":[{"datetime":"2024-06-14T13:30:00Z","open":1,"high":1,"low":1,"close":1,"volume":null}]}`,
+			meta:      twelvedata.TimeSeriesResponseMeta{Symbol: "AAPL", Interval: "1h", ExchangeTimezone: "America/New_York"},
+			wallClock: []string{"2024-06-14 09:30:00"},
+			location:  "America/New_York",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decoder := twelvedata.NewTimeSeriesJSONDecoder(strings.NewReader(tt.body), tt.loc)
+			meta, err := decoder.Meta()
+			if err != nil {
+				t.Fatal(err)
+			}
+			expect(t, "Meta", meta, tt.meta)
+
+			var wallClocks []string
+			for {
+				candle, err := decoder.Next()
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					if tt.err == "" || !strings.Contains(err.Error(), tt.err) {
+						t.Fatalf("Next() error = %v, want an error containing %q", err, tt.err)
+					}
+					break
+				}
+
+				wallClocks = append(wallClocks, candle.DateTime.Format("2006-01-02 15:04:05"))
+				expect(t, "location", candle.DateTime.Location().String(), tt.location)
+			}
+
+			expect(t, "candles", wallClocks, tt.wallClock)
+		})
+	}
+}
+
+func TestTimeSeriesValuesBeforeMeta(t *testing.T) {
+	var requests int
+	client := handlerClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		_, _ = w.Write([]byte(valuesBeforeMeta))
+	}))
+	req := twelvedata.TimeSeriesRequest{Symbol: ptr("AAPL"), Interval: ptr(twelvedata.TimeSeriesInterval1Min)}
+
+	// The whole response is decoded again once the meta block names the exchange timezone
+	series, err := client.GetTimeSeries(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect(t, "requests", requests, 1)
+	expect(t, "Meta.ExchangeTimezone", series.Meta.ExchangeTimezone, "America/New_York")
+	expect(t, "candles", len(series.Candles), 2)
+	expect(t, "Candles[0].DateTime", wallClock(series.Candles[0].DateTime.Time), "2024-06-14 15:59:00")
+	expect(t, "Candles[1].Volume", series.Candles[1].Volume, twelvedata.TDFloat{})
+	for _, candle := range series.Candles {
+		expect(t, "location", candle.DateTime.Location().String(), "America/New_York")
+	}
+
+	// A stream can't go back to the values
+	stream, err := client.StreamTimeSeries(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, err := range stream.Candles() {
+		if err == nil || !strings.Contains(err.Error(), "values precede the meta block") {
+			t.Errorf("streamed candle error = %v, want the values to precede the meta block", err)
+		}
+	}
+
+	// In the request timezone the values can be streamed
+	req.TimeZone = ptr("America/New_York")
+	stream, err = client.StreamTimeSeries(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var streamed []string
+	for candle, err := range stream.Candles() {
+		if err != nil {
+			t.Fatal(err)
+		}
+		streamed = append(streamed, wallClock(candle.DateTime.Time))
+	}
+	expect(t, "streamed", streamed, []string{"2024-06-14 15:59:00", "2024-06-14 15:58:00"})
+}
@@ -0,0 +1,81 @@
+package twelvedata
+
+import (
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+)
+
+const (
+	urlEndpointSymbolSearch = "/symbol_search"
+)
+
+// SymbolSearchRequest is the available parameters for a symbol search request
+type SymbolSearchRequest struct {
+	Symbol     *string // Required: Symbol or name to search for (e.g. "AA", "Apple")
+	FIGI       *string // Financial Instrument Global Identifier
+	ISIN       *string // International Securities Identification Number
+	CUSIP      *string // Committee on Uniform Securities Identification Procedures
+	OutputSize *int    // Number of matches to return (default is 30, max is 120)
+	ShowPlan   *bool   // Include the plan needed to access each match (default is false)
+}
+
+func (req SymbolSearchRequest) ToParams() (map[string]string, error) {
+	params := make(map[string]string)
+
+	if req.Symbol == nil {
+		return nil, errors.New("symbol is required")
+	}
+
+	AddStringParam(params, "symbol", req.Symbol)
+	AddStringParam(params, "figi", req.FIGI)
+	AddStringParam(params, "isin", req.ISIN)
+	AddStringParam(params, "cusip", req.CUSIP)
+
+	AddIntParam(params, "outputsize", req.OutputSize)
+
+	AddBoolParam(params, "show_plan", req.ShowPlan)
+
+	return params, nil
+}
+
+type SymbolSearchAccess struct {
+	Global string `json:"global"` // Access level for the symbol (e.g. "Basic", "Pro")
+	Plan   string `json:"plan"`   // Cheapest plan giving access to the symbol (e.g. "Basic")
+}
+
+type SymbolSearchResult struct {
+	Symbol           string              `json:"symbol"`            // Symbol of the instrument (e.g. "AAPL")
+	InstrumentName   string              `json:"instrument_name"`   // Full name of the instrument (e.g. "Apple Inc")
+	Exchange         string              `json:"exchange"`          // Exchange code (e.g. "NASDAQ")
+	MicCode          string              `json:"mic_code"`          // Market Identifier Code (e.g. "XNGS")
+	ExchangeTimezone string              `json:"exchange_timezone"` // Timezone of the exchange (e.g. "America/New_York")
+	InstrumentType   string              `json:"instrument_type"`   // Type of the instrument (e.g. "Common Stock")
+	Country          string              `json:"country"`           // Country of the exchange (e.g. "United States")
+	Currency         string              `json:"currency"`          // Currency code (e.g. "USD")
+	Access           *SymbolSearchAccess `json:"access"`            // Only present when ShowPlan is set
+}
+
+type SymbolSearchResponse struct {
+	Data   []SymbolSearchResult `json:"data"` // Matches, best first
+	Status string               `json:"status"`
+}
+
+// GetSymbolSearch returns the instruments best matching a symbol or name
+func (c *APIClient) GetSymbolSearch(req SymbolSearchRequest) (symbolSearch *SymbolSearchResponse, err error) {
+	params, err := req.ToParams()
+	if err != nil {
+		return nil, errors.Wrap(err, "Error converting SymbolSearchRequest to params")
+	}
+
+	data, err := c.Client.Get(urlEndpointSymbolSearch, params)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error fetching symbol search data")
+	}
+
+	err = c.Client.unmarshal(jsoniter.ConfigDefault, data, &symbolSearch, "symbol search")
+	if err != nil {
+		return nil, err
+	}
+
+	return symbolSearch, nil
+}
@@ -0,0 +1,94 @@
+package tdotel
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/jonnotjohn/twelvedata-go"
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// MetricHooks records request counts, latencies, retries, decoding errors and the remaining credits per endpoint
+type MetricHooks struct {
+	twelvedata.NopHooks
+	requests     metric.Int64Counter
+	duration     metric.Float64Histogram
+	retries      metric.Int64Counter
+	decodeErrors metric.Int64Counter
+	creditsLeft  metric.Int64Gauge
+}
+
+var _ twelvedata.Hooks = (*MetricHooks)(nil)
+
+// NewMetricHooks creates the instruments of the adapter:
+//   - twelvedata.requests: requests by endpoint and outcome ("ok", "api_error", "http_error" or
+//     "transport_error"). Error payloads count as "api_error" whatever their HTTP status
+//   - twelvedata.request.duration: seconds from the first attempt to the last response, by endpoint and outcome
+//   - twelvedata.retries: retried attempts by endpoint
+//   - twelvedata.decode_errors: responses that couldn't be decoded, by endpoint
+//   - twelvedata.credits.left: API credits left in the current minute, from the last response
+func NewMetricHooks(provider metric.MeterProvider) (*MetricHooks, error) {
+	meter := provider.Meter(instrumentationName)
+
+	h := &MetricHooks{}
+	var err error
+	if h.requests, err = meter.Int64Counter("twelvedata.requests",
+		metric.WithDescription("Requests sent to the Twelve Data API"), metric.WithUnit("{request}")); err != nil {
+		return nil, errors.Wrap(err, "Error creating requests counter")
+	}
+
+	if h.duration, err = meter.Float64Histogram("twelvedata.request.duration",
+		metric.WithDescription("Duration of requests to the Twelve Data API, including retries"), metric.WithUnit("s")); err != nil {
+		return nil, errors.Wrap(err, "Error creating request duration histogram")
+	}
+
+	if h.retries, err = meter.Int64Counter("twelvedata.retries",
+		metric.WithDescription("Retried attempts of requests to the Twelve Data API"), metric.WithUnit("{attempt}")); err != nil {
+		return nil, errors.Wrap(err, "Error creating retries counter")
+	}
+
+	if h.decodeErrors, err = meter.Int64Counter("twelvedata.decode_errors",
+		metric.WithDescription("Twelve Data API responses that couldn't be decoded"), metric.WithUnit("{response}")); err != nil {
+		return nil, errors.Wrap(err, "Error creating decode errors counter")
+	}
+
+	if h.creditsLeft, err = meter.Int64Gauge("twelvedata.credits.left",
+		metric.WithDescription("Twelve Data API credits left in the current minute"), metric.WithUnit("{credit}")); err != nil {
+		return nil, errors.Wrap(err, "Error creating credits gauge")
+	}
+
+	return h, nil
+}
+
+func (h *MetricHooks) OnRetry(ctx context.Context, event twelvedata.ResponseEvent) {
+	h.retries.Add(ctx, 1, metric.WithAttributes(attribute.String("twelvedata.endpoint", event.Endpoint)))
+}
+
+func (h *MetricHooks) AfterResponse(ctx context.Context, event twelvedata.ResponseEvent) {
+	outcome := "ok"
+	switch {
+	case event.Err != nil:
+		outcome = "transport_error"
+	case event.APIError != nil:
+		outcome = "api_error"
+	case event.StatusCode != http.StatusOK:
+		outcome = "http_error"
+	}
+
+	attributes := metric.WithAttributes(
+		attribute.String("twelvedata.endpoint", event.Endpoint),
+		attribute.String("twelvedata.outcome", outcome),
+	)
+	h.requests.Add(ctx, 1, attributes)
+	h.duration.Record(ctx, event.Duration.Seconds(), attributes)
+
+	if event.CreditsLeft >= 0 {
+		h.creditsLeft.Record(ctx, int64(event.CreditsLeft))
+	}
+}
+
+func (h *MetricHooks) OnDecodeError(ctx context.Context, event twelvedata.DecodeErrorEvent) {
+	h.decodeErrors.Add(ctx, 1, metric.WithAttributes(attribute.String("twelvedata.endpoint", event.Endpoint)))
+}
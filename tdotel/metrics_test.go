@@ -0,0 +1,180 @@
+package tdotel_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/jonnotjohn/twelvedata-go"
+	"github.com/jonnotjohn/twelvedata-go/tdotel"
+	"github.com/jonnotjohn/twelvedata-go/tdtest"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// collect returns the metric named name collected by reader
+func collect(t *testing.T, reader *sdkmetric.ManualReader, name string) (metricdata.Metrics, bool) {
+	t.Helper()
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, scope := range rm.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			if m.Name == name {
+				return m, true
+			}
+		}
+	}
+
+	return metricdata.Metrics{}, false
+}
+
+// counts returns the values of an int64 counter by the value of the attribute key
+func counts(t *testing.T, m metricdata.Metrics, key attribute.Key) map[string]int64 {
+	t.Helper()
+
+	sum, ok := m.Data.(metricdata.Sum[int64])
+	if !ok {
+		t.Fatalf("%s is a %T, want an int64 sum", m.Name, m.Data)
+	}
+
+	values := make(map[string]int64)
+	for _, point := range sum.DataPoints {
+		value, _ := point.Attributes.Value(key)
+		values[value.AsString()] += point.Value
+	}
+
+	return values
+}
+
+func TestMetricHooksOutcome(t *testing.T) {
+	tests := []struct {
+		name    string
+		doer    twelvedata.Doer
+		want    string
+		retries int64
+	}{
+		{
+			name: "ok",
+			doer: respond(http.StatusOK, `{"symbol": "AAPL"}`),
+			want: "ok",
+		},
+		{
+			name: "error payload with status 200",
+			doer: respond(http.StatusOK, `{"code": 404, "message": "symbol not found", "status": "error"}`),
+			want: "api_error",
+		},
+		{
+			name:    "error payload with status 429",
+			doer:    respond(http.StatusTooManyRequests, `{"code": 429, "message": "rate limited", "status": "error"}`),
+			want:    "api_error",
+			retries: 1,
+		},
+		{
+			name:    "http error",
+			doer:    respond(http.StatusBadGateway, "Bad Gateway"),
+			want:    "http_error",
+			retries: 1,
+		},
+		{
+			name: "transport error",
+			doer: twelvedata.DoerFunc(func(*http.Request) (*http.Response, error) {
+				return nil, errors.New("connection refused")
+			}),
+			want:    "transport_error",
+			retries: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader := sdkmetric.NewManualReader()
+			hooks, err := tdotel.NewMetricHooks(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_ = getQuote(newClient(t, nil, tt.doer, hooks))
+
+			requests, ok := collect(t, reader, "twelvedata.requests")
+			if !ok {
+				t.Fatal("twelvedata.requests wasn't recorded")
+			}
+
+			if got := counts(t, requests, "twelvedata.outcome"); len(got) != 1 || got[tt.want] != 1 {
+				t.Errorf("requests by outcome = %v, want %s: 1", got, tt.want)
+			}
+
+			retries, _ := collect(t, reader, "twelvedata.retries")
+			var gotRetries int64
+			if retries.Data != nil {
+				gotRetries = counts(t, retries, "twelvedata.endpoint")["/quote"]
+			}
+			if gotRetries != tt.retries {
+				t.Errorf("retries = %d, want %d", gotRetries, tt.retries)
+			}
+		})
+	}
+}
+
+func TestMetricHooksDecodeErrors(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	hooks, err := tdotel.NewMetricHooks(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := newClient(t, nil, respond(http.StatusOK, `{"symbol": `), hooks)
+	_ = getQuote(client)
+
+	// Error payloads decode fine and aren't decoding errors
+	client = newClient(t, nil, respond(http.StatusOK, `{"code": 404, "message": "symbol not found", "status": "error"}`), hooks)
+	_ = getQuote(client)
+
+	decodeErrors, ok := collect(t, reader, "twelvedata.decode_errors")
+	if !ok {
+		t.Fatal("twelvedata.decode_errors wasn't recorded")
+	}
+
+	if got := counts(t, decodeErrors, "twelvedata.endpoint"); got["/quote"] != 1 {
+		t.Errorf("decode errors = %v, want /quote: 1", got)
+	}
+}
+
+func TestMetricHooksCreditsLeft(t *testing.T) {
+	server := tdtest.NewServer()
+	defer server.Close()
+	server.SetCreditsPerMinute(10)
+
+	reader := sdkmetric.NewManualReader()
+	hooks, err := tdotel.NewMetricHooks(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := newClient(t, server, nil, hooks)
+	for range 3 {
+		if err := getQuote(client); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	credits, ok := collect(t, reader, "twelvedata.credits.left")
+	if !ok {
+		t.Fatal("twelvedata.credits.left wasn't recorded")
+	}
+
+	gauge, ok := credits.Data.(metricdata.Gauge[int64])
+	if !ok || len(gauge.DataPoints) != 1 {
+		t.Fatalf("credits.left = %#v, want a gauge with one point", credits.Data)
+	}
+
+	if got := gauge.DataPoints[0].Value; got != 7 {
+		t.Errorf("credits left = %d, want 7", got)
+	}
+}
@@ -0,0 +1,129 @@
+// Package tdotel reports the requests of a twelvedata client as OpenTelemetry spans and metrics. Both adapters take
+// providers instead of using the global ones, so they can be tested with the in-memory exporters of the SDK:
+//
+//	exporter := tracetest.NewInMemoryExporter()
+//	tracerProvider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+//	reader := sdkmetric.NewManualReader()
+//	meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+//
+//	metricHooks, err := tdotel.NewMetricHooks(meterProvider)
+//	...
+//	client, err := twelvedata.NewAPIClient(twelvedata.Config{
+//		Hooks: twelvedata.MultiHooks(tdotel.NewTracingHooks(tracerProvider), metricHooks),
+//	})
+package tdotel
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/jonnotjohn/twelvedata-go"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName names the tracer and meter of the adapters
+const instrumentationName = "github.com/jonnotjohn/twelvedata-go/tdotel"
+
+// TracingHooks records a client span per request, with an event per retry and a child span per decoding error
+type TracingHooks struct {
+	twelvedata.NopHooks
+	tracer trace.Tracer
+}
+
+var _ twelvedata.Hooks = (*TracingHooks)(nil)
+
+func NewTracingHooks(provider trace.TracerProvider) *TracingHooks {
+	return &TracingHooks{tracer: provider.Tracer(instrumentationName)}
+}
+
+// BeforeRequest starts the span of the request. Its name is the endpoint, with the params as attributes.
+func (h *TracingHooks) BeforeRequest(ctx context.Context, event twelvedata.RequestEvent) context.Context {
+	ctx, _ = h.tracer.Start(ctx, "GET "+event.Endpoint,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(requestAttributes(event.Endpoint, event.Params)...),
+	)
+
+	return ctx
+}
+
+func (h *TracingHooks) OnRetry(ctx context.Context, event twelvedata.ResponseEvent) {
+	span := trace.SpanFromContext(ctx)
+	attributes := []attribute.KeyValue{attribute.Int("twelvedata.attempt", event.Attempt)}
+	if event.StatusCode != 0 {
+		attributes = append(attributes, attribute.Int("http.response.status_code", event.StatusCode))
+	}
+	if event.Err != nil {
+		attributes = append(attributes, attribute.String("exception.message", event.Err.Error()))
+	}
+
+	span.AddEvent("retry", trace.WithAttributes(attributes...))
+}
+
+// AfterResponse ends the span of the request, with an error status for transport errors, error payloads and statuses
+// other than 200
+func (h *TracingHooks) AfterResponse(ctx context.Context, event twelvedata.ResponseEvent) {
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(responseAttributes(event)...)
+
+	switch {
+	case event.Err != nil:
+		span.RecordError(event.Err)
+		span.SetStatus(codes.Error, event.Err.Error())
+	case event.APIError != nil:
+		span.SetStatus(codes.Error, event.APIError.Error())
+	case event.StatusCode != http.StatusOK:
+		span.SetStatus(codes.Error, "unexpected status code "+strconv.Itoa(event.StatusCode))
+	}
+
+	span.End()
+}
+
+// OnDecodeError records the error as a child of the request span, which has already ended
+func (h *TracingHooks) OnDecodeError(ctx context.Context, event twelvedata.DecodeErrorEvent) {
+	_, span := h.tracer.Start(ctx, "decode "+event.Endpoint,
+		trace.WithAttributes(attribute.String("twelvedata.endpoint", event.Endpoint)),
+	)
+	span.RecordError(event.Err)
+	span.SetStatus(codes.Error, event.Err.Error())
+	span.End()
+}
+
+func requestAttributes(endpoint string, params map[string]string) []attribute.KeyValue {
+	keys := make([]string, 0, len(params))
+	for key := range params {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	attributes := []attribute.KeyValue{
+		attribute.String("http.request.method", http.MethodGet),
+		attribute.String("twelvedata.endpoint", endpoint),
+	}
+	for _, key := range keys {
+		attributes = append(attributes, attribute.String("twelvedata.param."+key, params[key]))
+	}
+
+	return attributes
+}
+
+func responseAttributes(event twelvedata.ResponseEvent) []attribute.KeyValue {
+	attributes := []attribute.KeyValue{attribute.Int("twelvedata.attempts", event.Attempt)}
+	if event.StatusCode != 0 {
+		attributes = append(attributes, attribute.Int("http.response.status_code", event.StatusCode))
+	}
+	if event.APIError != nil {
+		attributes = append(attributes, attribute.Int("twelvedata.error.code", event.APIError.Code))
+	}
+	if event.CreditsUsed >= 0 {
+		attributes = append(attributes, attribute.Int("twelvedata.credits.used", event.CreditsUsed))
+	}
+	if event.CreditsLeft >= 0 {
+		attributes = append(attributes, attribute.Int("twelvedata.credits.left", event.CreditsLeft))
+	}
+
+	return attributes
+}
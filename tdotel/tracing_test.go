@@ -0,0 +1,196 @@
+package tdotel_test
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jonnotjohn/twelvedata-go"
+	"github.com/jonnotjohn/twelvedata-go/tdotel"
+	"github.com/jonnotjohn/twelvedata-go/tdtest"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// newClient creates a client retrying once, sending its requests to the fake server or through doer when set
+func newClient(t *testing.T, server *tdtest.Server, doer twelvedata.Doer, hooks twelvedata.Hooks) *twelvedata.APIClient {
+	t.Helper()
+
+	retryCount := 2
+	retryWaitTime := time.Nanosecond
+	cfg := twelvedata.Config{Doer: doer, RetryCount: &retryCount, RetryWaitTime: &retryWaitTime, Hooks: hooks}
+	if server != nil {
+		cfg.APIUrl = twelvedata.APIUrl(server.URL)
+	}
+
+	client, err := twelvedata.NewAPIClient(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return client
+}
+
+// respond returns a Doer answering every request with status and body
+func respond(status int, body string) twelvedata.Doer {
+	return twelvedata.DoerFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: status,
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body:       io.NopCloser(strings.NewReader(body)),
+			Request:    req,
+		}, nil
+	})
+}
+
+func getQuote(client *twelvedata.APIClient) error {
+	symbol := "AAPL"
+	_, err := client.GetQuote(twelvedata.QuoteRequest{Symbol: &symbol})
+	return err
+}
+
+func attributeValue(attributes []attribute.KeyValue, key attribute.Key) (attribute.Value, bool) {
+	for _, kv := range attributes {
+		if kv.Key == key {
+			return kv.Value, true
+		}
+	}
+
+	return attribute.Value{}, false
+}
+
+func TestTracingHooks(t *testing.T) {
+	tests := []struct {
+		name        string
+		fault       *tdtest.Fault
+		wantErr     bool
+		wantCode    codes.Code
+		wantDesc    string
+		wantStatus  int64
+		wantAPICode int64 // Zero when the span shouldn't have an error code
+		wantRetries int
+	}{
+		{
+			name:       "ok",
+			wantCode:   codes.Unset,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:        "error payload with status 200",
+			fault:       &tdtest.Fault{Path: "/quote", Code: http.StatusNotFound, Message: "symbol not found"},
+			wantErr:     true,
+			wantCode:    codes.Error,
+			wantDesc:    "API error 404: symbol not found",
+			wantStatus:  http.StatusOK,
+			wantAPICode: http.StatusNotFound,
+		},
+		{
+			name:        "retried",
+			fault:       &tdtest.Fault{Path: "/quote", Status: http.StatusServiceUnavailable},
+			wantCode:    codes.Unset,
+			wantStatus:  http.StatusOK,
+			wantRetries: 1,
+		},
+		{
+			name:        "failed after retries",
+			fault:       &tdtest.Fault{Path: "/quote", Status: http.StatusServiceUnavailable, Message: "unavailable", Times: 2},
+			wantErr:     true,
+			wantCode:    codes.Error,
+			wantDesc:    "API error 503: unavailable",
+			wantStatus:  http.StatusServiceUnavailable,
+			wantAPICode: http.StatusServiceUnavailable,
+			wantRetries: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := tdtest.NewServer()
+			defer server.Close()
+
+			if tt.fault != nil {
+				server.Inject(*tt.fault)
+			}
+
+			exporter := tracetest.NewInMemoryExporter()
+			provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+			client := newClient(t, server, nil, tdotel.NewTracingHooks(provider))
+
+			if err := getQuote(client); (err != nil) != tt.wantErr {
+				t.Fatalf("GetQuote() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			spans := exporter.GetSpans()
+			if len(spans) != 1 {
+				t.Fatalf("got %d spans, want 1", len(spans))
+			}
+
+			span := spans[0]
+			if span.Name != "GET /quote" {
+				t.Errorf("span name = %q, want %q", span.Name, "GET /quote")
+			}
+
+			if span.Status.Code != tt.wantCode || span.Status.Description != tt.wantDesc {
+				t.Errorf("span status = %v %q, want %v %q", span.Status.Code, span.Status.Description, tt.wantCode, tt.wantDesc)
+			}
+
+			if value, _ := attributeValue(span.Attributes, "http.response.status_code"); value.AsInt64() != tt.wantStatus {
+				t.Errorf("status code attribute = %d, want %d", value.AsInt64(), tt.wantStatus)
+			}
+
+			if value, _ := attributeValue(span.Attributes, "twelvedata.error.code"); value.AsInt64() != tt.wantAPICode {
+				t.Errorf("error code attribute = %d, want %d", value.AsInt64(), tt.wantAPICode)
+			}
+
+			if value, ok := attributeValue(span.Attributes, "twelvedata.param.symbol"); !ok || value.AsString() != "AAPL" {
+				t.Errorf("symbol attribute = %q, want %q", value.AsString(), "AAPL")
+			}
+
+			if _, ok := attributeValue(span.Attributes, "twelvedata.param.apikey"); ok {
+				t.Error("span has the API key as attribute")
+			}
+
+			if tt.wantStatus == http.StatusOK {
+				if value, ok := attributeValue(span.Attributes, "twelvedata.credits.used"); !ok || value.AsInt64() != 1 {
+					t.Errorf("credits used attribute = %d, want 1", value.AsInt64())
+				}
+			}
+
+			if len(span.Events) != tt.wantRetries {
+				t.Errorf("got %d retry events, want %d", len(span.Events), tt.wantRetries)
+			}
+		})
+	}
+}
+
+func TestTracingHooksDecodeError(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	client := newClient(t, nil, respond(http.StatusOK, `{"symbol": `), tdotel.NewTracingHooks(provider))
+
+	if err := getQuote(client); err == nil {
+		t.Fatal("GetQuote() succeeded with a truncated body")
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("got %d spans, want 2", len(spans))
+	}
+
+	request, decode := spans[0], spans[1]
+	if request.Status.Code != codes.Unset {
+		t.Errorf("request span status = %v, want %v", request.Status.Code, codes.Unset)
+	}
+
+	if decode.Name != "decode /quote" || decode.Status.Code != codes.Error {
+		t.Errorf("decode span = %q %v, want %q %v", decode.Name, decode.Status.Code, "decode /quote", codes.Error)
+	}
+
+	if decode.Parent.SpanID() != request.SpanContext.SpanID() {
+		t.Error("decode span isn't a child of the request span")
+	}
+}
@@ -0,0 +1,287 @@
+package tdtest
+
+import (
+	"context"
+	"iter"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/jonnotjohn/twelvedata-go"
+	"github.com/pkg/errors"
+)
+
+// ErrNotMocked is returned by MockClient methods without a mock function
+var ErrNotMocked = errors.New("method not mocked")
+
+// MockClient implements twelvedata.Client with a function per method. Methods without a function return
+// ErrNotMocked.
+type MockClient struct {
+	GetQuoteFunc                   func(req twelvedata.QuoteRequest) (*twelvedata.Quote, error)
+	GetTimeSeriesFunc              func(req twelvedata.TimeSeriesRequest) (*twelvedata.TimeSeriesResponse, error)
+	GetTimeSeriesContextFunc       func(ctx context.Context, req twelvedata.TimeSeriesRequest) (*twelvedata.TimeSeriesResponse, error)
+	GetPriceFunc                   func(req twelvedata.PriceRequest) (*twelvedata.Price, error)
+	GetEarliestTimestampFunc       func(req twelvedata.EarliestTimestampRequest) (time.Time, error)
+	StreamTimeSeriesFunc           func(req twelvedata.TimeSeriesRequest) (*twelvedata.TimeSeriesStream, error)
+	GetStocksFunc                  func() (*twelvedata.StocksResponse, error)
+	GetStocksRequestFunc           func(req twelvedata.StocksRequest) (*twelvedata.StocksResponse, error)
+	StreamStocksFunc               func(req twelvedata.StocksRequest) iter.Seq2[twelvedata.Stocks, error]
+	GetCryptocurrenciesFunc        func() (*twelvedata.CryptoResponse, error)
+	GetCryptocurrenciesRequestFunc func(req twelvedata.CryptoRequest) (*twelvedata.CryptoResponse, error)
+	GetLogoFunc                    func(req twelvedata.LogoRequest) (*twelvedata.Logo, error)
+	GetSymbolSearchFunc            func(req twelvedata.SymbolSearchRequest) (*twelvedata.SymbolSearchResponse, error)
+	GetExchangeRateFunc            func(req twelvedata.ExchangeRateRequest) (*twelvedata.ExchangeRate, error)
+	GetCurrencyConversionFunc      func(req twelvedata.CurrencyConversionRequest) (*twelvedata.CurrencyConversion, error)
+	GetInsiderTransactionsFunc     func(req twelvedata.FundamentalsRequest) (*twelvedata.InsiderTransactionsResponse, error)
+	GetInstitutionalHoldersFunc    func(req twelvedata.FundamentalsRequest) (*twelvedata.InstitutionalHoldersResponse, error)
+	GetFundHoldersFunc             func(req twelvedata.FundamentalsRequest) (*twelvedata.FundHoldersResponse, error)
+	GetKeyExecutivesFunc           func(req twelvedata.FundamentalsRequest) (*twelvedata.KeyExecutivesResponse, error)
+	GetOptionsExpirationFunc       func(req twelvedata.OptionsExpirationRequest) (*twelvedata.OptionsExpirationResponse, error)
+	GetOptionChainFunc             func(req twelvedata.OptionChainRequest) (*twelvedata.OptionChainResponse, error)
+	GetMarketMoversFunc            func(req twelvedata.MarketMoversRequest) (*twelvedata.MarketMoversResponse, error)
+	GetMarketStateFunc             func(req twelvedata.MarketStateRequest) ([]twelvedata.MarketState, error)
+	GetExchangeScheduleFunc        func(req twelvedata.ExchangeScheduleRequest) (*twelvedata.ExchangeScheduleResponse, error)
+	GetFundSummaryFunc             func(req twelvedata.FundRequest) (*twelvedata.FundSummary, error)
+	GetFundPerformanceFunc         func(req twelvedata.FundRequest) (*twelvedata.FundPerformance, error)
+	GetFundRiskFunc                func(req twelvedata.FundRequest) (*twelvedata.FundRisk, error)
+	GetFundRatingsFunc             func(req twelvedata.FundRequest) (*twelvedata.FundRatings, error)
+	GetFundCompositionFunc         func(req twelvedata.FundRequest) (*twelvedata.FundComposition, error)
+	GetFundPurchaseInfoFunc        func(req twelvedata.FundRequest) (*twelvedata.FundPurchaseInfo, error)
+	GetFundFullFunc                func(req twelvedata.FundRequest) (*twelvedata.FundFull, error)
+}
+
+var _ twelvedata.Client = (*MockClient)(nil)
+
+func (m *MockClient) GetQuote(req twelvedata.QuoteRequest) (*twelvedata.Quote, error) {
+	if m.GetQuoteFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetQuoteFunc(req)
+}
+
+func (m *MockClient) GetTimeSeries(req twelvedata.TimeSeriesRequest) (*twelvedata.TimeSeriesResponse, error) {
+	if m.GetTimeSeriesFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetTimeSeriesFunc(req)
+}
+
+// GetTimeSeriesContext calls GetTimeSeriesFunc when GetTimeSeriesContextFunc isn't set, so one mock function serves
+// both variants
+func (m *MockClient) GetTimeSeriesContext(ctx context.Context, req twelvedata.TimeSeriesRequest) (*twelvedata.TimeSeriesResponse, error) {
+	if m.GetTimeSeriesContextFunc == nil {
+		return m.GetTimeSeries(req)
+	}
+	return m.GetTimeSeriesContextFunc(ctx, req)
+}
+
+func (m *MockClient) GetPrice(req twelvedata.PriceRequest) (*twelvedata.Price, error) {
+	if m.GetPriceFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetPriceFunc(req)
+}
+
+func (m *MockClient) GetEarliestTimestamp(req twelvedata.EarliestTimestampRequest) (time.Time, error) {
+	if m.GetEarliestTimestampFunc == nil {
+		return time.Time{}, ErrNotMocked
+	}
+	return m.GetEarliestTimestampFunc(req)
+}
+
+func (m *MockClient) StreamTimeSeries(req twelvedata.TimeSeriesRequest) (*twelvedata.TimeSeriesStream, error) {
+	if m.StreamTimeSeriesFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.StreamTimeSeriesFunc(req)
+}
+
+func (m *MockClient) GetStocks() (*twelvedata.StocksResponse, error) {
+	if m.GetStocksFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetStocksFunc()
+}
+
+func (m *MockClient) GetStocksRequest(req twelvedata.StocksRequest) (*twelvedata.StocksResponse, error) {
+	if m.GetStocksRequestFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetStocksRequestFunc(req)
+}
+
+func (m *MockClient) StreamStocks(req twelvedata.StocksRequest) iter.Seq2[twelvedata.Stocks, error] {
+	if m.StreamStocksFunc == nil {
+		return func(yield func(twelvedata.Stocks, error) bool) {
+			yield(twelvedata.Stocks{}, ErrNotMocked)
+		}
+	}
+	return m.StreamStocksFunc(req)
+}
+
+func (m *MockClient) GetCryptocurrencies() (*twelvedata.CryptoResponse, error) {
+	if m.GetCryptocurrenciesFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetCryptocurrenciesFunc()
+}
+
+func (m *MockClient) GetCryptocurrenciesRequest(req twelvedata.CryptoRequest) (*twelvedata.CryptoResponse, error) {
+	if m.GetCryptocurrenciesRequestFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetCryptocurrenciesRequestFunc(req)
+}
+
+func (m *MockClient) GetLogo(req twelvedata.LogoRequest) (*twelvedata.Logo, error) {
+	if m.GetLogoFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetLogoFunc(req)
+}
+
+func (m *MockClient) GetSymbolSearch(req twelvedata.SymbolSearchRequest) (*twelvedata.SymbolSearchResponse, error) {
+	if m.GetSymbolSearchFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetSymbolSearchFunc(req)
+}
+
+func (m *MockClient) GetExchangeRate(req twelvedata.ExchangeRateRequest) (*twelvedata.ExchangeRate, error) {
+	if m.GetExchangeRateFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetExchangeRateFunc(req)
+}
+
+func (m *MockClient) GetCurrencyConversion(req twelvedata.CurrencyConversionRequest) (*twelvedata.CurrencyConversion, error) {
+	if m.GetCurrencyConversionFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetCurrencyConversionFunc(req)
+}
+
+func (m *MockClient) GetInsiderTransactions(req twelvedata.FundamentalsRequest) (*twelvedata.InsiderTransactionsResponse, error) {
+	if m.GetInsiderTransactionsFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetInsiderTransactionsFunc(req)
+}
+
+func (m *MockClient) GetInstitutionalHolders(req twelvedata.FundamentalsRequest) (*twelvedata.InstitutionalHoldersResponse, error) {
+	if m.GetInstitutionalHoldersFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetInstitutionalHoldersFunc(req)
+}
+
+func (m *MockClient) GetFundHolders(req twelvedata.FundamentalsRequest) (*twelvedata.FundHoldersResponse, error) {
+	if m.GetFundHoldersFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetFundHoldersFunc(req)
+}
+
+func (m *MockClient) GetKeyExecutives(req twelvedata.FundamentalsRequest) (*twelvedata.KeyExecutivesResponse, error) {
+	if m.GetKeyExecutivesFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetKeyExecutivesFunc(req)
+}
+
+func (m *MockClient) GetOptionsExpiration(req twelvedata.OptionsExpirationRequest) (*twelvedata.OptionsExpirationResponse, error) {
+	if m.GetOptionsExpirationFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetOptionsExpirationFunc(req)
+}
+
+func (m *MockClient) GetOptionChain(req twelvedata.OptionChainRequest) (*twelvedata.OptionChainResponse, error) {
+	if m.GetOptionChainFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetOptionChainFunc(req)
+}
+
+func (m *MockClient) GetMarketMovers(req twelvedata.MarketMoversRequest) (*twelvedata.MarketMoversResponse, error) {
+	if m.GetMarketMoversFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetMarketMoversFunc(req)
+}
+
+func (m *MockClient) GetMarketState(req twelvedata.MarketStateRequest) ([]twelvedata.MarketState, error) {
+	if m.GetMarketStateFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetMarketStateFunc(req)
+}
+
+func (m *MockClient) GetExchangeSchedule(req twelvedata.ExchangeScheduleRequest) (*twelvedata.ExchangeScheduleResponse, error) {
+	if m.GetExchangeScheduleFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetExchangeScheduleFunc(req)
+}
+
+func (m *MockClient) GetFundSummary(req twelvedata.FundRequest) (*twelvedata.FundSummary, error) {
+	if m.GetFundSummaryFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetFundSummaryFunc(req)
+}
+
+func (m *MockClient) GetFundPerformance(req twelvedata.FundRequest) (*twelvedata.FundPerformance, error) {
+	if m.GetFundPerformanceFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetFundPerformanceFunc(req)
+}
+
+func (m *MockClient) GetFundRisk(req twelvedata.FundRequest) (*twelvedata.FundRisk, error) {
+	if m.GetFundRiskFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetFundRiskFunc(req)
+}
+
+func (m *MockClient) GetFundRatings(req twelvedata.FundRequest) (*twelvedata.FundRatings, error) {
+	if m.GetFundRatingsFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetFundRatingsFunc(req)
+}
+
+func (m *MockClient) GetFundComposition(req twelvedata.FundRequest) (*twelvedata.FundComposition, error) {
+	if m.GetFundCompositionFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetFundCompositionFunc(req)
+}
+
+func (m *MockClient) GetFundPurchaseInfo(req twelvedata.FundRequest) (*twelvedata.FundPurchaseInfo, error) {
+	if m.GetFundPurchaseInfoFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetFundPurchaseInfoFunc(req)
+}
+
+func (m *MockClient) GetFundFull(req twelvedata.FundRequest) (*twelvedata.FundFull, error) {
+	if m.GetFundFullFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetFundFullFunc(req)
+}
+
+// HandlerDoer returns a twelvedata.Doer serving requests with handler in-process, without opening a socket. Combine it
+// with the fake server as HandlerDoer(server.Config.Handler).
+func HandlerDoer(handler http.Handler) twelvedata.Doer {
+	return twelvedata.DoerFunc(func(req *http.Request) (*http.Response, error) {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, req)
+
+		response := recorder.Result()
+		response.Request = req
+		return response, nil
+	})
+}
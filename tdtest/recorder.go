@@ -0,0 +1,97 @@
+package tdtest
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+)
+
+// Recorder is an http.RoundTripper that passes requests to Transport and writes every exchange to a fixture file in
+// Dir. The API key is removed from the recorded parameters.
+type Recorder struct {
+	Dir       string            // Directory the fixtures are written to. Created if needed
+	Transport http.RoundTripper // Transport performing the requests. Defaults to http.DefaultTransport
+
+	mu sync.Mutex
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	transport := r.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, errors.Wrap(err, "Error reading response to record")
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	fixture := Fixture{
+		Request: FixtureRequest{
+			Method: req.Method,
+			Path:   req.URL.Path,
+			Params: requestParams(req.URL.Query()),
+		},
+		Response: FixtureResponse{
+			Status:  resp.StatusCode,
+			Headers: redactHeaders(resp.Header),
+		},
+	}
+
+	// Scrub the key in case the API echoes it back
+	if apiKey := req.URL.Query().Get(apiKeyParam); apiKey != "" {
+		body = bytes.ReplaceAll(body, []byte(apiKey), []byte("REDACTED"))
+	}
+
+	if jsoniter.Valid(body) {
+		fixture.Response.Body = body
+	} else {
+		fixture.Response.BodyText = string(body)
+	}
+
+	if err := r.write(fixture); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (r *Recorder) write(fixture Fixture) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := os.MkdirAll(r.Dir, 0o755); err != nil {
+		return errors.Wrap(err, "Error creating fixture directory")
+	}
+
+	data, err := jsoniter.ConfigCompatibleWithStandardLibrary.MarshalIndent(fixture, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "Error encoding fixture")
+	}
+
+	name := fixtureName(fixture.Request.Method, fixture.Request.Path, fixture.Request.Params)
+	if err := os.WriteFile(filepath.Join(r.Dir, name), append(data, '\n'), 0o644); err != nil {
+		return errors.Wrap(err, "Error writing fixture")
+	}
+
+	return nil
+}
+
+// isFixtureFile reports whether name looks like a fixture file
+func isFixtureFile(name string) bool {
+	return strings.HasSuffix(name, ".json")
+}
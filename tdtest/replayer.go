@@ -0,0 +1,186 @@
+package tdtest
+
+import (
+	"bytes"
+	"io"
+	"io/fs"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/jonnotjohn/twelvedata-go"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+)
+
+// MatchMode selects how a request is matched against the fixtures
+type MatchMode int
+
+const (
+	// MatchExact requires the same path and exactly the same parameters (the API key is ignored)
+	MatchExact MatchMode = iota
+	// MatchSubset requires the same path and every fixture parameter to be present with the same value in the request.
+	// The fixture with the most parameters wins.
+	MatchSubset
+	// MatchPath only requires the same path
+	MatchPath
+)
+
+// Replayer is an http.RoundTripper answering requests from fixtures instead of the network
+type Replayer struct {
+	fixtures []Fixture
+	mode     MatchMode
+
+	mu    sync.Mutex
+	calls []FixtureRequest
+}
+
+// NewReplayer loads every fixture file at the root of fsys
+func NewReplayer(fsys fs.FS, mode MatchMode) (*Replayer, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, errors.Wrap(err, "Error listing fixtures")
+	}
+
+	replayer := &Replayer{mode: mode}
+	for _, entry := range entries {
+		if entry.IsDir() || !isFixtureFile(entry.Name()) {
+			continue
+		}
+
+		data, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, errors.Wrapf(err, "Error reading fixture %s", entry.Name())
+		}
+
+		var fixture Fixture
+		if err := jsoniter.Unmarshal(data, &fixture); err != nil {
+			return nil, errors.Wrapf(err, "Error decoding fixture %s", entry.Name())
+		}
+
+		if fixture.Request.Method == "" {
+			fixture.Request.Method = http.MethodGet
+		}
+
+		if fixture.Response.Status == 0 {
+			fixture.Response.Status = http.StatusOK
+		}
+
+		replayer.fixtures = append(replayer.fixtures, fixture)
+	}
+
+	return replayer, nil
+}
+
+// NewReplayClient creates an API client answering every request from the fixtures in fsys. Unmatched requests fail
+// without retrying.
+func NewReplayClient(fsys fs.FS, mode MatchMode) (*twelvedata.APIClient, error) {
+	replayer, err := NewReplayer(fsys, mode)
+	if err != nil {
+		return nil, err
+	}
+
+	return replayer.Client()
+}
+
+// Client creates an API client answering every request from the replayer
+func (r *Replayer) Client() (*twelvedata.APIClient, error) {
+	retryCount := 1
+	retryWaitTime := time.Nanosecond
+
+	return twelvedata.NewAPIClient(twelvedata.Config{
+		APIKey:        "REDACTED",
+		Doer:          &http.Client{Transport: r},
+		RetryCount:    &retryCount,
+		RetryWaitTime: &retryWaitTime,
+	})
+}
+
+// Calls returns the requests answered so far, in order
+func (r *Replayer) Calls() []FixtureRequest {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]FixtureRequest(nil), r.calls...)
+}
+
+func (r *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	call := FixtureRequest{Method: req.Method, Path: req.URL.Path, Params: requestParams(req.URL.Query())}
+
+	fixture, ok := r.match(call)
+	if !ok {
+		return nil, errors.Errorf("no fixture matches %s %s %v", call.Method, call.Path, call.Params)
+	}
+
+	r.mu.Lock()
+	r.calls = append(r.calls, call)
+	r.mu.Unlock()
+
+	header := make(http.Header, len(fixture.Response.Headers))
+	for key, values := range fixture.Response.Headers {
+		header[key] = values
+	}
+
+	body := fixture.Response.body()
+	return &http.Response{
+		Status:        http.StatusText(fixture.Response.Status),
+		StatusCode:    fixture.Response.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+func (r *Replayer) match(call FixtureRequest) (Fixture, bool) {
+	var best Fixture
+	bestScore := -1
+
+	for _, fixture := range r.fixtures {
+		if fixture.Request.Method != call.Method || fixture.Request.Path != call.Path {
+			continue
+		}
+
+		score := len(fixture.Request.Params)
+		switch r.mode {
+		case MatchExact:
+			if !paramsEqual(fixture.Request.Params, call.Params) {
+				continue
+			}
+		case MatchSubset:
+			if !paramsSubset(fixture.Request.Params, call.Params) {
+				continue
+			}
+		case MatchPath:
+			// Prefer the fixture sharing the most parameters with the call
+			score = 0
+			for key, value := range fixture.Request.Params {
+				if call.Params[key] == value {
+					score++
+				}
+			}
+		}
+
+		if score > bestScore {
+			best, bestScore = fixture, score
+		}
+	}
+
+	return best, bestScore >= 0
+}
+
+func paramsEqual(a, b map[string]string) bool {
+	return len(a) == len(b) && paramsSubset(a, b)
+}
+
+func paramsSubset(subset, set map[string]string) bool {
+	for key, value := range subset {
+		if other, ok := set[key]; !ok || other != value {
+			return false
+		}
+	}
+	return true
+}
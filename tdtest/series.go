@@ -0,0 +1,623 @@
+package tdtest
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jonnotjohn/twelvedata-go"
+)
+
+const (
+	defaultOutputSize = 30
+	maxOutputSize     = 5000
+)
+
+// instrument describes how the fake server trades a symbol
+type instrument struct {
+	symbol        string
+	name          string
+	exchange      string
+	micCode       string
+	currency      string
+	currencyBase  string
+	currencyQuote string
+	kind          string
+	location      *time.Location
+	allDay        bool // Trades around the clock, every day
+}
+
+// bar is a synthetic candle
+type bar struct {
+	start                  time.Time
+	open, high, low, close float64
+	volume                 float64
+}
+
+func (s *Server) instrument(symbol string) (instrument, *apiError) {
+	if symbol == "" {
+		return instrument{}, missingParam("symbol")
+	}
+
+	if crypto, ok := s.findCrypto(symbol); ok {
+		return instrument{
+			symbol:        crypto.Symbol,
+			name:          crypto.CurrencyBase + " " + crypto.CurrencyQuote,
+			exchange:      firstOrEmpty(crypto.AvailableExchanges),
+			currencyBase:  crypto.CurrencyBase,
+			currencyQuote: crypto.CurrencyQuote,
+			kind:          "Digital Currency",
+			location:      time.UTC,
+			allDay:        true,
+		}, nil
+	}
+
+	if stock, ok := s.findStock(symbol); ok {
+		location, err := time.LoadLocation("America/New_York")
+		if err != nil {
+			location = time.UTC
+		}
+
+		return instrument{
+			symbol:   stock.Symbol,
+			name:     stock.Name,
+			exchange: stock.Exchange,
+			micCode:  stock.MicCode,
+			currency: stock.Currency,
+			kind:     stock.Type,
+			location: location,
+		}, nil
+	}
+
+	return instrument{}, symbolNotFound(symbol)
+}
+
+// interval is a bar size. Intraday intervals have a duration, the others are calendar periods.
+type interval struct {
+	name     string
+	duration time.Duration
+	days     int
+	months   int
+}
+
+var intervals = map[string]interval{
+	"1min":   {name: "1min", duration: time.Minute},
+	"5min":   {name: "5min", duration: 5 * time.Minute},
+	"15min":  {name: "15min", duration: 15 * time.Minute},
+	"30min":  {name: "30min", duration: 30 * time.Minute},
+	"45min":  {name: "45min", duration: 45 * time.Minute},
+	"1h":     {name: "1h", duration: time.Hour},
+	"2h":     {name: "2h", duration: 2 * time.Hour},
+	"4h":     {name: "4h", duration: 4 * time.Hour},
+	"5h":     {name: "5h", duration: 5 * time.Hour},
+	"1day":   {name: "1day", days: 1},
+	"1week":  {name: "1week", days: 7},
+	"1month": {name: "1month", months: 1},
+}
+
+func (iv interval) intraday() bool {
+	return iv.duration > 0
+}
+
+// periodStart returns the start of the day, week or month containing t
+func (iv interval) periodStart(t time.Time) time.Time {
+	year, month, day := t.Date()
+	switch {
+	case iv.months > 0:
+		return time.Date(year, month, 1, 0, 0, 0, 0, t.Location())
+	case iv.days == 7:
+		offset := (int(t.Weekday()) + 6) % 7
+		return time.Date(year, month, day-offset, 0, 0, 0, 0, t.Location())
+	default:
+		return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+	}
+}
+
+// nextPeriod moves a period start by n periods
+func (iv interval) nextPeriod(start time.Time, n int) time.Time {
+	switch {
+	case iv.months > 0:
+		return start.AddDate(0, n*iv.months, 0)
+	case iv.days == 7:
+		return start.AddDate(0, 0, 7*n)
+	default:
+		return start.AddDate(0, 0, n)
+	}
+}
+
+// session returns the trading session of a day, or false when the market is closed that day
+func (in instrument) session(day time.Time) (open, close time.Time, ok bool) {
+	year, month, date := day.Date()
+	if in.allDay {
+		return time.Date(year, month, date, 0, 0, 0, 0, day.Location()), time.Date(year, month, date+1, 0, 0, 0, 0, day.Location()), true
+	}
+
+	if weekday := day.Weekday(); weekday == time.Saturday || weekday == time.Sunday {
+		return time.Time{}, time.Time{}, false
+	}
+
+	return time.Date(year, month, date, 9, 30, 0, 0, day.Location()), time.Date(year, month, date, 16, 0, 0, 0, day.Location()), true
+}
+
+// starts returns the bar starts of the period beginning at period, in ascending order
+func (in instrument) starts(iv interval, period time.Time) []time.Time {
+	if !iv.intraday() {
+		if iv.days == 1 {
+			if _, _, ok := in.session(period); !ok {
+				return nil
+			}
+		}
+		return []time.Time{period}
+	}
+
+	open, close, ok := in.session(period)
+	if !ok {
+		return nil
+	}
+
+	var starts []time.Time
+	for start := open; start.Before(close); start = start.Add(iv.duration) {
+		starts = append(starts, start)
+	}
+	return starts
+}
+
+// end returns when the bar starting at start ends
+func (in instrument) end(iv interval, start time.Time) time.Time {
+	if iv.intraday() {
+		end := start.Add(iv.duration)
+		if _, close, ok := in.session(start); ok && end.After(close) {
+			return close
+		}
+		return end
+	}
+
+	if iv.days == 1 {
+		if _, close, ok := in.session(start); ok {
+			return close
+		}
+	}
+	return iv.nextPeriod(start, 1)
+}
+
+// bars returns up to n bars between from and to (inclusive), in ascending order. When forward is set the earliest
+// bars are kept, otherwise the latest ones.
+func (in instrument) bars(iv interval, from, to time.Time, n int, forward bool) []bar {
+	to = to.In(in.location)
+	from = from.In(in.location)
+
+	var starts []time.Time
+	inRange := func(start time.Time) bool {
+		return !start.Before(from) && !start.After(to)
+	}
+
+	if forward {
+		for period := iv.periodStart(from); len(starts) < n && !period.After(to); period = iv.nextPeriod(period, 1) {
+			for _, start := range in.starts(iv, period) {
+				if len(starts) < n && inRange(start) {
+					starts = append(starts, start)
+				}
+			}
+		}
+	} else {
+		first := iv.periodStart(from)
+		for period := iv.periodStart(to); len(starts) < n && !period.Before(first); period = iv.nextPeriod(period, -1) {
+			periodStarts := in.starts(iv, period)
+			for i := len(periodStarts) - 1; i >= 0; i-- {
+				if len(starts) < n && inRange(periodStarts[i]) {
+					starts = append(starts, periodStarts[i])
+				}
+			}
+		}
+
+		for i, j := 0, len(starts)-1; i < j; i, j = i+1, j-1 {
+			starts[i], starts[j] = starts[j], starts[i]
+		}
+	}
+
+	bars := make([]bar, len(starts))
+	for i, start := range starts {
+		bars[i] = in.bar(iv, start)
+	}
+	return bars
+}
+
+// bar computes the candle starting at start. Prices only depend on the symbol and the minute, so overlapping
+// requests and different intervals agree with each other.
+func (in instrument) bar(iv interval, start time.Time) bar {
+	end := in.end(iv, start)
+	last := end.Add(-time.Minute)
+	if last.Before(start) {
+		last = start
+	}
+
+	// Daily and longer bars open with the first session of the period
+	first := start
+	if open, _, ok := in.session(start); ok && !iv.intraday() {
+		first = open
+	}
+
+	open := in.price(first)
+	close := in.price(last)
+	minutes := math.Max(1, end.Sub(start).Minutes())
+
+	return bar{
+		start:  start,
+		open:   open,
+		high:   math.Max(open, close) * (1 + 0.004*in.noise(start.Unix(), 1)),
+		low:    math.Min(open, close) * (1 - 0.004*in.noise(start.Unix(), 2)),
+		close:  close,
+		volume: math.Round(minutes * (1000 + 9000*in.noise(start.Unix(), 3))),
+	}
+}
+
+func (in instrument) seed() uint64 {
+	hash := fnv.New64a()
+	_, _ = hash.Write([]byte(strings.ToUpper(in.symbol)))
+	return hash.Sum64()
+}
+
+// price is a smooth walk around a base price derived from the symbol
+func (in instrument) price(t time.Time) float64 {
+	seed := in.seed()
+	base := 20 + float64(seed%48000)/100
+	days := float64(t.Unix()) / 86400
+	phase := float64(seed%360) * math.Pi / 180
+
+	price := base * (1 +
+		0.08*math.Sin(2*math.Pi*days/45+phase) +
+		0.03*math.Sin(2*math.Pi*days/7+2*phase) +
+		0.004*(in.noise(t.Unix()/60, 0)-0.5))
+
+	return math.Round(price*1e5) / 1e5
+}
+
+// noise returns a deterministic pseudo-random number in [0, 1)
+func (in instrument) noise(n int64, stream uint64) float64 {
+	// splitmix64
+	x := in.seed() ^ uint64(n)*0x9e3779b97f4a7c15 ^ stream<<56
+	x = (x ^ x>>30) * 0xbf58476d1ce4e5b9
+	x = (x ^ x>>27) * 0x94d049bb133111eb
+	x ^= x >> 31
+	return float64(x>>11) / (1 << 53)
+}
+
+// seriesQuery is a parsed time series request
+type seriesQuery struct {
+	instrument instrument
+	interval   interval
+	location   *time.Location // Timezone of the returned datetimes and of start_date and end_date
+	from, to   time.Time
+	outputSize int
+	forward    bool
+	ascending  bool
+	dp         int
+}
+
+func (s *Server) parseSeriesQuery(params map[string]string) (seriesQuery, *apiError) {
+	in, apiErr := s.instrument(params["symbol"])
+	if apiErr != nil {
+		return seriesQuery{}, apiErr
+	}
+
+	iv, ok := intervals[params["interval"]]
+	if !ok {
+		return seriesQuery{}, missingParam("interval")
+	}
+
+	query := seriesQuery{instrument: in, interval: iv, location: in.location, outputSize: defaultOutputSize, dp: 5}
+
+	if timezone := params["timezone"]; timezone != "" && !strings.EqualFold(timezone, "Exchange") {
+		location, err := time.LoadLocation(timezone)
+		if err != nil {
+			return seriesQuery{}, missingParam("timezone")
+		}
+		query.location = location
+	}
+
+	if outputSize, ok := params["outputsize"]; ok {
+		value, err := strconv.Atoi(outputSize)
+		if err != nil || value < 1 || value > maxOutputSize {
+			return seriesQuery{}, missingParam("outputsize")
+		}
+		query.outputSize = value
+	}
+
+	if dp, ok := params["dp"]; ok {
+		value, err := strconv.Atoi(dp)
+		if err != nil || value > 11 {
+			return seriesQuery{}, missingParam("dp")
+		}
+		if value >= 0 {
+			query.dp = value
+		}
+	}
+
+	switch order := strings.ToLower(params["order"]); order {
+	case "", "desc":
+	case "asc":
+		query.ascending = true
+	default:
+		return seriesQuery{}, missingParam("order")
+	}
+
+	query.from = time.Unix(0, 0)
+	query.to = s.currentTime()
+
+	if date, ok := params["date"]; ok {
+		day, err := parseDate(date, query.location)
+		if err != nil {
+			return seriesQuery{}, missingParam("date")
+		}
+		query.from, query.to = day, day.AddDate(0, 0, 1).Add(-time.Nanosecond)
+	}
+
+	if startDate, ok := params["start_date"]; ok {
+		from, err := parseDate(startDate, query.location)
+		if err != nil {
+			return seriesQuery{}, missingParam("start_date")
+		}
+		query.from = from
+
+		// The API returns the bars following start_date when sorting in ascending order
+		query.forward = query.ascending
+		if _, ok := params["outputsize"]; !ok {
+			query.outputSize = maxOutputSize
+		}
+	}
+
+	if endDate, ok := params["end_date"]; ok {
+		to, err := parseDate(endDate, query.location)
+		if err != nil {
+			return seriesQuery{}, missingParam("end_date")
+		}
+		query.to = to
+	}
+
+	if query.from.After(query.to) {
+		return seriesQuery{}, &apiError{Code: http.StatusBadRequest, Message: "**start_date** must be before **end_date**."}
+	}
+
+	return query, nil
+}
+
+// parseDate parses the date formats accepted by the API in the given timezone
+func parseDate(str string, loc *time.Location) (time.Time, error) {
+	if parsed, err := time.ParseInLocation("2006-01-02 15:04:05", str, loc); err == nil {
+		return parsed, nil
+	}
+	return time.ParseInLocation("2006-01-02", str, loc)
+}
+
+func (q seriesQuery) formatTime(t time.Time) string {
+	if q.interval.intraday() {
+		return t.In(q.location).Format("2006-01-02 15:04:05")
+	}
+	return t.Format("2006-01-02")
+}
+
+func (q seriesQuery) formatPrice(price float64) string {
+	return strconv.FormatFloat(price, 'f', q.dp, 64)
+}
+
+func (s *Server) timeSeries(params map[string]string) (any, *apiError) {
+	query, apiErr := s.parseSeriesQuery(params)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	bars := query.instrument.bars(query.interval, query.from, query.to, query.outputSize, query.forward)
+	if len(bars) == 0 {
+		return nil, &apiError{Code: http.StatusBadRequest, Message: "No data is available on the specified dates. Try setting different start/end dates."}
+	}
+
+	if !query.ascending {
+		for i, j := 0, len(bars)-1; i < j; i, j = i+1, j-1 {
+			bars[i], bars[j] = bars[j], bars[i]
+		}
+	}
+
+	if isCSV(params) {
+		d := csvDelimiter(params)
+		var b strings.Builder
+		b.WriteString(strings.Join([]string{"datetime", "open", "high", "low", "close", "volume"}, d) + "\n")
+		for _, bar := range bars {
+			b.WriteString(strings.Join([]string{
+				query.formatTime(bar.start),
+				query.formatPrice(bar.open),
+				query.formatPrice(bar.high),
+				query.formatPrice(bar.low),
+				query.formatPrice(bar.close),
+				strconv.FormatFloat(bar.volume, 'f', 0, 64),
+			}, d) + "\n")
+		}
+		return csvBody(b.String()), nil
+	}
+
+	values := make([]map[string]string, len(bars))
+	for i, bar := range bars {
+		values[i] = map[string]string{
+			"datetime": query.formatTime(bar.start),
+			"open":     query.formatPrice(bar.open),
+			"high":     query.formatPrice(bar.high),
+			"low":      query.formatPrice(bar.low),
+			"close":    query.formatPrice(bar.close),
+			"volume":   strconv.FormatFloat(bar.volume, 'f', 0, 64),
+		}
+	}
+
+	in := query.instrument
+	meta := map[string]string{
+		"symbol":            in.symbol,
+		"interval":          query.interval.name,
+		"exchange_timezone": in.location.String(),
+		"exchange":          in.exchange,
+		"type":              in.kind,
+	}
+
+	if in.allDay {
+		meta["currency_base"] = in.currencyBase
+		meta["currency_quote"] = in.currencyQuote
+	} else {
+		meta["currency"] = in.currency
+		meta["mic_code"] = in.micCode
+	}
+
+	return map[string]any{"meta": meta, "values": values, "status": "ok"}, nil
+}
+
+func (s *Server) quote(params map[string]string) (any, *apiError) {
+	in, apiErr := s.instrument(params["symbol"])
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	now := s.currentTime()
+	daily := intervals["1day"]
+
+	year := in.bars(daily, now.AddDate(-1, 0, 0), now, 400, false)
+	if len(year) < 2 {
+		return nil, &apiError{Code: http.StatusBadRequest, Message: "No data is available for this symbol."}
+	}
+
+	last, previous := year[len(year)-1], year[len(year)-2]
+	low, high := last.low, last.high
+	var volume float64
+	for _, bar := range year {
+		low = math.Min(low, bar.low)
+		high = math.Max(high, bar.high)
+		volume += bar.volume
+	}
+
+	close := in.lastPrice(daily, last, now)
+
+	format := func(value float64) string {
+		return strconv.FormatFloat(value, 'f', 5, 64)
+	}
+
+	open, _, _ := in.session(now.In(in.location))
+	isOpen := !now.Before(open) && now.Before(in.end(daily, last.start)) && sameDay(last.start, now.In(in.location))
+
+	return map[string]any{
+		"symbol":         in.symbol,
+		"name":           in.name,
+		"exchange":       in.exchange,
+		"mic_code":       in.micCode,
+		"currency":       in.currency,
+		"datetime":       last.start.Format("2006-01-02"),
+		"timestamp":      last.start.Unix(),
+		"last_quote_at":  now.Unix(),
+		"open":           format(last.open),
+		"high":           format(math.Max(last.high, close)),
+		"low":            format(math.Min(last.low, close)),
+		"close":          format(close),
+		"volume":         format(last.volume),
+		"previous_close": format(previous.close),
+		"change":         format(close - previous.close),
+		"percent_change": format((close - previous.close) / previous.close * 100),
+		"average_volume": format(math.Round(volume / float64(len(year)))),
+		"is_market_open": isOpen,
+		"fifty_two_week": map[string]string{
+			"low":                 format(low),
+			"high":                format(high),
+			"low_change":          format(close - low),
+			"high_change":         format(close - high),
+			"low_change_percent":  format((close - low) / low * 100),
+			"high_change_percent": format((close - high) / high * 100),
+			"range":               format(low) + " - " + format(high),
+		},
+	}, nil
+}
+
+// lastPrice returns the close of the last daily bar, which is the latest minute price while the day is trading
+func (in instrument) lastPrice(daily interval, last bar, now time.Time) float64 {
+	if now.Before(in.end(daily, last.start)) {
+		return in.price(now)
+	}
+
+	return last.close
+}
+
+func (s *Server) price(params map[string]string) (any, *apiError) {
+	in, apiErr := s.instrument(params["symbol"])
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	now := s.currentTime()
+	daily := intervals["1day"]
+
+	days := in.bars(daily, now.AddDate(0, 0, -10), now, 10, false)
+	if len(days) == 0 {
+		return nil, &apiError{Code: http.StatusBadRequest, Message: "No data is available for this symbol."}
+	}
+
+	price := in.lastPrice(daily, days[len(days)-1], now)
+	return map[string]string{"price": strconv.FormatFloat(price, 'f', 5, 64)}, nil
+}
+
+func (s *Server) marketState(params map[string]string) (any, *apiError) {
+	s.mu.Lock()
+	stocks := append([]twelvedata.Stocks(nil), s.stocks...)
+	s.mu.Unlock()
+
+	now := s.currentTime()
+	seen := make(map[string]bool)
+	states := []map[string]any{}
+	for _, stock := range stocks {
+		if seen[stock.MicCode] ||
+			(params["exchange"] != "" && !strings.EqualFold(params["exchange"], stock.Exchange)) ||
+			(params["code"] != "" && !strings.EqualFold(params["code"], stock.MicCode)) ||
+			(params["country"] != "" && !strings.EqualFold(params["country"], stock.Country)) {
+			continue
+		}
+		seen[stock.MicCode] = true
+
+		in, apiErr := s.instrument(stock.Symbol)
+		if apiErr != nil {
+			return nil, apiErr
+		}
+
+		var afterOpen, toOpen, toClose time.Duration
+		open, close, ok := in.session(now.In(in.location))
+		isOpen := ok && !now.Before(open) && now.Before(close)
+		if isOpen {
+			afterOpen, toClose = now.Sub(open), close.Sub(now)
+		} else {
+			for day := now.In(in.location); ; day = day.AddDate(0, 0, 1) {
+				if open, _, ok := in.session(day); ok && open.After(now) {
+					toOpen = open.Sub(now)
+					break
+				}
+			}
+		}
+
+		states = append(states, map[string]any{
+			"name":            stock.Exchange,
+			"code":            stock.MicCode,
+			"country":         stock.Country,
+			"is_market_open":  isOpen,
+			"time_after_open": formatDuration(afterOpen),
+			"time_to_open":    formatDuration(toOpen),
+			"time_to_close":   formatDuration(toClose),
+		})
+	}
+
+	return states, nil
+}
+
+// formatDuration formats durations like the API ("HH:MM:SS")
+func formatDuration(d time.Duration) string {
+	seconds := int(d.Seconds())
+	return fmt.Sprintf("%02d:%02d:%02d", seconds/3600, seconds/60%60, seconds%60)
+}
+
+func sameDay(a, b time.Time) bool {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+	return ay == by && am == bm && ad == bd
+}
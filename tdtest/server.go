@@ -0,0 +1,526 @@
+package tdtest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jonnotjohn/twelvedata-go"
+	jsoniter "github.com/json-iterator/go"
+)
+
+// Fault is a failure injected into the responses of a Server
+type Fault struct {
+	Path    string        // Endpoint to fail (e.g. "/quote"). Empty matches every endpoint
+	Status  int           // HTTP status to answer with. Zero answers 200 with an error payload, like the API does
+	Code    int           // Error code of the payload (e.g. 400, 404, 429). Defaults to Status or 500
+	Message string        // Error message of the payload
+	Delay   time.Duration // Extra latency before answering
+	Times   int           // Number of requests to fail. Zero fails only the next one, negative fails forever
+}
+
+// Server is an in-process fake of the TwelveData API supporting /quote, /time_series, /price, /market_state, /stocks,
+// /cryptocurrencies, /logo and /symbol_search with deterministic synthetic data. Point a client at it with
+// Config.APIUrl or use Server.Client.
+//
+// Like the API, /quote, /time_series and /price answer a comma separated list of symbols with an object keyed by
+// symbol, holding an error payload for the symbols that failed, and charge one credit per symbol.
+//
+// Symbols containing a "/" trade around the clock in UTC, other symbols trade Monday to Friday from 09:30 to 16:00
+// in America/New_York. The same bar always has the same prices, whatever the query.
+type Server struct {
+	*httptest.Server
+
+	mu            sync.Mutex
+	stocks        []twelvedata.Stocks
+	cryptos       []twelvedata.Crypto
+	apiKey        string
+	latency       time.Duration
+	faults        []Fault
+	creditsLimit  int
+	creditsUsed   int
+	creditsMinute time.Time
+	now           func() time.Time
+	requests      []FixtureRequest
+}
+
+// NewServer starts a fake API server with a few stocks and cryptocurrencies. Close it when done.
+func NewServer() *Server {
+	s := &Server{
+		stocks: []twelvedata.Stocks{
+			{Symbol: "AAPL", Name: "Apple Inc", Currency: "USD", Exchange: "NASDAQ", MicCode: "XNGS", Country: "United States", Type: "Common Stock"},
+			{Symbol: "MSFT", Name: "Microsoft Corp", Currency: "USD", Exchange: "NASDAQ", MicCode: "XNGS", Country: "United States", Type: "Common Stock"},
+			{Symbol: "IBM", Name: "International Business Machines Corp", Currency: "USD", Exchange: "NYSE", MicCode: "XNYS", Country: "United States", Type: "Common Stock"},
+		},
+		cryptos: []twelvedata.Crypto{
+			{Symbol: "BTC/USD", AvailableExchanges: []string{"Binance", "Coinbase Pro"}, CurrencyBase: "Bitcoin", CurrencyQuote: "US Dollar"},
+			{Symbol: "ETH/USD", AvailableExchanges: []string{"Binance", "Kraken"}, CurrencyBase: "Ethereum", CurrencyQuote: "US Dollar"},
+		},
+		creditsLimit: 800,
+		now:          time.Now,
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/quote", s.handle(batch(s.quote)))
+	mux.HandleFunc("/time_series", s.handle(batch(s.timeSeries)))
+	mux.HandleFunc("/price", s.handle(batch(s.price)))
+	mux.HandleFunc("/market_state", s.handle(s.marketState))
+	mux.HandleFunc("/stocks", s.handle(s.stocksList))
+	mux.HandleFunc("/cryptocurrencies", s.handle(s.cryptoList))
+	mux.HandleFunc("/logo", s.handle(s.logo))
+	mux.HandleFunc("/symbol_search", s.handle(s.symbolSearch))
+	mux.HandleFunc("/", s.handle(func(params map[string]string) (any, *apiError) {
+		return nil, &apiError{Code: http.StatusNotFound, Message: "endpoint not supported by the fake server"}
+	}))
+
+	s.Server = httptest.NewServer(mux)
+	return s
+}
+
+// Client creates an API client for the server that doesn't retry and doesn't log
+func (s *Server) Client() (*twelvedata.APIClient, error) {
+	retryCount := 1
+	retryWaitTime := time.Nanosecond
+
+	s.mu.Lock()
+	apiKey := s.apiKey
+	s.mu.Unlock()
+
+	return twelvedata.NewAPIClient(twelvedata.Config{
+		APIKey:        apiKey,
+		APIUrl:        twelvedata.APIUrl(s.URL),
+		RetryCount:    &retryCount,
+		RetryWaitTime: &retryWaitTime,
+	})
+}
+
+// SetStocks replaces the stocks known to the server
+func (s *Server) SetStocks(stocks []twelvedata.Stocks) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stocks = stocks
+}
+
+// SetCryptocurrencies replaces the cryptocurrencies known to the server
+func (s *Server) SetCryptocurrencies(cryptos []twelvedata.Crypto) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cryptos = cryptos
+}
+
+// SetAPIKey makes the server reject requests without this key. Empty accepts any key.
+func (s *Server) SetAPIKey(apiKey string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.apiKey = apiKey
+}
+
+// SetLatency delays every response
+func (s *Server) SetLatency(latency time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.latency = latency
+}
+
+// SetCreditsPerMinute sets the credit budget per minute (default 800). Requests over budget get a 429.
+func (s *Server) SetCreditsPerMinute(credits int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.creditsLimit = credits
+}
+
+// SetNow sets the clock used for credit windows and for the end of generated series
+func (s *Server) SetNow(now func() time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.now = now
+}
+
+// Inject queues a fault. Faults are applied in the order they were injected.
+func (s *Server) Inject(fault Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if fault.Times == 0 {
+		fault.Times = 1
+	}
+	s.faults = append(s.faults, fault)
+}
+
+// Requests returns the requests received so far, in order, without the API key
+func (s *Server) Requests() []FixtureRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]FixtureRequest(nil), s.requests...)
+}
+
+type apiError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Status  string `json:"status"`
+}
+
+// csvBody is returned by handlers answering with CSV
+type csvBody string
+
+// takeFault returns the first queued fault matching path and consumes one of its uses
+func (s *Server) takeFault(path string) (Fault, bool) {
+	for i := range s.faults {
+		fault := &s.faults[i]
+		if fault.Path != "" && fault.Path != path {
+			continue
+		}
+
+		taken := *fault
+		if fault.Times > 0 {
+			if fault.Times--; fault.Times == 0 {
+				s.faults = append(s.faults[:i], s.faults[i+1:]...)
+			}
+		}
+
+		return taken, true
+	}
+
+	return Fault{}, false
+}
+
+// spendCredits uses credits of the current minute and returns the credits left, or false when over budget
+func (s *Server) spendCredits(credits int) (left int, ok bool) {
+	minute := s.now().Truncate(time.Minute)
+	if !minute.Equal(s.creditsMinute) {
+		s.creditsMinute = minute
+		s.creditsUsed = 0
+	}
+
+	if s.creditsUsed+credits > s.creditsLimit {
+		return s.creditsLimit - s.creditsUsed, false
+	}
+
+	s.creditsUsed += credits
+	return s.creditsLimit - s.creditsUsed, true
+}
+
+func (s *Server) handle(handler func(params map[string]string) (any, *apiError)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		params := requestParams(r.URL.Query())
+		apiKey := r.URL.Query().Get(apiKeyParam)
+		credits := max(len(batchSymbols(params)), 1)
+
+		s.mu.Lock()
+		s.requests = append(s.requests, FixtureRequest{Method: r.Method, Path: r.URL.Path, Params: params})
+		latency := s.latency
+		fault, faulted := s.takeFault(r.URL.Path)
+		expectedKey := s.apiKey
+		creditsLeft, withinBudget := s.spendCredits(credits)
+		s.mu.Unlock()
+
+		if faulted {
+			latency += fault.Delay
+		}
+
+		if latency > 0 {
+			select {
+			case <-time.After(latency):
+			case <-r.Context().Done():
+				return
+			}
+		}
+
+		w.Header().Set("api-credits-used", strconv.Itoa(credits))
+		w.Header().Set("api-credits-left", strconv.Itoa(creditsLeft))
+
+		switch {
+		case faulted && (fault.Status != 0 || fault.Code != 0 || fault.Message != ""):
+			code := fault.Code
+			if code == 0 {
+				code = fault.Status
+			}
+			if code == 0 {
+				code = http.StatusInternalServerError
+			}
+
+			message := fault.Message
+			if message == "" {
+				message = "injected fault"
+			}
+
+			status := fault.Status
+			if status == 0 {
+				status = http.StatusOK
+			}
+
+			writeJSON(w, status, apiError{Code: code, Message: message, Status: "error"})
+			return
+		case expectedKey != "" && apiKey != expectedKey:
+			writeJSON(w, http.StatusOK, apiError{Code: http.StatusUnauthorized, Message: "**apikey** parameter is incorrect or not specified.", Status: "error"})
+			return
+		case !withinBudget:
+			writeJSON(w, http.StatusTooManyRequests, apiError{
+				Code:    http.StatusTooManyRequests,
+				Message: "You have run out of API credits for the current minute.",
+				Status:  "error",
+			})
+			return
+		}
+
+		body, apiErr := handler(params)
+		if apiErr != nil {
+			apiErr.Status = "error"
+			writeJSON(w, http.StatusOK, apiErr)
+			return
+		}
+
+		if csv, ok := body.(csvBody); ok {
+			w.Header().Set("Content-Type", "text/csv")
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(csv))
+			return
+		}
+
+		writeJSON(w, http.StatusOK, body)
+	}
+}
+
+// batchSymbols returns the symbols of a comma separated symbol param
+func batchSymbols(params map[string]string) []string {
+	if params["symbol"] == "" {
+		return nil
+	}
+
+	return strings.Split(params["symbol"], ",")
+}
+
+// batch answers requests for several symbols with the answers of handler for each symbol, keyed by symbol
+func batch(handler func(params map[string]string) (any, *apiError)) func(params map[string]string) (any, *apiError) {
+	return func(params map[string]string) (any, *apiError) {
+		symbols := batchSymbols(params)
+		if len(symbols) < 2 {
+			return handler(params)
+		}
+
+		if isCSV(params) {
+			return nil, &apiError{Code: http.StatusBadRequest, Message: "**format** CSV is not supported for multiple symbols."}
+		}
+
+		bodies := make(map[string]any, len(symbols))
+		for _, symbol := range symbols {
+			symbolParams := make(map[string]string, len(params))
+			for key, value := range params {
+				symbolParams[key] = value
+			}
+			symbolParams["symbol"] = strings.TrimSpace(symbol)
+
+			body, apiErr := handler(symbolParams)
+			if apiErr != nil {
+				apiErr.Status = "error"
+				body = apiErr
+			}
+			bodies[symbolParams["symbol"]] = body
+		}
+
+		return bodies, nil
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	data, err := jsoniter.ConfigCompatibleWithStandardLibrary.Marshal(body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	_, _ = w.Write(data)
+}
+
+func (s *Server) findStock(symbol string) (twelvedata.Stocks, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, stock := range s.stocks {
+		if strings.EqualFold(stock.Symbol, symbol) {
+			return stock, true
+		}
+	}
+	return twelvedata.Stocks{}, false
+}
+
+func (s *Server) findCrypto(symbol string) (twelvedata.Crypto, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, crypto := range s.cryptos {
+		if strings.EqualFold(crypto.Symbol, symbol) {
+			return crypto, true
+		}
+	}
+	return twelvedata.Crypto{}, false
+}
+
+func (s *Server) currentTime() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.now()
+}
+
+// csvDelimiter returns the delimiter requested for CSV responses
+func csvDelimiter(params map[string]string) string {
+	if delimiter := params["delimiter"]; delimiter != "" {
+		return delimiter
+	}
+	return ";"
+}
+
+func isCSV(params map[string]string) bool {
+	return strings.EqualFold(params["format"], "csv")
+}
+
+func (s *Server) stocksList(params map[string]string) (any, *apiError) {
+	s.mu.Lock()
+	all := append([]twelvedata.Stocks(nil), s.stocks...)
+	s.mu.Unlock()
+
+	filters := map[string]func(twelvedata.Stocks) string{
+		"symbol":   func(st twelvedata.Stocks) string { return st.Symbol },
+		"exchange": func(st twelvedata.Stocks) string { return st.Exchange },
+		"mic_code": func(st twelvedata.Stocks) string { return st.MicCode },
+		"country":  func(st twelvedata.Stocks) string { return st.Country },
+		"type":     func(st twelvedata.Stocks) string { return st.Type },
+	}
+
+	stocks := []twelvedata.Stocks{}
+	for _, stock := range all {
+		matches := true
+		for param, field := range filters {
+			if value, ok := params[param]; ok && !strings.EqualFold(field(stock), value) {
+				matches = false
+			}
+		}
+
+		if matches {
+			stocks = append(stocks, stock)
+		}
+	}
+
+	if isCSV(params) {
+		d := csvDelimiter(params)
+		var b strings.Builder
+		b.WriteString(strings.Join([]string{"symbol", "name", "currency", "exchange", "mic_code", "country", "type", "figi_code", "cfi_code", "isin", "cusip"}, d) + "\n")
+		for _, st := range stocks {
+			b.WriteString(strings.Join([]string{st.Symbol, st.Name, st.Currency, st.Exchange, st.MicCode, st.Country, st.Type, st.FigiCode, st.CfiCode, st.ISIN, st.CUSIP}, d) + "\n")
+		}
+		return csvBody(b.String()), nil
+	}
+
+	return twelvedata.StocksResponse{Data: stocks, Count: len(stocks), Status: "ok"}, nil
+}
+
+func (s *Server) cryptoList(params map[string]string) (any, *apiError) {
+	s.mu.Lock()
+	all := append([]twelvedata.Crypto(nil), s.cryptos...)
+	s.mu.Unlock()
+
+	cryptos := []twelvedata.Crypto{}
+	for _, crypto := range all {
+		if symbol, ok := params["symbol"]; ok && !strings.EqualFold(crypto.Symbol, symbol) {
+			continue
+		}
+		cryptos = append(cryptos, crypto)
+	}
+
+	if isCSV(params) {
+		d := csvDelimiter(params)
+		var b strings.Builder
+		b.WriteString(strings.Join([]string{"symbol", "available_exchanges", "currency_base", "currency_quote"}, d) + "\n")
+		for _, c := range cryptos {
+			b.WriteString(strings.Join([]string{c.Symbol, "[" + strings.Join(c.AvailableExchanges, ", ") + "]", c.CurrencyBase, c.CurrencyQuote}, d) + "\n")
+		}
+		return csvBody(b.String()), nil
+	}
+
+	return twelvedata.CryptoResponse{Data: cryptos, Status: "ok"}, nil
+}
+
+func (s *Server) logo(params map[string]string) (any, *apiError) {
+	symbol := params["symbol"]
+	if symbol == "" {
+		return nil, missingParam("symbol")
+	}
+
+	if crypto, ok := s.findCrypto(symbol); ok {
+		base, quote, _ := strings.Cut(strings.ToLower(crypto.Symbol), "/")
+		return map[string]any{
+			"meta":       map[string]string{"symbol": crypto.Symbol, "exchange": firstOrEmpty(crypto.AvailableExchanges)},
+			"logo_base":  "https://logo.twelvedata.com/crypto/" + base + ".png",
+			"logo_quote": "https://logo.twelvedata.com/crypto/" + quote + ".png",
+		}, nil
+	}
+
+	if stock, ok := s.findStock(symbol); ok {
+		return map[string]any{
+			"meta": map[string]string{"symbol": stock.Symbol, "exchange": stock.Exchange},
+			"url":  "https://logo.twelvedata.com/" + strings.ToLower(stock.Symbol) + ".png",
+		}, nil
+	}
+
+	return nil, symbolNotFound(symbol)
+}
+
+func (s *Server) symbolSearch(params map[string]string) (any, *apiError) {
+	query := strings.ToUpper(params["symbol"])
+	if query == "" {
+		return nil, missingParam("symbol")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	matches := []map[string]string{}
+	for _, stock := range s.stocks {
+		if strings.HasPrefix(stock.Symbol, query) || strings.Contains(strings.ToUpper(stock.Name), query) {
+			matches = append(matches, map[string]string{
+				"symbol":            stock.Symbol,
+				"instrument_name":   stock.Name,
+				"exchange":          stock.Exchange,
+				"mic_code":          stock.MicCode,
+				"exchange_timezone": "America/New_York",
+				"instrument_type":   stock.Type,
+				"country":           stock.Country,
+				"currency":          stock.Currency,
+			})
+		}
+	}
+
+	for _, crypto := range s.cryptos {
+		if strings.HasPrefix(crypto.Symbol, query) || strings.Contains(strings.ToUpper(crypto.CurrencyBase), query) {
+			matches = append(matches, map[string]string{
+				"symbol":            crypto.Symbol,
+				"instrument_name":   crypto.CurrencyBase + " " + crypto.CurrencyQuote,
+				"exchange":          firstOrEmpty(crypto.AvailableExchanges),
+				"exchange_timezone": "UTC",
+				"instrument_type":   "Digital Currency",
+			})
+		}
+	}
+
+	return map[string]any{"data": matches, "status": "ok"}, nil
+}
+
+func firstOrEmpty(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+func missingParam(name string) *apiError {
+	return &apiError{Code: http.StatusBadRequest, Message: "**" + name + "** parameter is missing or invalid."}
+}
+
+func symbolNotFound(symbol string) *apiError {
+	return &apiError{Code: http.StatusNotFound, Message: "**symbol** not found: " + symbol + ". Please specify it correctly."}
+}
@@ -1,31 +1,47 @@
 package twelvedata
 
 import (
-	"github.com/pkg/errors"
 	"strconv"
+	"strings"
 	"time"
+
+	"github.com/pkg/errors"
 )
 
-// TDTime handles the datetime format used by TwelveData API
+// TDTime handles the datetime format used by TwelveData API. The API sends wall clock datetimes without an offset,
+// which are parsed as UTC until the client localizes them to the effective timezone of the response (the request
+// TimeZone, then the exchange timezone, then UTC). Unix timestamps are absolute and only change their location.
 type TDTime struct {
 	time.Time
+	wallClock bool // Parsed from a datetime without offset, see Localize
 }
 
+// TDZonedTime is a datetime already parsed in the timezone of its response
 type TDZonedTime struct {
 	time.Time
 }
 
-func (t *TDTime) UnmarshalJSON(data []byte) error {
-	// Remove quotes from JSON string
-	str := string(data[1 : len(data)-1])
+// Localize returns the time in loc. Wall clock datetimes keep their clock reading and are reinterpreted in loc,
+// absolute times keep their instant. The zero time is returned unchanged.
+func (t TDTime) Localize(loc *time.Location) TDTime {
+	if t.IsZero() || loc == nil {
+		return t
+	}
 
-	if parsed, err := time.Parse("2006-01-02 15:04:05", str); err == nil {
-		t.Time = parsed
-		return nil
+	if !t.wallClock {
+		return TDTime{Time: t.In(loc)}
 	}
 
-	if parsed, err := time.Parse("2006-01-02", str); err == nil {
-		t.Time = parsed
+	year, month, day := t.Date()
+	hour, minute, sec := t.Clock()
+	return TDTime{Time: time.Date(year, month, day, hour, minute, sec, t.Nanosecond(), loc), wallClock: true}
+}
+
+func (t *TDTime) UnmarshalJSON(data []byte) error {
+	*t = TDTime{}
+
+	// Leave the zero time for missing values
+	if string(data) == "null" || string(data) == `""` {
 		return nil
 	}
 
@@ -33,10 +49,112 @@ func (t *TDTime) UnmarshalJSON(data []byte) error {
 	// This will break on 20 November 2286, but that's a long way off
 	if fullStr := string(data); len(fullStr) == 10 {
 		if parsedInt, errInt := strconv.ParseInt(fullStr, 10, 64); errInt == nil {
-			t.Time = time.Unix(parsedInt, 0)
+			t.Time = time.Unix(parsedInt, 0).UTC()
 			return nil
 		}
 	}
 
-	return errors.New("invalid datetime format encountered when parsing response from TwelveData API: " + str)
+	// Remove quotes from JSON string
+	str := strings.Trim(string(data), `"`)
+
+	// Times written by MarshalJSON carry their offset
+	if parsed, err := time.Parse(time.RFC3339Nano, str); err == nil {
+		t.Time = parsed
+		return nil
+	}
+
+	parsed, err := parseInLocation(str, time.UTC)
+	if err != nil {
+		return errors.New("invalid datetime format encountered when parsing response from TwelveData API: " + str)
+	}
+
+	t.Time = parsed
+	t.wallClock = true
+	return nil
+}
+
+// MarshalJSON writes the time as RFC 3339 with its UTC offset, or null for the zero time
+func (t TDTime) MarshalJSON() ([]byte, error) {
+	return marshalTime(t.Time)
+}
+
+// UnmarshalJSON reads RFC 3339 times as written by MarshalJSON. Datetimes in the API formats are parsed as UTC.
+func (t *TDZonedTime) UnmarshalJSON(data []byte) error {
+	var parsed TDTime
+	if err := parsed.UnmarshalJSON(data); err != nil {
+		return err
+	}
+
+	t.Time = parsed.Time
+	return nil
+}
+
+// MarshalJSON writes the time as RFC 3339 with its UTC offset, or null for the zero time
+func (t TDZonedTime) MarshalJSON() ([]byte, error) {
+	return marshalTime(t.Time)
+}
+
+func marshalTime(t time.Time) ([]byte, error) {
+	if t.IsZero() {
+		return []byte("null"), nil
+	}
+
+	return []byte(`"` + t.Format(time.RFC3339Nano) + `"`), nil
+}
+
+// resolveLocation returns the effective timezone of a response: the request timezone when set, otherwise the
+// exchange timezone when known, otherwise UTC
+func resolveLocation(timezone *string, exchangeTimezone string) (*time.Location, error) {
+	if timezone != nil && *timezone != "" && *timezone != "Exchange" {
+		loc, err := time.LoadLocation(*timezone)
+		return loc, errors.Wrap(err, "failed to load request timezone")
+	}
+
+	if exchangeTimezone != "" {
+		loc, err := time.LoadLocation(exchangeTimezone)
+		return loc, errors.Wrap(err, "failed to load exchange timezone")
+	}
+
+	return time.UTC, nil
+}
+
+// TDDuration handles the "HH:MM:SS" durations used by the TwelveData API. Hours may exceed 24.
+type TDDuration struct {
+	time.Duration
+}
+
+func (d *TDDuration) UnmarshalJSON(data []byte) error {
+	// Remove quotes from JSON string
+	str := strings.Trim(string(data), `"`)
+
+	parts := strings.Split(str, ":")
+	if len(parts) != 3 {
+		return errors.New("invalid duration format encountered when parsing response from TwelveData API: " + str)
+	}
+
+	var total time.Duration
+	for i, unit := range []time.Duration{time.Hour, time.Minute, time.Second} {
+		value, err := strconv.Atoi(parts[i])
+		if err != nil {
+			return errors.New("invalid duration format encountered when parsing response from TwelveData API: " + str)
+		}
+		total += time.Duration(value) * unit
+	}
+
+	d.Duration = total
+	return nil
+}
+
+// parseInLocation parses the datetime formats used by the TwelveData API ("2006-01-02 15:04:05" and "2006-01-02")
+// in the given timezone
+func parseInLocation(str string, loc *time.Location) (time.Time, error) {
+	if parsed, err := time.ParseInLocation("2006-01-02 15:04:05", str, loc); err == nil {
+		return parsed, nil
+	}
+
+	if parsed, err := time.ParseInLocation("2006-01-02", str, loc); err == nil {
+		return parsed, nil
+	}
+
+	return time.Time{}, errors.Errorf("failed to parse datetime %s in timezone %s", str, loc)
 }
@@ -0,0 +1,161 @@
+package twelvedata_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jonnotjohn/twelvedata-go"
+	jsoniter "github.com/json-iterator/go"
+)
+
+func TestTimeRoundTrip(t *testing.T) {
+	times := []time.Time{
+		at("2024-03-08 15:30"),
+		at("2024-03-11 09:30"), // After the start of daylight saving time
+		time.Date(2024, 6, 14, 9, 0, 0, 0, mustLoadLocation("Asia/Tokyo")),
+		time.Date(2024, 6, 14, 9, 0, 0, 0, mustLoadLocation("Asia/Kathmandu")), // +05:45
+		time.Date(2024, 6, 14, 9, 0, 0, 0, mustLoadLocation("America/St_Johns")),
+		time.Date(2024, 6, 14, 9, 0, 0, 123456789, time.UTC),
+	}
+
+	for _, original := range times {
+		_, offset := original.Zone()
+
+		tdTime := twelvedata.TDTime{Time: original}
+		data, err := json.Marshal(tdTime)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var readTime twelvedata.TDTime
+		if err := json.Unmarshal(data, &readTime); err != nil {
+			t.Fatalf("TDTime %s: %v", data, err)
+		}
+
+		zoned := twelvedata.TDZonedTime{Time: original}
+		zonedData, err := jsoniter.Marshal(zoned)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expect(t, "TDZonedTime JSON", string(zonedData), string(data))
+
+		var readZoned twelvedata.TDZonedTime
+		if err := jsoniter.Unmarshal(zonedData, &readZoned); err != nil {
+			t.Fatalf("TDZonedTime %s: %v", zonedData, err)
+		}
+
+		// The instant and the offset are kept, the name of the location isn't written
+		for name, read := range map[string]time.Time{"TDTime": readTime.Time, "TDZonedTime": readZoned.Time} {
+			if !read.Equal(original) {
+				t.Errorf("%s %s read back as %s", name, data, read)
+			}
+			if _, readOffset := read.Zone(); readOffset != offset {
+				t.Errorf("%s %s read back with offset %d, want %d", name, data, readOffset, offset)
+			}
+		}
+
+		// Localizing a time read back restores its location
+		localized := readTime.Localize(original.Location())
+		expect(t, "Localize()", localized.Time.String(), original.String())
+	}
+}
+
+func TestTimeRoundTripZero(t *testing.T) {
+	data, err := json.Marshal(struct {
+		Time  twelvedata.TDTime      `json:"time"`
+		Zoned twelvedata.TDZonedTime `json:"zoned"`
+	}{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect(t, "JSON", string(data), `{"time":null,"zoned":null}`)
+
+	read := struct {
+		Time  twelvedata.TDTime      `json:"time"`
+		Zoned twelvedata.TDZonedTime `json:"zoned"`
+	}{
+		Time:  twelvedata.TDTime{Time: at("2024-06-14 09:30")},
+		Zoned: twelvedata.TDZonedTime{Time: at("2024-06-14 09:30")},
+	}
+	if err := json.Unmarshal(data, &read); err != nil {
+		t.Fatal(err)
+	}
+	expect(t, "Time.IsZero()", read.Time.IsZero(), true)
+	expect(t, "Zoned.IsZero()", read.Zoned.IsZero(), true)
+}
+
+func TestTDTimeLocalize(t *testing.T) {
+	tests := []struct {
+		json string
+		loc  *time.Location
+		want time.Time
+	}{
+		// Wall clocks keep their reading across daylight saving time
+		{json: `"2024-03-08 15:30:00"`, loc: newYork, want: at("2024-03-08 15:30")},
+		{json: `"2024-03-11 09:30:00"`, loc: newYork, want: at("2024-03-11 09:30")},
+		{json: `"2024-06-14"`, loc: newYork, want: at("2024-06-14 00:00")},
+		// Absolute times keep their instant
+		{json: `1718371800`, loc: newYork, want: at("2024-06-14 09:30")},
+		{json: `"2024-06-14T13:30:00Z"`, loc: newYork, want: at("2024-06-14 09:30")},
+		{json: `"2024-06-14T09:30:00-04:00"`, loc: time.UTC, want: time.Date(2024, 6, 14, 13, 30, 0, 0, time.UTC)},
+		// Without a location, wall clocks stay in UTC
+		{json: `"2024-06-14 09:30:00"`, want: time.Date(2024, 6, 14, 9, 30, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		var parsed twelvedata.TDTime
+		if err := json.Unmarshal([]byte(tt.json), &parsed); err != nil {
+			t.Fatalf("%s: %v", tt.json, err)
+		}
+
+		expect(t, "Localize("+tt.json+")", parsed.Localize(tt.loc).Time.String(), tt.want.String())
+	}
+}
+
+func TestResponsesLocalizedToCachedTimezone(t *testing.T) {
+	client := handlerClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/time_series":
+			_, _ = w.Write([]byte(`{"meta":{"symbol":"` + r.URL.Query().Get("symbol") + `","interval":"1day",` +
+				`"exchange_timezone":"America/New_York"},"values":[],"status":"ok"}`))
+		case "/market_movers/stocks":
+			_, _ = w.Write([]byte(`{"values":[` +
+				`{"symbol":"NVCR","mic_code":"XNGS","datetime":"2024-06-14 15:59:59","last":23.91},` +
+				`{"symbol":"XYZ","mic_code":"XNYS","datetime":"2024-06-14 15:59:59","last":1}],"status":"ok"}`))
+		case "/mutual_funds/world/summary":
+			_, _ = w.Write([]byte(`{"mutual_fund":{"summary":{"symbol":"VFIAX","share_class_inception_date":"2000-11-13",` +
+				`"people":[{"name":"Michelle Louie","tenure_since":"2017-11-30"}]}},"status":"ok"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+
+	// Movers and funds are only localized once a time series response cached the timezone of their listing
+	movers, err := client.GetMarketMovers(twelvedata.MarketMoversRequest{Market: ptr(twelvedata.MarketMoversMarketStocks)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect(t, "DateTime before caching", movers.Values[0].DateTime.Time, time.Date(2024, 6, 14, 15, 59, 59, 0, time.UTC))
+
+	for _, symbol := range []string{"NVCR", "VFIAX"} {
+		if _, err := client.GetTimeSeries(twelvedata.TimeSeriesRequest{Symbol: ptr(symbol), Interval: ptr(twelvedata.TimeSeriesInterval1Day)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	movers, err = client.GetMarketMovers(twelvedata.MarketMoversRequest{Market: ptr(twelvedata.MarketMoversMarketStocks)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect(t, "DateTime", movers.Values[0].DateTime.Time, time.Date(2024, 6, 14, 15, 59, 59, 0, newYork))
+	expect(t, "DateTime of an uncached listing", movers.Values[1].DateTime.Time, time.Date(2024, 6, 14, 15, 59, 59, 0, time.UTC))
+
+	summary, err := client.GetFundSummary(twelvedata.FundRequest{Type: ptr(twelvedata.FundTypeMutualFund), Symbol: ptr("VFIAX")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect(t, "ShareClassInceptionDate", summary.ShareClassInceptionDate.Time, time.Date(2000, 11, 13, 0, 0, 0, 0, newYork))
+	expect(t, "People[0].TenureSince", summary.People[0].TenureSince.Time, time.Date(2017, 11, 30, 0, 0, 0, 0, newYork))
+}
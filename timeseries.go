@@ -1,6 +1,8 @@
 package twelvedata
 
 import (
+	"bytes"
+	"context"
 	"time"
 
 	jsoniter "github.com/json-iterator/go"
@@ -46,9 +48,14 @@ type TimeSeriesRequest struct {
 	TimeZone      *string             // Timezone for the response (e.g. "America/New_York", "UTC"). Defaults to "Exchange"
 	Date          *time.Time          // Specific day to fetch data for (time is ignored)
 	StartDate     *time.Time          // Time when the series starts
+	FromEarliest  *bool               // When StartDate is nil, start from the earliest available bar (costs an extra call, see GetEarliestTimestamp)
 	EndDate       *time.Time          // Time when the series ends
 	PreviousClose *bool               // Include previous close price in the response (default is false)
 	Adjust        *string             // Adjusting mode for prices ("none", "dividends", "splits", "all"). Default is "none"
+	Format        *ResponseFormat     // Response format ("JSON" or "CSV"). CSV responses are smaller for large output sizes
+	Delimiter     *string             // Delimiter of CSV responses (default is ";")
+
+	PreserveDecimals *bool // Keep the exact decimals of prices in TDFloat.Decimal (defaults to Config.PreserveDecimals)
 }
 
 func (req TimeSeriesRequest) ToParams() (map[string]string, error) {
@@ -85,6 +92,10 @@ func (req TimeSeriesRequest) ToParams() (map[string]string, error) {
 	AddDateParam(params, "start_date", req.StartDate, "2006-01-02 15:04:05")
 	AddDateParam(params, "end_date", req.EndDate, "2006-01-02 15:04:05")
 
+	if err := addFormatParams(params, req.Format, req.Delimiter); err != nil {
+		return nil, err
+	}
+
 	return params, nil
 }
 
@@ -107,94 +118,219 @@ type TimeSeriesResponseMeta struct {
 
 type TimeSeriesCandle struct {
 	DateTime TDZonedTime `json:"datetime"`
-	Open     float64     `json:"open,string"`
-	Close    float64     `json:"close,string"`
-	High     float64     `json:"high,string"`
-	Low      float64     `json:"low,string"`
-	Volume   float64     `json:"volume,string"`
+	Open     TDFloat     `json:"open"`
+	Close    TDFloat     `json:"close"`
+	High     TDFloat     `json:"high"`
+	Low      TDFloat     `json:"low"`
+	Volume   TDFloat     `json:"volume"` // Not provided for some forex and crypto pairs
 }
 
 // UnmarshalJSON Parses JSON response, first taking the exchange timezone from the meta field (if present) and then
 // parsing each candle's datetime in the correct timezone.
 func (r *TimeSeriesResponse) UnmarshalJSON(data []byte) error {
-	type Alias TimeSeriesResponse
-	aux := &struct {
-		*Alias
-		Candles []jsoniter.RawMessage `json:"values"`
-	}{Alias: (*Alias)(r)}
+	return r.decode(jsoniter.ConfigDefault, data, nil)
+}
+
+// decode implements UnmarshalJSON with the given JSON configuration. The candles are parsed in loc, or in the exchange
+// timezone of the meta block when loc is nil.
+func (r *TimeSeriesResponse) decode(api jsoniter.API, data []byte, loc *time.Location) error {
+	decoded, err := decodeTimeSeriesJSON(api, data, loc)
+	if err != nil {
+		return err
+	}
 
-	if err := jsoniter.Unmarshal(data, aux); err != nil {
-		return errors.Wrap(err, "failed to unmarshal TimeSeriesResponse into Alias")
+	*r = *decoded
+	return nil
+}
+
+func (c *APIClient) GetTimeSeries(req TimeSeriesRequest) (candles *TimeSeriesResponse, err error) {
+	return c.GetTimeSeriesContext(context.Background(), req)
+}
+
+// GetTimeSeriesContext is GetTimeSeries with a context cancelling the requests and the waits between retries,
+// including the lookups of the earliest timestamp and the exchange timezone
+func (c *APIClient) GetTimeSeriesContext(ctx context.Context, req TimeSeriesRequest) (candles *TimeSeriesResponse, err error) {
+	params, loc, err := c.timeSeriesParams(ctx, &req)
+	if err != nil {
+		return nil, err
 	}
 
-	timezone, err := time.LoadLocation(r.Meta.ExchangeTimezone)
+	resp, err := c.Client.getContext(ctx, urlEndpointTimeSeries, params)
 	if err != nil {
-		return errors.Wrap(err, "failed to load exchange timezone")
+		return nil, errors.Wrap(err, "Error fetching time series data")
 	}
 
-	r.Candles = make([]TimeSeriesCandle, len(aux.Candles))
-	for i, rawCandle := range aux.Candles {
-		if err := r.unmarshalCandleWithTimezone(rawCandle, &r.Candles[i], timezone); err != nil {
-			return errors.Errorf("error unmarshaling value %d: %+v", i, err)
+	if isCSV(req.Format) {
+		var decoded []TimeSeriesCandle
+		err = c.Client.decode(resp, "CSV time series", func(body []byte) (err error) {
+			decoder := NewTimeSeriesCSVDecoder(bytes.NewReader(body), csvDelimiter(req.Delimiter), loc)
+			decoder.preserveDecimals = c.preservesDecimals(req.PreserveDecimals)
+			decoded, err = decoder.DecodeAll()
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		meta, err := c.csvTimeSeriesMeta(ctx, req)
+		if err != nil {
+			return nil, err
 		}
+
+		return &TimeSeriesResponse{Meta: meta, Candles: decoded}, nil
 	}
 
-	return nil
+	candles = &TimeSeriesResponse{}
+	err = c.Client.decode(resp, "time series", func(body []byte) error {
+		return candles.decode(c.jsonAPI(req.PreserveDecimals), body, loc)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if candles != nil && req.Symbol != nil && candles.Meta.ExchangeTimezone != "" {
+		c.timezones.Store(timezoneCacheKey(req), candles.Meta.ExchangeTimezone)
+	}
+
+	return candles, nil
 }
 
-// unmarshalCandleWithTimezone unmarshals a single candle from JSON data, applying the specified timezone to the datetime.
-// Supports time string formats: "2006-01-02 15:04:05" and "2006-01-02".
-func (r *TimeSeriesResponse) unmarshalCandleWithTimezone(data []byte, c *TimeSeriesCandle, tz *time.Location) error {
-	var temp struct {
-		DateTime string  `json:"datetime"`
-		Open     float64 `json:"open,string"`
-		Close    float64 `json:"close,string"`
-		High     float64 `json:"high,string"`
-		Low      float64 `json:"low,string"`
-		Volume   float64 `json:"volume,string"`
+// timeSeriesParams resolves FromEarliest into req.StartDate and returns the params of req with the timezone the
+// datetimes of the response are in. The location is nil when it comes from the meta block of a JSON response.
+func (c *APIClient) timeSeriesParams(ctx context.Context, req *TimeSeriesRequest) (params map[string]string, loc *time.Location, err error) {
+	if req.StartDate == nil && req.FromEarliest != nil && *req.FromEarliest {
+		earliest, err := c.getEarliestTimestamp(ctx, EarliestTimestampRequest{
+			Symbol:   req.Symbol,
+			FIGI:     req.FIGI,
+			ISIN:     req.ISIN,
+			CUSIP:    req.CUSIP,
+			Interval: req.Interval,
+			Exchange: req.Exchange,
+			MicCode:  req.MicCode,
+			TimeZone: req.TimeZone,
+		})
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "Error resolving earliest timestamp for time series")
+		}
+
+		if !isIntraday(*req.Interval) {
+			// Daily bars are dated at midnight, before the session open the earliest timestamp is at
+			earliest = time.Date(earliest.Year(), earliest.Month(), earliest.Day(), 0, 0, 0, 0, earliest.Location())
+		}
+
+		req.StartDate = &earliest
 	}
 
-	if err := jsoniter.Unmarshal(data, &temp); err != nil {
-		return err
+	params, err = req.ToParams()
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "Error converting TimeSeriesRequest to params")
 	}
 
-	var parsedTime time.Time
+	if isCSV(req.Format) {
+		// CSV responses have no meta block, so the timezone has to be known before the request
+		if loc, err = c.timeSeriesLocation(ctx, *req); err != nil {
+			return nil, nil, errors.Wrap(err, "Error resolving timezone for CSV time series")
+		}
+	} else if req.TimeZone != nil && *req.TimeZone != "Exchange" {
+		// The meta block always names the exchange timezone, even when the datetimes are in the request timezone
+		if loc, err = resolveLocation(req.TimeZone, ""); err != nil {
+			return nil, nil, errors.Wrap(err, "Error resolving timezone for time series")
+		}
+	}
 
-	parsedTime, _ = time.ParseInLocation("2006-01-02 15:04:05", temp.DateTime, tz)
+	return params, loc, nil
+}
 
-	if parsedTime.IsZero() {
-		parsedTime, _ = time.ParseInLocation("2006-01-02", temp.DateTime, tz)
+// errNoExchangeTimezone is returned when the meta block of a timezone lookup names no exchange timezone, which isn't
+// cached so that the next request looks it up again
+var errNoExchangeTimezone = errors.New("no exchange timezone in time series response")
+
+// timezoneCacheKey identifies the listing of a time series request in the timezone cache
+func timezoneCacheKey(req TimeSeriesRequest) string {
+	key := *req.Symbol
+	for _, field := range []*string{req.Exchange, req.MicCode, req.Country} {
+		key += "|"
+		if field != nil {
+			key += *field
+		}
 	}
 
-	if parsedTime.IsZero() {
-		return errors.Errorf("failed to parse datetime %s in timezone %s", temp.DateTime, tz)
+	return key
+}
+
+// timeSeriesLocation resolves the timezone the datetimes of a time series response are in: the request timezone
+// when set, otherwise the exchange timezone from the cache, looked up with a single candle JSON request on a miss
+func (c *APIClient) timeSeriesLocation(ctx context.Context, req TimeSeriesRequest) (*time.Location, error) {
+	if req.TimeZone != nil && *req.TimeZone != "Exchange" {
+		return time.LoadLocation(*req.TimeZone)
 	}
 
-	c.DateTime = TDZonedTime{Time: parsedTime}
-	c.Open = temp.Open
-	c.Close = temp.Close
-	c.High = temp.High
-	c.Low = temp.Low
-	c.Volume = temp.Volume
+	timezone, err := c.exchangeTimezone(ctx, req)
+	if err != nil {
+		return nil, err
+	}
 
-	return nil
+	return time.LoadLocation(timezone)
 }
 
-func (c *APIClient) GetTimeSeries(req TimeSeriesRequest) (candles *TimeSeriesResponse, err error) {
-	params, err := req.ToParams()
-	if err != nil {
-		return nil, errors.Wrap(err, "Error converting TimeSeriesRequest to params")
+// exchangeTimezone returns the exchange timezone of the listing of req from the cache, looked up with a single candle
+// JSON request on a miss
+func (c *APIClient) exchangeTimezone(ctx context.Context, req TimeSeriesRequest) (string, error) {
+	if timezone, ok := c.timezones.Load(timezoneCacheKey(req)); ok {
+		return timezone.(string), nil
+	}
+
+	outputSize := 1
+	lookup := TimeSeriesRequest{
+		Symbol:     req.Symbol,
+		FIGI:       req.FIGI,
+		ISIN:       req.ISIN,
+		CUSIP:      req.CUSIP,
+		Interval:   req.Interval,
+		Exchange:   req.Exchange,
+		MicCode:    req.MicCode,
+		Country:    req.Country,
+		Type:       req.Type,
+		OutputSize: &outputSize,
 	}
 
-	resp, err := c.Client.Get(urlEndpointTimeSeries, params)
+	resp, err := c.GetTimeSeriesContext(ctx, lookup)
 	if err != nil {
-		return nil, errors.Wrap(err, "Error fetching time series data")
+		return "", err
+	}
+
+	if resp.Meta.ExchangeTimezone == "" {
+		return "", errNoExchangeTimezone
 	}
 
-	err = jsoniter.Unmarshal(resp.Body(), &candles)
+	return resp.Meta.ExchangeTimezone, nil
+}
+
+// csvTimeSeriesMeta fills the meta block missing from CSV responses from the request. The exchange timezone is that
+// of the listing, whatever the timezone of the datetimes, so it is looked up when missing from the cache.
+func (c *APIClient) csvTimeSeriesMeta(ctx context.Context, req TimeSeriesRequest) (TimeSeriesResponseMeta, error) {
+	exchangeTimezone, err := c.exchangeTimezone(ctx, req)
 	if err != nil {
-		return nil, errors.Wrap(err, "Error unmarshalling time series response")
+		return TimeSeriesResponseMeta{}, errors.Wrap(err, "Error resolving exchange timezone for CSV time series")
 	}
 
-	return candles, nil
+	meta := TimeSeriesResponseMeta{
+		Symbol:           *req.Symbol,
+		Interval:         string(*req.Interval),
+		ExchangeTimezone: exchangeTimezone,
+	}
+
+	if req.Exchange != nil {
+		meta.Exchange = *req.Exchange
+	}
+
+	if req.MicCode != nil {
+		meta.MicCode = *req.MicCode
+	}
+
+	if req.Type != nil {
+		meta.Type = *req.Type
+	}
+
+	return meta, nil
 }
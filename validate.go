@@ -0,0 +1,420 @@
+package twelvedata
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// TradingSessions tells the validator when a market is open, so closed periods aren't reported as gaps
+type TradingSessions interface {
+	IsOpen(t time.Time) bool         // Whether the market is open at t
+	IsTradingDay(day time.Time) bool // Whether the market trades on the calendar day of day
+}
+
+// WeekdaySessions is a TradingSessions with the same session every Monday to Friday and no holidays
+type WeekdaySessions struct {
+	Location *time.Location // Exchange timezone. Defaults to the location of the checked time
+	Open     time.Duration  // Session open as an offset from midnight (e.g. 9h30m)
+	Close    time.Duration  // Session close as an offset from midnight (e.g. 16h)
+}
+
+func (s WeekdaySessions) in(t time.Time) time.Time {
+	if s.Location != nil {
+		return t.In(s.Location)
+	}
+	return t
+}
+
+func (s WeekdaySessions) IsTradingDay(day time.Time) bool {
+	weekday := s.in(day).Weekday()
+	return weekday != time.Saturday && weekday != time.Sunday
+}
+
+func (s WeekdaySessions) IsOpen(t time.Time) bool {
+	t = s.in(t)
+	return s.IsTradingDay(t) && !t.Before(atOffset(t, s.Open)) && t.Before(atOffset(t, s.Close))
+}
+
+// NextOpen returns the first session open strictly after t
+func (s WeekdaySessions) NextOpen(t time.Time) (time.Time, error) {
+	t = s.in(t)
+	for day := 0; day <= 7; day++ {
+		open := atOffset(t.AddDate(0, 0, day), s.Open)
+		if open.After(t) && s.IsTradingDay(open) {
+			return open, nil
+		}
+	}
+
+	return time.Time{}, errors.Errorf("no session opens within a week after %s", t)
+}
+
+// sessionOpener is implemented by the TradingSessions that know when the next session opens (WeekdaySessions and
+// Calendar). Intraday bars start over at each session open, so gap detection moves its grid there.
+type sessionOpener interface {
+	NextOpen(t time.Time) (time.Time, error)
+}
+
+// ValidateOptions controls the checks run by TimeSeriesResponse.Validate
+type ValidateOptions struct {
+	Sessions         TradingSessions // Market sessions used for gap detection. Nil treats the market as always open (e.g. crypto)
+	OutlierThreshold float64         // Robust z-score of the close-to-close return above which a candle is an outlier. Zero disables the check
+	IgnoreZeroVolume bool            // Don't report zero volume candles (forex and indices have no volume)
+}
+
+// Gap is a run of consecutive missing bars
+type Gap struct {
+	From    time.Time // First missing bar
+	To      time.Time // Last missing bar
+	Missing int       // Number of missing bars
+}
+
+// CandleIssue is a problem with a single candle
+type CandleIssue struct {
+	DateTime time.Time
+	Reason   string
+}
+
+// DataQualityReport is the result of validating a time series
+type DataQualityReport struct {
+	Candles      int           // Number of candles checked
+	Gaps         []Gap         // Missing bars while the market was open
+	Duplicates   []time.Time   // Timestamps that appear more than once
+	OutOfOrder   []time.Time   // Candles that break the ordering of the series
+	Inconsistent []CandleIssue // Candles with impossible OHLC values (e.g. high < low, close outside the range)
+	ZeroVolume   []time.Time   // Candles without any volume
+	Outliers     []CandleIssue // Candles with an abnormal close-to-close return
+
+	sessions TradingSessions
+}
+
+// OK reports whether no issue was found
+func (r *DataQualityReport) OK() bool {
+	return len(r.Gaps) == 0 && len(r.Duplicates) == 0 && len(r.OutOfOrder) == 0 && len(r.Inconsistent) == 0 &&
+		len(r.ZeroVolume) == 0 && len(r.Outliers) == 0
+}
+
+// Validate checks the candles against the series interval and the trading sessions and reports gaps, duplicates,
+// out of order candles, OHLC inconsistencies, zero volume candles and outliers. The response is not modified.
+func (r *TimeSeriesResponse) Validate(opts ValidateOptions) (*DataQualityReport, error) {
+	interval := TimeSeriesInterval(r.Meta.Interval)
+	if _, ok := intervalDuration(interval); !ok && interval != TimeSeriesInterval1Month {
+		return nil, errors.Errorf("unsupported interval %q", r.Meta.Interval)
+	}
+
+	report := &DataQualityReport{Candles: len(r.Candles), sessions: opts.Sessions}
+
+	descending := len(r.Candles) > 1 && r.Candles[0].DateTime.After(r.Candles[len(r.Candles)-1].DateTime.Time)
+	for i := 1; i < len(r.Candles); i++ {
+		prev, cur := r.Candles[i-1].DateTime.Time, r.Candles[i].DateTime.Time
+		if (descending && cur.After(prev)) || (!descending && cur.Before(prev)) {
+			report.OutOfOrder = append(report.OutOfOrder, cur)
+		}
+	}
+
+	for _, candle := range r.Candles {
+		if reason := ohlcIssue(candle); reason != "" {
+			report.Inconsistent = append(report.Inconsistent, CandleIssue{DateTime: candle.DateTime.Time, Reason: reason})
+		}
+
+		if candle.Volume.Or(0) == 0 && !opts.IgnoreZeroVolume {
+			report.ZeroVolume = append(report.ZeroVolume, candle.DateTime.Time)
+		}
+	}
+
+	sorted := sortedCandles(r.Candles)
+
+	unique := sorted[:0:0]
+	for i, candle := range sorted {
+		if i > 0 && candle.DateTime.Equal(sorted[i-1].DateTime.Time) {
+			if len(report.Duplicates) == 0 || !report.Duplicates[len(report.Duplicates)-1].Equal(candle.DateTime.Time) {
+				report.Duplicates = append(report.Duplicates, candle.DateTime.Time)
+			}
+			continue
+		}
+		unique = append(unique, candle)
+	}
+
+	for i := 1; i < len(unique); i++ {
+		report.Gaps = append(report.Gaps, findGaps(unique[i-1].DateTime.Time, unique[i].DateTime.Time, interval, opts.Sessions)...)
+	}
+
+	if opts.OutlierThreshold > 0 {
+		report.Outliers = findOutliers(unique, opts.OutlierThreshold)
+	}
+
+	return report, nil
+}
+
+func ohlcIssue(candle TimeSeriesCandle) string {
+	if !candle.Open.Valid || !candle.High.Valid || !candle.Low.Valid || !candle.Close.Valid {
+		return "price is missing"
+	}
+
+	open, high, low, close := candle.Open.Float64, candle.High.Float64, candle.Low.Float64, candle.Close.Float64
+	switch {
+	case high < low:
+		return "high is below low"
+	case open > high || open < low:
+		return "open is outside the high-low range"
+	case close > high || close < low:
+		return "close is outside the high-low range"
+	case low <= 0:
+		return "price is not positive"
+	case candle.Volume.Or(0) < 0:
+		return "volume is negative"
+	}
+
+	return ""
+}
+
+// sortedCandles returns a copy of candles in ascending order
+func sortedCandles(candles []TimeSeriesCandle) []TimeSeriesCandle {
+	sorted := make([]TimeSeriesCandle, len(candles))
+	copy(sorted, candles)
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].DateTime.Before(sorted[j].DateTime.Time)
+	})
+
+	return sorted
+}
+
+// nextBar returns the timestamp of the bar following t
+func nextBar(t time.Time, interval TimeSeriesInterval) time.Time {
+	return candleEnd(t, interval)
+}
+
+// expectedBar reports whether a bar at t is expected given the sessions
+func expectedBar(t time.Time, interval TimeSeriesInterval, sessions TradingSessions) bool {
+	switch {
+	case sessions == nil:
+		return true
+	case isIntraday(interval):
+		return sessions.IsOpen(t)
+	case interval == TimeSeriesInterval1Day:
+		return sessions.IsTradingDay(t)
+	}
+
+	// Weekly and monthly bars are expected for every period
+	return true
+}
+
+// barAfterClose returns the bar following t when the market is closed at t. Intraday bars start over at the next
+// session open when the sessions know it, as intervals like 45min and 5h don't divide the time between sessions.
+func barAfterClose(t time.Time, interval TimeSeriesInterval, sessions TradingSessions) time.Time {
+	if opener, ok := sessions.(sessionOpener); ok && isIntraday(interval) {
+		if open, err := opener.NextOpen(t); err == nil {
+			return open
+		}
+	}
+
+	return nextBar(t, interval)
+}
+
+// findGaps returns the runs of expected bars strictly between prev and next
+func findGaps(prev, next time.Time, interval TimeSeriesInterval, sessions TradingSessions) []Gap {
+	var gaps []Gap
+	var current *Gap
+
+	for t := nextBar(prev, interval); t.Before(next); {
+		if !expectedBar(t, interval, sessions) {
+			current = nil
+			t = barAfterClose(t, interval, sessions)
+			continue
+		}
+
+		if current == nil {
+			gaps = append(gaps, Gap{From: t})
+			current = &gaps[len(gaps)-1]
+		}
+
+		current.To = t
+		current.Missing++
+		t = nextBar(t, interval)
+	}
+
+	return gaps
+}
+
+// findOutliers flags candles whose log return deviates from the median by more than threshold robust standard
+// deviations (median absolute deviation scaled to a normal distribution)
+func findOutliers(sorted []TimeSeriesCandle, threshold float64) []CandleIssue {
+	if len(sorted) < 3 {
+		return nil
+	}
+
+	returns := make([]float64, 0, len(sorted)-1)
+	for i := 1; i < len(sorted); i++ {
+		prev, cur := sorted[i-1].Close.Or(0), sorted[i].Close.Or(0)
+		if prev <= 0 || cur <= 0 {
+			returns = append(returns, 0)
+			continue
+		}
+		returns = append(returns, math.Log(cur/prev))
+	}
+
+	med := median(returns)
+
+	deviations := make([]float64, len(returns))
+	for i, r := range returns {
+		deviations[i] = math.Abs(r - med)
+	}
+
+	mad := 1.4826 * median(deviations)
+	if mad == 0 {
+		return nil
+	}
+
+	var outliers []CandleIssue
+	for i, r := range returns {
+		if score := math.Abs(r-med) / mad; score > threshold {
+			outliers = append(outliers, CandleIssue{
+				DateTime: sorted[i+1].DateTime.Time,
+				Reason:   "close-to-close return is an outlier",
+			})
+		}
+	}
+
+	return outliers
+}
+
+func median(values []float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	n := len(sorted)
+	if n%2 == 1 {
+		return sorted[n/2]
+	}
+	return (sorted[n/2-1] + sorted[n/2]) / 2
+}
+
+// RepairStrategy selects how TimeSeriesResponse.Repair and APIClient.RepairTimeSeries fix the issues of a report
+type RepairStrategy string
+
+const (
+	RepairDrop        RepairStrategy = "drop"         // Drop duplicate, inconsistent and outlier candles and leave gaps as they are
+	RepairForwardFill RepairStrategy = "forward_fill" // Like RepairDrop, then fill gaps with flat candles at the previous close and zero volume
+	RepairRefetch     RepairStrategy = "refetch"      // Like RepairDrop, then fetch the candles of each gap again (APIClient.RepairTimeSeries only)
+)
+
+// Repair returns a copy of the response with the issues of the report fixed using the given strategy. Candles are
+// sorted in the order of the original series. RepairRefetch needs an API client, see APIClient.RepairTimeSeries.
+func (r *TimeSeriesResponse) Repair(report *DataQualityReport, strategy RepairStrategy) (*TimeSeriesResponse, error) {
+	switch strategy {
+	case RepairDrop, RepairForwardFill:
+	case RepairRefetch:
+		return nil, errors.New("refetch repair needs an API client, use APIClient.RepairTimeSeries")
+	default:
+		return nil, errors.Errorf("unknown repair strategy %q", strategy)
+	}
+
+	candles := dropBadCandles(r.Candles, report)
+	if strategy == RepairForwardFill {
+		candles = forwardFill(candles, TimeSeriesInterval(r.Meta.Interval), report.sessions)
+	}
+
+	return &TimeSeriesResponse{Meta: r.Meta, Candles: inOrderOf(r.Candles, candles)}, nil
+}
+
+// RepairTimeSeries repairs a response previously fetched with req. With RepairRefetch the candles of every gap are
+// requested again and merged in; bars the API still doesn't return stay missing.
+func (c *APIClient) RepairTimeSeries(req TimeSeriesRequest, resp *TimeSeriesResponse, report *DataQualityReport, strategy RepairStrategy) (*TimeSeriesResponse, error) {
+	if strategy != RepairRefetch {
+		return resp.Repair(report, strategy)
+	}
+
+	candles := dropBadCandles(resp.Candles, report)
+	for _, gap := range report.Gaps {
+		gapReq := req
+		gapReq.Date = nil
+		gapReq.FromEarliest = nil
+		gapEnd := nextBar(gap.To, TimeSeriesInterval(resp.Meta.Interval))
+		gapReq.StartDate = &gap.From
+		gapReq.EndDate = &gapEnd
+
+		outputSize := gap.Missing
+		gapReq.OutputSize = &outputSize
+
+		refetched, err := c.GetTimeSeries(gapReq)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Error refetching time series gap from %s to %s", gap.From, gap.To)
+		}
+
+		candles = append(candles, refetched.Candles...)
+	}
+
+	candles = dropBadCandles(candles, &DataQualityReport{Inconsistent: report.Inconsistent, Outliers: report.Outliers})
+
+	return &TimeSeriesResponse{Meta: resp.Meta, Candles: inOrderOf(resp.Candles, candles)}, nil
+}
+
+// dropBadCandles returns the candles sorted ascending without duplicates and without the candles flagged as
+// inconsistent or outliers in the report
+func dropBadCandles(candles []TimeSeriesCandle, report *DataQualityReport) []TimeSeriesCandle {
+	bad := make(map[int64]bool)
+	for _, issue := range report.Inconsistent {
+		bad[issue.DateTime.UnixNano()] = true
+	}
+	for _, issue := range report.Outliers {
+		bad[issue.DateTime.UnixNano()] = true
+	}
+
+	sorted := sortedCandles(candles)
+
+	kept := sorted[:0]
+	for i, candle := range sorted {
+		if bad[candle.DateTime.UnixNano()] {
+			continue
+		}
+
+		if i > 0 && len(kept) > 0 && kept[len(kept)-1].DateTime.Equal(candle.DateTime.Time) {
+			continue
+		}
+
+		kept = append(kept, candle)
+	}
+
+	return kept
+}
+
+// forwardFill inserts a flat candle at the previous close for every missing bar, including the bars of candles that
+// were dropped as bad
+func forwardFill(sorted []TimeSeriesCandle, interval TimeSeriesInterval, sessions TradingSessions) []TimeSeriesCandle {
+	filled := make([]TimeSeriesCandle, 0, len(sorted))
+	for i, candle := range sorted {
+		if i > 0 {
+			prevClose := sorted[i-1].Close
+			for _, gap := range findGaps(sorted[i-1].DateTime.Time, candle.DateTime.Time, interval, sessions) {
+				for t := gap.From; !t.After(gap.To); t = nextBar(t, interval) {
+					filled = append(filled, TimeSeriesCandle{
+						DateTime: TDZonedTime{Time: t},
+						Open:     prevClose,
+						High:     prevClose,
+						Low:      prevClose,
+						Close:    prevClose,
+						Volume:   NewTDFloat(0),
+					})
+				}
+			}
+		}
+
+		filled = append(filled, candle)
+	}
+
+	return filled
+}
+
+// inOrderOf returns the ascending candles in the order (ascending or descending) of original
+func inOrderOf(original, ascending []TimeSeriesCandle) []TimeSeriesCandle {
+	if len(original) > 1 && original[0].DateTime.After(original[len(original)-1].DateTime.Time) {
+		for i, j := 0, len(ascending)-1; i < j; i, j = i+1, j-1 {
+			ascending[i], ascending[j] = ascending[j], ascending[i]
+		}
+	}
+
+	return ascending
+}
@@ -0,0 +1,374 @@
+package twelvedata_test
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jonnotjohn/twelvedata-go"
+)
+
+var newYork = mustLoadLocation("America/New_York")
+
+func mustLoadLocation(name string) *time.Location {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		panic(err)
+	}
+	return loc
+}
+
+// nyse trades from 9:30 to 16:00 in New York
+var nyse = twelvedata.WeekdaySessions{Location: newYork, Open: 9*time.Hour + 30*time.Minute, Close: 16 * time.Hour}
+
+// at returns the time of a "2006-01-02 15:04" wall clock in New York
+func at(wallClock string) time.Time {
+	t, err := time.ParseInLocation("2006-01-02 15:04", wallClock, newYork)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// bar returns a consistent candle at wallClock closing at price
+func bar(wallClock string, price float64) twelvedata.TimeSeriesCandle {
+	return twelvedata.TimeSeriesCandle{
+		DateTime: twelvedata.TDZonedTime{Time: at(wallClock)},
+		Open:     twelvedata.NewTDFloat(price),
+		High:     twelvedata.NewTDFloat(price + 1),
+		Low:      twelvedata.NewTDFloat(price - 1),
+		Close:    twelvedata.NewTDFloat(price),
+		Volume:   twelvedata.NewTDFloat(100),
+	}
+}
+
+// with returns the candle after applying change
+func with(candle twelvedata.TimeSeriesCandle, change func(c *twelvedata.TimeSeriesCandle)) twelvedata.TimeSeriesCandle {
+	change(&candle)
+	return candle
+}
+
+func series(interval twelvedata.TimeSeriesInterval, candles ...twelvedata.TimeSeriesCandle) *twelvedata.TimeSeriesResponse {
+	return &twelvedata.TimeSeriesResponse{
+		Meta:    twelvedata.TimeSeriesResponseMeta{Symbol: "AAPL", Interval: string(interval), ExchangeTimezone: "America/New_York"},
+		Candles: candles,
+	}
+}
+
+// describe lists the issues of a report as readable lines
+func describe(report *twelvedata.DataQualityReport) []string {
+	format := func(t time.Time) string { return t.In(newYork).Format("2006-01-02 15:04") }
+
+	var lines []string
+	for _, gap := range report.Gaps {
+		lines = append(lines, fmt.Sprintf("gap %s to %s (%d)", format(gap.From), format(gap.To), gap.Missing))
+	}
+	for _, t := range report.Duplicates {
+		lines = append(lines, "duplicate "+format(t))
+	}
+	for _, t := range report.OutOfOrder {
+		lines = append(lines, "out of order "+format(t))
+	}
+	for _, issue := range report.Inconsistent {
+		lines = append(lines, fmt.Sprintf("inconsistent %s: %s", format(issue.DateTime), issue.Reason))
+	}
+	for _, t := range report.ZeroVolume {
+		lines = append(lines, "zero volume "+format(t))
+	}
+	for _, issue := range report.Outliers {
+		lines = append(lines, "outlier "+format(issue.DateTime))
+	}
+
+	return lines
+}
+
+// closes lists the wall clocks and closes of candles
+func closes(candles []twelvedata.TimeSeriesCandle) []string {
+	lines := make([]string, len(candles))
+	for i, candle := range candles {
+		lines[i] = fmt.Sprintf("%s %g", candle.DateTime.In(newYork).Format("2006-01-02 15:04"), candle.Close.Float64)
+	}
+
+	return lines
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		series *twelvedata.TimeSeriesResponse
+		opts   twelvedata.ValidateOptions
+		want   []string
+	}{
+		{
+			name:   "clean across weekend and DST change",
+			series: series(twelvedata.TimeSeriesInterval1Hour, bar("2024-03-08 14:30", 10), bar("2024-03-08 15:30", 11), bar("2024-03-11 09:30", 12), bar("2024-03-11 10:30", 13)),
+			opts:   twelvedata.ValidateOptions{Sessions: nyse},
+		},
+		{
+			name:   "gap within session",
+			series: series(twelvedata.TimeSeriesInterval1Hour, bar("2024-03-08 09:30", 10), bar("2024-03-08 12:30", 11)),
+			opts:   twelvedata.ValidateOptions{Sessions: nyse},
+			want:   []string{"gap 2024-03-08 10:30 to 2024-03-08 11:30 (2)"},
+		},
+		{
+			name:   "gaps across session boundary",
+			series: series(twelvedata.TimeSeriesInterval1Hour, bar("2024-03-08 14:30", 10), bar("2024-03-11 10:30", 11)),
+			opts:   twelvedata.ValidateOptions{Sessions: nyse},
+			want:   []string{"gap 2024-03-08 15:30 to 2024-03-08 15:30 (1)", "gap 2024-03-11 09:30 to 2024-03-11 09:30 (1)"},
+		},
+		{
+			name:   "45min gap at the session open",
+			series: series(twelvedata.TimeSeriesInterval45Min, bar("2024-06-13 14:30", 10), bar("2024-06-13 15:15", 11), bar("2024-06-14 10:15", 12)),
+			opts:   twelvedata.ValidateOptions{Sessions: nyse},
+			want:   []string{"gap 2024-06-14 09:30 to 2024-06-14 09:30 (1)"},
+		},
+		{
+			name:   "5h gap of a whole day",
+			series: series(twelvedata.TimeSeriesInterval5Hour, bar("2024-06-12 09:30", 10), bar("2024-06-12 14:30", 11), bar("2024-06-14 09:30", 12)),
+			opts:   twelvedata.ValidateOptions{Sessions: nyse},
+			want:   []string{"gap 2024-06-13 09:30 to 2024-06-13 14:30 (2)"},
+		},
+		{
+			name:   "gap without sessions",
+			series: series(twelvedata.TimeSeriesInterval1Hour, bar("2024-03-08 15:30", 10), bar("2024-03-08 18:30", 11)),
+			want:   []string{"gap 2024-03-08 16:30 to 2024-03-08 17:30 (2)"},
+		},
+		{
+			name:   "daily gap skips weekend",
+			series: series(twelvedata.TimeSeriesInterval1Day, bar("2024-03-07 00:00", 10), bar("2024-03-08 00:00", 11), bar("2024-03-12 00:00", 12)),
+			opts:   twelvedata.ValidateOptions{Sessions: nyse},
+			want:   []string{"gap 2024-03-11 00:00 to 2024-03-11 00:00 (1)"},
+		},
+		{
+			name:   "duplicate timestamps",
+			series: series(twelvedata.TimeSeriesInterval1Hour, bar("2024-03-08 09:30", 10), bar("2024-03-08 10:30", 11), bar("2024-03-08 10:30", 11), bar("2024-03-08 10:30", 12), bar("2024-03-08 11:30", 13)),
+			opts:   twelvedata.ValidateOptions{Sessions: nyse},
+			want:   []string{"duplicate 2024-03-08 10:30"},
+		},
+		{
+			name:   "out of order",
+			series: series(twelvedata.TimeSeriesInterval1Hour, bar("2024-03-08 09:30", 10), bar("2024-03-08 11:30", 11), bar("2024-03-08 10:30", 12)),
+			opts:   twelvedata.ValidateOptions{Sessions: nyse},
+			want:   []string{"out of order 2024-03-08 10:30"},
+		},
+		{
+			name:   "descending order",
+			series: series(twelvedata.TimeSeriesInterval1Hour, bar("2024-03-08 11:30", 10), bar("2024-03-08 10:30", 11), bar("2024-03-08 09:30", 12)),
+			opts:   twelvedata.ValidateOptions{Sessions: nyse},
+		},
+		{
+			name: "inverted high and low",
+			series: series(twelvedata.TimeSeriesInterval1Hour, bar("2024-03-08 09:30", 10), with(bar("2024-03-08 10:30", 11), func(c *twelvedata.TimeSeriesCandle) {
+				c.High, c.Low = c.Low, c.High
+			})),
+			opts: twelvedata.ValidateOptions{Sessions: nyse},
+			want: []string{"inconsistent 2024-03-08 10:30: high is below low"},
+		},
+		{
+			name: "close outside range",
+			series: series(twelvedata.TimeSeriesInterval1Hour, with(bar("2024-03-08 09:30", 10), func(c *twelvedata.TimeSeriesCandle) {
+				c.Close = twelvedata.NewTDFloat(20)
+			})),
+			opts: twelvedata.ValidateOptions{Sessions: nyse},
+			want: []string{"inconsistent 2024-03-08 09:30: close is outside the high-low range"},
+		},
+		{
+			name: "missing price",
+			series: series(twelvedata.TimeSeriesInterval1Hour, with(bar("2024-03-08 09:30", 10), func(c *twelvedata.TimeSeriesCandle) {
+				c.Open = twelvedata.TDFloat{}
+			})),
+			opts: twelvedata.ValidateOptions{Sessions: nyse},
+			want: []string{"inconsistent 2024-03-08 09:30: price is missing"},
+		},
+		{
+			name: "zero and missing volume",
+			series: series(twelvedata.TimeSeriesInterval1Hour,
+				with(bar("2024-03-08 09:30", 10), func(c *twelvedata.TimeSeriesCandle) { c.Volume = twelvedata.NewTDFloat(0) }),
+				with(bar("2024-03-08 10:30", 11), func(c *twelvedata.TimeSeriesCandle) { c.Volume = twelvedata.TDFloat{} }),
+			),
+			opts: twelvedata.ValidateOptions{Sessions: nyse},
+			want: []string{"zero volume 2024-03-08 09:30", "zero volume 2024-03-08 10:30"},
+		},
+		{
+			name: "ignored zero volume",
+			series: series(twelvedata.TimeSeriesInterval1Hour,
+				with(bar("2024-03-08 09:30", 10), func(c *twelvedata.TimeSeriesCandle) { c.Volume = twelvedata.NewTDFloat(0) }),
+			),
+			opts: twelvedata.ValidateOptions{Sessions: nyse, IgnoreZeroVolume: true},
+		},
+		{
+			name: "negative volume",
+			series: series(twelvedata.TimeSeriesInterval1Hour,
+				with(bar("2024-03-08 09:30", 10), func(c *twelvedata.TimeSeriesCandle) { c.Volume = twelvedata.NewTDFloat(-5) }),
+			),
+			opts: twelvedata.ValidateOptions{Sessions: nyse},
+			want: []string{"inconsistent 2024-03-08 09:30: volume is negative"},
+		},
+		{
+			name: "outlier",
+			series: series(twelvedata.TimeSeriesInterval1Hour,
+				bar("2024-03-08 09:30", 100), bar("2024-03-08 10:30", 101), bar("2024-03-08 11:30", 100),
+				bar("2024-03-08 12:30", 101), bar("2024-03-08 13:30", 150), bar("2024-03-08 14:30", 151),
+			),
+			opts: twelvedata.ValidateOptions{Sessions: nyse, OutlierThreshold: 5},
+			want: []string{"outlier 2024-03-08 13:30"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report, err := tt.series.Validate(tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := describe(report); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Validate() = %q, want %q", got, tt.want)
+			}
+
+			if report.OK() != (len(tt.want) == 0) {
+				t.Errorf("OK() = %v with issues %q", report.OK(), tt.want)
+			}
+
+			if report.Candles != len(tt.series.Candles) {
+				t.Errorf("Candles = %d, want %d", report.Candles, len(tt.series.Candles))
+			}
+		})
+	}
+}
+
+func TestValidateUnsupportedInterval(t *testing.T) {
+	resp := series("3min", bar("2024-03-08 09:30", 10))
+	if _, err := resp.Validate(twelvedata.ValidateOptions{}); err == nil {
+		t.Error("Validate() succeeded with an unsupported interval")
+	}
+}
+
+func TestRepair(t *testing.T) {
+	inverted := func(c *twelvedata.TimeSeriesCandle) { c.High, c.Low = c.Low, c.High }
+
+	tests := []struct {
+		name     string
+		series   *twelvedata.TimeSeriesResponse
+		strategy twelvedata.RepairStrategy
+		want     []string
+	}{
+		{
+			name:     "drop duplicates and inconsistent candles",
+			series:   series(twelvedata.TimeSeriesInterval1Hour, bar("2024-03-08 09:30", 10), bar("2024-03-08 10:30", 11), bar("2024-03-08 10:30", 11), with(bar("2024-03-08 11:30", 12), inverted), bar("2024-03-08 12:30", 13)),
+			strategy: twelvedata.RepairDrop,
+			want:     []string{"2024-03-08 09:30 10", "2024-03-08 10:30 11", "2024-03-08 12:30 13"},
+		},
+		{
+			name:     "drop keeps descending order",
+			series:   series(twelvedata.TimeSeriesInterval1Hour, bar("2024-03-08 12:30", 13), with(bar("2024-03-08 11:30", 12), inverted), bar("2024-03-08 09:30", 10)),
+			strategy: twelvedata.RepairDrop,
+			want:     []string{"2024-03-08 12:30 13", "2024-03-08 09:30 10"},
+		},
+		{
+			name:     "forward fill gaps and dropped candles",
+			series:   series(twelvedata.TimeSeriesInterval1Hour, bar("2024-03-08 09:30", 10), with(bar("2024-03-08 10:30", 11), inverted), bar("2024-03-08 12:30", 13)),
+			strategy: twelvedata.RepairForwardFill,
+			want:     []string{"2024-03-08 09:30 10", "2024-03-08 10:30 10", "2024-03-08 11:30 10", "2024-03-08 12:30 13"},
+		},
+		{
+			name:     "forward fill across session boundary",
+			series:   series(twelvedata.TimeSeriesInterval1Hour, bar("2024-03-08 14:30", 10), bar("2024-03-11 10:30", 11)),
+			strategy: twelvedata.RepairForwardFill,
+			want:     []string{"2024-03-08 14:30 10", "2024-03-08 15:30 10", "2024-03-11 09:30 10", "2024-03-11 10:30 11"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report, err := tt.series.Validate(twelvedata.ValidateOptions{Sessions: nyse})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			repaired, err := tt.series.Repair(report, tt.strategy)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := closes(repaired.Candles); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Repair() = %q, want %q", got, tt.want)
+			}
+
+			if repaired.Meta != tt.series.Meta {
+				t.Errorf("Repair() meta = %+v, want %+v", repaired.Meta, tt.series.Meta)
+			}
+		})
+	}
+}
+
+func TestRepairStrategyErrors(t *testing.T) {
+	resp := series(twelvedata.TimeSeriesInterval1Hour, bar("2024-03-08 09:30", 10))
+	report, err := resp.Validate(twelvedata.ValidateOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, strategy := range []twelvedata.RepairStrategy{twelvedata.RepairRefetch, "interpolate"} {
+		if _, err := resp.Repair(report, strategy); err == nil {
+			t.Errorf("Repair(%q) succeeded", strategy)
+		}
+	}
+}
+
+func TestRepairTimeSeriesRefetch(t *testing.T) {
+	var requests []map[string]string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/time_series", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		requests = append(requests, map[string]string{
+			"start_date": query.Get("start_date"),
+			"end_date":   query.Get("end_date"),
+			"outputsize": query.Get("outputsize"),
+		})
+
+		// The API still misses the bars of the later gaps
+		values := `[]`
+		if query.Get("start_date") == "2024-03-08 10:30:00" {
+			values = `[{"datetime":"2024-03-08 11:30:00","open":"12","high":"13","low":"11","close":"12","volume":"100"},` +
+				`{"datetime":"2024-03-08 10:30:00","open":"11","high":"12","low":"10","close":"11","volume":"100"}]`
+		}
+		w.Write([]byte(`{"meta":{"symbol":"AAPL","interval":"1h","exchange_timezone":"America/New_York"},"values":` + values + `,"status":"ok"}`))
+	})
+
+	client := handlerClient(t, mux)
+
+	resp := series(twelvedata.TimeSeriesInterval1Hour,
+		bar("2024-03-08 09:30", 10), bar("2024-03-08 12:30", 13), bar("2024-03-08 14:30", 15), bar("2024-03-11 09:30", 16),
+	)
+	report, err := resp.Validate(twelvedata.ValidateOptions{Sessions: nyse})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	symbol := "AAPL"
+	interval := twelvedata.TimeSeriesInterval1Hour
+	repaired, err := client.RepairTimeSeries(twelvedata.TimeSeriesRequest{Symbol: &symbol, Interval: &interval}, resp, report, twelvedata.RepairRefetch)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantRequests := []map[string]string{
+		{"start_date": "2024-03-08 10:30:00", "end_date": "2024-03-08 12:30:00", "outputsize": "2"},
+		{"start_date": "2024-03-08 13:30:00", "end_date": "2024-03-08 14:30:00", "outputsize": "1"},
+		{"start_date": "2024-03-08 15:30:00", "end_date": "2024-03-08 16:30:00", "outputsize": "1"},
+	}
+	if !reflect.DeepEqual(requests, wantRequests) {
+		t.Errorf("requests = %v, want %v", requests, wantRequests)
+	}
+
+	want := []string{
+		"2024-03-08 09:30 10", "2024-03-08 10:30 11", "2024-03-08 11:30 12", "2024-03-08 12:30 13", "2024-03-08 14:30 15",
+		"2024-03-11 09:30 16",
+	}
+	if got := closes(repaired.Candles); !reflect.DeepEqual(got, want) {
+		t.Errorf("RepairTimeSeries() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,327 @@
+// Package watch polls the quotes of a set of symbols and publishes an event whenever the price, the volume or the
+// trading session of a symbol changes. It stands in for the WebSocket API on plans without access to it.
+package watch
+
+import (
+	"context"
+	"iter"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jonnotjohn/twelvedata-go"
+	"github.com/pkg/errors"
+)
+
+const (
+	intervalDefault       = time.Minute
+	closedIntervalDefault = 15 * time.Minute
+	bufferDefault         = 64
+)
+
+// Change tells what changed in an Event
+type Change uint8
+
+const (
+	ChangePrice   Change = 1 << iota // The price changed
+	ChangeVolume                     // The volume of the day changed
+	ChangeSession                    // The market opened or closed
+)
+
+// Event is published when the price, the volume or the session state of a symbol changed
+type Event struct {
+	Symbol       string
+	Time         time.Time          // When the change was observed
+	Price        twelvedata.TDFloat // Latest price
+	Volume       twelvedata.TDFloat // Volume of the day, as of the last quote
+	IsMarketOpen bool
+	Changes      Change            // What changed since the previous event of the symbol. The first event has all set
+	Quote        *twelvedata.Quote // Quote the event comes from, nil when it comes from a price poll
+}
+
+// Tick returns the price of the event as a tick for a twelvedata.CandleBuilder. The volume of the day doesn't tell
+// the volume of the tick, so it is left out.
+func (e Event) Tick() twelvedata.Tick {
+	return twelvedata.Tick{Time: e.Time, Price: e.Price.Float64}
+}
+
+// Client is the part of *twelvedata.APIClient a Watcher uses
+type Client interface {
+	FetchMany(ctx context.Context, requests []twelvedata.FetchRequest, opts twelvedata.FetchOptions) iter.Seq[twelvedata.FetchResult]
+	GetMarketState(req twelvedata.MarketStateRequest) ([]twelvedata.MarketState, error)
+}
+
+var _ Client = (*twelvedata.APIClient)(nil)
+
+// Options configures a Watcher. The zero value polls quotes every minute.
+type Options struct {
+	Interval       time.Duration            // Time between polls of the symbols whose market is open (default 1 minute)
+	ClosedInterval time.Duration            // Time between checks whether closed markets opened (default 15 minutes)
+	Prices         bool                     // Poll /price between the quotes refreshing volume and session every ClosedInterval
+	Budget         *twelvedata.CreditBudget // Credits to stay within, shared with the other users of the API key
+	BatchSize      int                      // Most symbols per request (default and maximum 120)
+	Buffer         int                      // Events buffered per subscriber (default 64)
+	OnError        func(symbol string, err error)
+}
+
+// symbolState is the last observed state of a watched symbol
+type symbolState struct {
+	req     twelvedata.QuoteRequest
+	known   bool // Observed at least once
+	failed  bool // The first polls failed, so the symbol is only polled with the checks of closed markets
+	price   twelvedata.TDFloat
+	volume  twelvedata.TDFloat
+	open    bool
+	micCode string
+	name    string // Exchange of the listing
+}
+
+// Watcher polls the quotes of symbols and publishes their changes to its subscribers.
+//
+// Symbols whose market is open are polled every Options.Interval, all in batch requests. When a quote reports the
+// market closed, the symbol is paused: every Options.ClosedInterval a single market state request tells which
+// exchanges opened again, and only their symbols are polled. With Options.Prices, open symbols are polled with the
+// /price endpoint and their quotes, which carry the volume and the session state, are refreshed every
+// Options.ClosedInterval. Symbols whose first polls fail (e.g. unknown symbols) are retried every
+// Options.ClosedInterval too.
+type Watcher struct {
+	client Client
+	opts   Options
+	states []symbolState
+
+	mu          sync.Mutex
+	subscribers []chan Event
+	running     bool
+	stopped     bool
+}
+
+// New creates a watcher of the quotes of requests. Start it with Run.
+func New(client Client, requests []twelvedata.QuoteRequest, opts Options) *Watcher {
+	if opts.Interval <= 0 {
+		opts.Interval = intervalDefault
+	}
+
+	if opts.ClosedInterval <= 0 {
+		opts.ClosedInterval = closedIntervalDefault
+	}
+
+	if opts.Buffer <= 0 {
+		opts.Buffer = bufferDefault
+	}
+
+	states := make([]symbolState, len(requests))
+	for i, req := range requests {
+		states[i].req = req
+	}
+
+	return &Watcher{client: client, opts: opts, states: states}
+}
+
+// Subscribe returns a channel receiving the events of the watcher, closed when Run returns. Subscribers that don't
+// keep up delay the polls once their buffer is full.
+func (w *Watcher) Subscribe() <-chan Event {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	events := make(chan Event, w.opts.Buffer)
+	if w.stopped {
+		close(events)
+		return events
+	}
+
+	w.subscribers = append(w.subscribers, events)
+	return events
+}
+
+// Run polls until ctx is done. It returns nil after a cancellation and an error when the watcher already ran.
+func (w *Watcher) Run(ctx context.Context) error {
+	w.mu.Lock()
+	if w.running || w.stopped {
+		w.mu.Unlock()
+		return errors.New("watcher already ran")
+	}
+	w.running = true
+	w.mu.Unlock()
+
+	defer w.stop()
+
+	ticker := time.NewTicker(w.opts.Interval)
+	defer ticker.Stop()
+
+	var lastCheck time.Time
+	for {
+		now := time.Now()
+		check := lastCheck.IsZero() || now.Sub(lastCheck) >= w.opts.ClosedInterval
+		if check {
+			lastCheck = now
+		}
+
+		w.poll(ctx, check)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// stop closes the channels of the subscribers
+func (w *Watcher) stop() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.stopped = true
+	for _, events := range w.subscribers {
+		close(events)
+	}
+	w.subscribers = nil
+}
+
+// poll sends one round of requests. check refreshes the quotes of open symbols in price mode and the session of
+// closed symbols.
+func (w *Watcher) poll(ctx context.Context, check bool) {
+	var requests []twelvedata.FetchRequest
+	var indexes []int // Index in w.states of each request
+
+	var closed []int
+	for i := range w.states {
+		state := &w.states[i]
+		switch {
+		case !state.known && state.failed && !check:
+			continue
+		case !state.known || (state.open && (!w.opts.Prices || check)):
+			requests = append(requests, twelvedata.FetchRequest{Quote: &state.req})
+		case state.open:
+			requests = append(requests, twelvedata.FetchRequest{Price: priceRequest(state.req)})
+		default:
+			closed = append(closed, i)
+			continue
+		}
+		indexes = append(indexes, i)
+	}
+
+	if check && len(closed) > 0 {
+		for _, i := range w.opened(closed) {
+			requests = append(requests, twelvedata.FetchRequest{Quote: &w.states[i].req})
+			indexes = append(indexes, i)
+		}
+	}
+
+	if len(requests) == 0 {
+		return
+	}
+
+	results := w.client.FetchMany(ctx, requests, twelvedata.FetchOptions{Budget: w.opts.Budget, BatchSize: w.opts.BatchSize})
+	for result := range results {
+		state := &w.states[indexes[result.Index]]
+		switch {
+		case result.Err != nil:
+			state.failed = !state.known
+			if ctx.Err() == nil && w.opts.OnError != nil {
+				w.opts.OnError(result.Request.Symbol(), result.Err)
+			}
+		case result.Quote != nil:
+			state.micCode, state.name = result.Quote.MicCode, result.Quote.Exchange
+			w.update(ctx, state, result.Quote.Close, result.Quote.Volume, result.Quote.IsMarketOpen, result.Quote)
+		case result.Price != nil:
+			w.update(ctx, state, result.Price.Price, state.volume, state.open, nil)
+		}
+	}
+}
+
+// opened returns the closed symbols whose exchange opened according to the market state. Symbols whose exchange
+// isn't listed, and all of them when the market state fails, are returned too, so a quote tells.
+func (w *Watcher) opened(closed []int) []int {
+	states, err := w.client.GetMarketState(twelvedata.MarketStateRequest{})
+	if err != nil {
+		if w.opts.OnError != nil {
+			w.opts.OnError("", errors.Wrap(err, "Error checking market state"))
+		}
+		return closed
+	}
+
+	var opened []int
+	for _, i := range closed {
+		open, listed := false, false
+		for _, market := range states {
+			if (w.states[i].micCode != "" && strings.EqualFold(market.Code, w.states[i].micCode)) ||
+				(w.states[i].micCode == "" && strings.EqualFold(market.Name, w.states[i].name)) {
+				open, listed = market.IsMarketOpen, true
+				break
+			}
+		}
+
+		if open || !listed {
+			opened = append(opened, i)
+		}
+	}
+
+	return opened
+}
+
+// update records an observation of a symbol and publishes an event when it changed
+func (w *Watcher) update(ctx context.Context, state *symbolState, price, volume twelvedata.TDFloat, open bool, quote *twelvedata.Quote) {
+	var changes Change
+	if !state.known {
+		changes = ChangePrice | ChangeVolume | ChangeSession
+	} else {
+		if !sameFloat(price, state.price) {
+			changes |= ChangePrice
+		}
+		if !sameFloat(volume, state.volume) {
+			changes |= ChangeVolume
+		}
+		if open != state.open {
+			changes |= ChangeSession
+		}
+	}
+
+	state.known, state.price, state.volume, state.open = true, price, volume, open
+	if changes == 0 {
+		return
+	}
+
+	event := Event{
+		Symbol:       *state.req.Symbol,
+		Time:         time.Now(),
+		Price:        price,
+		Volume:       volume,
+		IsMarketOpen: open,
+		Changes:      changes,
+		Quote:        quote,
+	}
+
+	w.mu.Lock()
+	subscribers := w.subscribers
+	w.mu.Unlock()
+
+	for _, events := range subscribers {
+		select {
+		case events <- event:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func sameFloat(a, b twelvedata.TDFloat) bool {
+	return a.Valid == b.Valid && a.Float64 == b.Float64
+}
+
+// priceRequest returns the price request for the listing of a quote request
+func priceRequest(req twelvedata.QuoteRequest) *twelvedata.PriceRequest {
+	return &twelvedata.PriceRequest{
+		Symbol:           req.Symbol,
+		FIGI:             req.FIGI,
+		ISIN:             req.ISIN,
+		CUSIP:            req.CUSIP,
+		Exchange:         req.Exchange,
+		MicCode:          req.MicCode,
+		Country:          req.Country,
+		Type:             req.Type,
+		PrePost:          req.PrePost,
+		DP:               req.DP,
+		PreserveDecimals: req.PreserveDecimals,
+	}
+}
@@ -0,0 +1,269 @@
+package watch_test
+
+import (
+	"context"
+	"iter"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jonnotjohn/twelvedata-go"
+	"github.com/jonnotjohn/twelvedata-go/watch"
+	"github.com/pkg/errors"
+)
+
+var errUnknown = errors.New("symbol not found")
+
+// outcome is the answer of a scripted poll for a symbol: a quote, a price or an error
+type outcome struct {
+	price  float64
+	volume float64
+	open   bool
+	err    error
+}
+
+// scriptedClient answers the polls of a watcher from a script and cancels the watcher when the script is over
+type scriptedClient struct {
+	polls   []map[string]outcome       // Answers of each FetchMany call by symbol
+	markets [][]twelvedata.MarketState // Answers of each GetMarketState call
+	cancel  context.CancelFunc
+	sent    [][]string // Requests of each FetchMany call, e.g. "quote AAPL"
+	checks  int        // GetMarketState calls
+}
+
+func (c *scriptedClient) FetchMany(ctx context.Context, requests []twelvedata.FetchRequest, opts twelvedata.FetchOptions) iter.Seq[twelvedata.FetchResult] {
+	var sent []string
+	for _, req := range requests {
+		if req.Price != nil {
+			sent = append(sent, "price "+req.Symbol())
+		} else {
+			sent = append(sent, "quote "+req.Symbol())
+		}
+	}
+	c.sent = append(c.sent, sent)
+
+	if len(c.sent) > len(c.polls) {
+		c.cancel()
+		return func(yield func(twelvedata.FetchResult) bool) {}
+	}
+	poll := c.polls[len(c.sent)-1]
+
+	return func(yield func(twelvedata.FetchResult) bool) {
+		for i, req := range requests {
+			answer := poll[req.Symbol()]
+			result := twelvedata.FetchResult{Index: i, Request: req, Err: answer.err}
+			switch {
+			case answer.err != nil:
+			case req.Price != nil:
+				result.Price = &twelvedata.Price{Price: twelvedata.NewTDFloat(answer.price)}
+			default:
+				result.Quote = &twelvedata.Quote{
+					Symbol:       req.Symbol(),
+					Exchange:     "NASDAQ",
+					MicCode:      "XNGS",
+					Close:        twelvedata.NewTDFloat(answer.price),
+					Volume:       twelvedata.NewTDFloat(answer.volume),
+					IsMarketOpen: answer.open,
+				}
+			}
+
+			if !yield(result) {
+				return
+			}
+		}
+	}
+}
+
+func (c *scriptedClient) GetMarketState(req twelvedata.MarketStateRequest) ([]twelvedata.MarketState, error) {
+	c.checks++
+	if c.checks > len(c.markets) {
+		c.cancel()
+		return nil, nil
+	}
+
+	if markets := c.markets[c.checks-1]; markets != nil {
+		return markets, nil
+	}
+	return nil, errors.New("market state unavailable")
+}
+
+// observed is an event without the fields that depend on the time of the test
+type observed struct {
+	Symbol  string
+	Price   float64
+	Volume  float64
+	Open    bool
+	Changes watch.Change
+	Quote   bool // Whether the event comes from a quote
+}
+
+func TestWatcher(t *testing.T) {
+	all := watch.ChangePrice | watch.ChangeVolume | watch.ChangeSession
+	nasdaq := func(open bool) []twelvedata.MarketState {
+		return []twelvedata.MarketState{{Name: "NASDAQ", Code: "XNGS", IsMarketOpen: open}}
+	}
+
+	tests := []struct {
+		name    string
+		symbols []string
+		opts    watch.Options
+		polls   []map[string]outcome
+		markets [][]twelvedata.MarketState
+		events  []observed
+		sent    [][]string
+		errors  []string // Symbols reported to OnError
+	}{
+		{
+			name:    "price, volume and session changes",
+			symbols: []string{"AAPL", "MSFT"},
+			opts:    watch.Options{ClosedInterval: time.Hour},
+			polls: []map[string]outcome{
+				{"AAPL": {price: 100, volume: 10, open: true}, "MSFT": {price: 400, volume: 5, open: true}},
+				{"AAPL": {price: 100, volume: 10, open: true}, "MSFT": {price: 400, volume: 5, open: true}},
+				{"AAPL": {price: 101, volume: 10, open: true}, "MSFT": {price: 400, volume: 5, open: true}},
+				{"AAPL": {price: 101, volume: 12, open: true}, "MSFT": {price: 400, volume: 5, open: true}},
+				{"AAPL": {price: 101, volume: 12, open: false}, "MSFT": {price: 400, volume: 5, open: true}},
+			},
+			events: []observed{
+				{Symbol: "AAPL", Price: 100, Volume: 10, Open: true, Changes: all, Quote: true},
+				{Symbol: "MSFT", Price: 400, Volume: 5, Open: true, Changes: all, Quote: true},
+				{Symbol: "AAPL", Price: 101, Volume: 10, Open: true, Changes: watch.ChangePrice, Quote: true},
+				{Symbol: "AAPL", Price: 101, Volume: 12, Open: true, Changes: watch.ChangeVolume, Quote: true},
+				{Symbol: "AAPL", Price: 101, Volume: 12, Changes: watch.ChangeSession, Quote: true},
+			},
+			sent: [][]string{
+				{"quote AAPL", "quote MSFT"},
+				{"quote AAPL", "quote MSFT"},
+				{"quote AAPL", "quote MSFT"},
+				{"quote AAPL", "quote MSFT"},
+				{"quote AAPL", "quote MSFT"},
+				{"quote MSFT"}, // AAPL is paused while its market is closed
+			},
+		},
+		{
+			name:    "prices between quotes",
+			symbols: []string{"AAPL"},
+			opts:    watch.Options{ClosedInterval: time.Hour, Prices: true},
+			polls: []map[string]outcome{
+				{"AAPL": {price: 100, volume: 10, open: true}},
+				{"AAPL": {price: 100}},
+				{"AAPL": {price: 102}},
+			},
+			events: []observed{
+				{Symbol: "AAPL", Price: 100, Volume: 10, Open: true, Changes: all, Quote: true},
+				{Symbol: "AAPL", Price: 102, Volume: 10, Open: true, Changes: watch.ChangePrice},
+			},
+			sent: [][]string{{"quote AAPL"}, {"price AAPL"}, {"price AAPL"}, {"price AAPL"}},
+		},
+		{
+			name:    "poll errors",
+			symbols: []string{"NOPE", "AAPL"},
+			opts:    watch.Options{ClosedInterval: time.Hour},
+			polls: []map[string]outcome{
+				{"NOPE": {err: errUnknown}, "AAPL": {price: 100, volume: 10, open: true}},
+				{"AAPL": {err: errors.New("timeout")}},
+				{"AAPL": {price: 100, volume: 10, open: true}},
+			},
+			events: []observed{
+				{Symbol: "AAPL", Price: 100, Volume: 10, Open: true, Changes: all, Quote: true},
+			},
+			// A symbol failing from the start is only retried with the checks of closed markets, a symbol that
+			// was observed keeps being polled
+			sent:   [][]string{{"quote NOPE", "quote AAPL"}, {"quote AAPL"}, {"quote AAPL"}, {"quote AAPL"}},
+			errors: []string{"NOPE", "AAPL"},
+		},
+		{
+			name:    "closed market opening",
+			symbols: []string{"AAPL"},
+			opts:    watch.Options{ClosedInterval: time.Nanosecond},
+			polls: []map[string]outcome{
+				{"AAPL": {price: 100, volume: 10}},
+				{"AAPL": {price: 100, volume: 10}},
+				{"AAPL": {price: 100, volume: 10, open: true}},
+			},
+			// The closed symbol isn't polled while its market is closed, but is when the market state fails
+			markets: [][]twelvedata.MarketState{nasdaq(false), nil, nasdaq(true)},
+			events: []observed{
+				{Symbol: "AAPL", Price: 100, Volume: 10, Changes: all, Quote: true},
+				{Symbol: "AAPL", Price: 100, Volume: 10, Open: true, Changes: watch.ChangeSession, Quote: true},
+			},
+			sent:   [][]string{{"quote AAPL"}, {"quote AAPL"}, {"quote AAPL"}, {"quote AAPL"}},
+			errors: []string{""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+
+			client := &scriptedClient{polls: tt.polls, markets: tt.markets, cancel: cancel}
+
+			var reported []string
+			tt.opts.Interval = time.Millisecond
+			tt.opts.OnError = func(symbol string, err error) { reported = append(reported, symbol) }
+
+			requests := make([]twelvedata.QuoteRequest, len(tt.symbols))
+			for i, symbol := range tt.symbols {
+				requests[i] = twelvedata.QuoteRequest{Symbol: &symbol}
+			}
+
+			watcher := watch.New(client, requests, tt.opts)
+			events := watcher.Subscribe()
+			if err := watcher.Run(ctx); err != nil {
+				t.Fatal(err)
+			}
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				t.Fatal("the watcher didn't finish the script")
+			}
+
+			var got []observed
+			for event := range events {
+				got = append(got, observed{
+					Symbol:  event.Symbol,
+					Price:   event.Price.Float64,
+					Volume:  event.Volume.Float64,
+					Open:    event.IsMarketOpen,
+					Changes: event.Changes,
+					Quote:   event.Quote != nil,
+				})
+			}
+
+			expect(t, "events", got, tt.events)
+			expect(t, "requests", client.sent, tt.sent)
+			expect(t, "errors", reported, tt.errors)
+		})
+	}
+}
+
+func TestWatcherRunsOnce(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	watcher := watch.New(&scriptedClient{cancel: cancel}, nil, watch.Options{})
+	if err := watcher.Run(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := watcher.Run(ctx); err == nil {
+		t.Error("second Run() succeeded")
+	}
+
+	if _, open := <-watcher.Subscribe(); open {
+		t.Error("Subscribe() after Run() returned an open channel")
+	}
+}
+
+func TestEventTick(t *testing.T) {
+	now := time.Date(2024, 6, 14, 15, 30, 0, 0, time.UTC)
+	event := watch.Event{Time: now, Price: twelvedata.NewTDFloat(101.5), Volume: twelvedata.NewTDFloat(1000)}
+
+	expect(t, "Tick()", event.Tick(), twelvedata.Tick{Time: now, Price: 101.5})
+}
+
+func expect(t *testing.T, name string, got, want any) {
+	t.Helper()
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("%s = %#v, want %#v", name, got, want)
+	}
+}